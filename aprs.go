@@ -0,0 +1,161 @@
+package pskreporter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAPRSISAddr is the APRS-IS server rotation.
+	DefaultAPRSISAddr = "rotate.aprs2.net:14580"
+
+	// aprsToCall is the destination identifying packets from this package,
+	// from the experimental APZ range.
+	aprsToCall = "APZPSK"
+
+	// aprsTimeout bounds a whole APRS-IS session.
+	aprsTimeout = 30 * time.Second
+
+	// MaxAPRSStatusLen is the longest status text allowed in a status packet
+	// without a timestamp, in bytes.
+	MaxAPRSStatusLen = 62
+)
+
+var (
+	errAPRSUnverified    = errors.New("aprs-is login was not verified")
+	errAPRSStatusNewline = errors.New("aprs status must not contain line breaks")
+	errAPRSStatusTooLong = fmt.Errorf("aprs status must be no more than %d bytes", MaxAPRSStatusLen)
+)
+
+// APRSOption is used to customize an APRSReporter.
+type APRSOption func(*aprsOptions) error
+
+type aprsOptions struct {
+	addr     string
+	passcode int
+}
+
+// WithAPRSServer sets the APRS-IS server address, as host:port.
+func WithAPRSServer(addr string) APRSOption {
+	return func(o *aprsOptions) error {
+		o.addr = addr
+		return nil
+	}
+}
+
+// WithAPRSPasscode sets the passcode used to log in, for stations whose
+// passcode differs from the one computed from their callsign.
+func WithAPRSPasscode(passcode int) APRSOption {
+	return func(o *aprsOptions) error {
+		o.passcode = passcode
+		return nil
+	}
+}
+
+// APRSReporter posts reception results to APRS-IS as status packets.
+type APRSReporter struct {
+	callsign string
+	addr     string
+	passcode int
+}
+
+// NewAPRSReporter creates a reporter that sends status packets from callsign,
+// which may include an SSID such as "AG6K-7".
+func NewAPRSReporter(callsign string, opts ...APRSOption) (*APRSReporter, error) {
+	if callsign == "" {
+		return nil, errors.New("aprs callsign is required")
+	}
+	callsign = strings.ToUpper(callsign)
+
+	o := aprsOptions{
+		addr:     DefaultAPRSISAddr,
+		passcode: APRSPasscode(callsign),
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	return &APRSReporter{callsign: callsign, addr: o.addr, passcode: o.passcode}, nil
+}
+
+// APRSPasscode computes the APRS-IS passcode for a callsign. Any SSID is
+// ignored.
+func APRSPasscode(callsign string) int {
+	call := strings.ToUpper(strings.SplitN(callsign, "-", 2)[0])
+	hash := 0x73e2
+	for i := 0; i < len(call); i += 2 {
+		hash ^= int(call[i]) << 8
+		if i+1 < len(call) {
+			hash ^= int(call[i+1])
+		}
+	}
+	return hash & 0x7fff
+}
+
+// FormatAPRSStatus renders the evidence from IsBeingHeard as status text.
+func FormatAPRSStatus(ev Evidence) string {
+	switch len(ev.Receivers) {
+	case 0:
+		return "not heard"
+	case 1:
+		return fmt.Sprintf("heard by 1 station, best DX %.0f km", ev.MaxDistance)
+	}
+	return fmt.Sprintf("heard by %d stations, best DX %.0f km", len(ev.Receivers), ev.MaxDistance)
+}
+
+// SendStatus logs in to APRS-IS and sends the status text as a status packet.
+// The status must be a single line of no more than MaxAPRSStatusLen bytes.
+func (r *APRSReporter) SendStatus(ctx context.Context, status string) error {
+	if strings.ContainsAny(status, "\r\n") {
+		return errAPRSStatusNewline
+	}
+	if len(status) > MaxAPRSStatusLen {
+		return errAPRSStatusTooLong
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", r.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(aprsTimeout)
+	if dl, ok := ctx.Deadline(); ok && dl.Before(deadline) {
+		deadline = dl
+	}
+	conn.SetDeadline(deadline)
+
+	if _, err := fmt.Fprintf(conn, "user %s pass %d vers go-pskreporter 1.0\r\n", r.callsign, r.passcode); err != nil {
+		return err
+	}
+
+	// Wait for the login response, skipping the server banner.
+	s := bufio.NewScanner(conn)
+	for {
+		if !s.Scan() {
+			if err := s.Err(); err != nil {
+				return err
+			}
+			return errors.New("aprs-is server closed the connection during login")
+		}
+		line := s.Text()
+		if !strings.HasPrefix(line, "# logresp") {
+			continue
+		}
+		if !strings.Contains(line, " verified") {
+			return errAPRSUnverified
+		}
+		break
+	}
+
+	_, err = fmt.Fprintf(conn, "%s>%s,TCPIP*:>%s\r\n", r.callsign, aprsToCall, status)
+	return err
+}
@@ -0,0 +1,89 @@
+package pskreporter
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPRSPasscode(t *testing.T) {
+	require.Equal(t, 13023, APRSPasscode("N0CALL"))
+	require.Equal(t, 13023, APRSPasscode("n0call-9"))
+}
+
+func TestFormatAPRSStatus(t *testing.T) {
+	require.Equal(t, "not heard", FormatAPRSStatus(Evidence{}))
+	require.Equal(t, "heard by 1 station, best DX 2570 km", FormatAPRSStatus(Evidence{Receivers: testSpots[:1], MaxDistance: 2570.1}))
+	require.Equal(t, "heard by 2 stations, best DX 2570 km", FormatAPRSStatus(Evidence{Receivers: testSpots, MaxDistance: 2570.1}))
+}
+
+// fakeAPRSIS accepts one connection, replies to the login with logresp and
+// sends every line it receives on lines.
+func fakeAPRSIS(t *testing.T, logresp string) (string, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	lines := make(chan string, 10)
+	go func() {
+		defer close(lines)
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte("# aprsc 2.1.14\r\n"))
+		s := bufio.NewScanner(conn)
+		for s.Scan() {
+			lines <- s.Text()
+			if strings.HasPrefix(s.Text(), "user ") {
+				conn.Write([]byte(logresp + "\r\n"))
+			}
+		}
+	}()
+	return l.Addr().String(), lines
+}
+
+func TestAPRSReporter(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewAPRSReporter("")
+	require.Error(t, err)
+
+	t.Run("verified", func(t *testing.T) {
+		addr, lines := fakeAPRSIS(t, "# logresp N0CALL-7 verified, server T2TEST")
+		r, err := NewAPRSReporter("n0call-7", WithAPRSServer(addr))
+		require.NoError(t, err)
+		require.NoError(t, r.SendStatus(ctx, "heard by 2 stations, best DX 2570 km"))
+
+		var got []string
+		for l := range lines {
+			got = append(got, l)
+		}
+		require.Equal(t, []string{
+			"user N0CALL-7 pass 13023 vers go-pskreporter 1.0",
+			"N0CALL-7>APZPSK,TCPIP*:>heard by 2 stations, best DX 2570 km",
+		}, got)
+	})
+
+	t.Run("unverified", func(t *testing.T) {
+		addr, _ := fakeAPRSIS(t, "# logresp N0CALL unverified, server T2TEST")
+		r, err := NewAPRSReporter("N0CALL", WithAPRSServer(addr), WithAPRSPasscode(1))
+		require.NoError(t, err)
+		require.Equal(t, errAPRSUnverified, r.SendStatus(ctx, "hi"))
+	})
+
+	t.Run("bad status", func(t *testing.T) {
+		// Nothing listens on the address, so these must fail before dialing.
+		r, err := NewAPRSReporter("N0CALL", WithAPRSServer("127.0.0.1:0"))
+		require.NoError(t, err)
+		require.Equal(t, errAPRSStatusNewline, r.SendStatus(ctx, "hi\r\nN0CALL>APZPSK,TCPIP*:>injected"))
+		require.Equal(t, errAPRSStatusNewline, r.SendStatus(ctx, "hi\n"))
+		require.Equal(t, errAPRSStatusTooLong, r.SendStatus(ctx, strings.Repeat("x", MaxAPRSStatusLen+1)))
+	})
+}
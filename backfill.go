@@ -0,0 +1,274 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// DefaultBackfillInterval is the time between backfill queries, following PSK
+// Reporter's request that clients query no more than once every five minutes.
+const DefaultBackfillInterval = 5 * time.Minute
+
+// maxLookback is the furthest back PSK Reporter can be queried.
+const maxLookback = 24 * time.Hour
+
+var errBackfillNoCallsigns = errors.New("backfill needs at least one callsign")
+
+// BackfillStep is one callsign of a BackfillPlan.
+type BackfillStep struct {
+	Callsign string
+
+	// At is when the first query runs, relative to the start of the backfill.
+	At time.Duration
+
+	// FlowStartSeconds is how far back the query reaches if it runs on time.
+	// It grows with At so that every query covers the same window, up to the
+	// 24 hours PSK Reporter keeps.
+	FlowStartSeconds int
+
+	// Missed is how much of the start of the window is older than 24 hours by
+	// the time the query runs, and so can't be retrieved.
+	Missed time.Duration
+
+	// LastSequenceNumber, if not zero, resumes an earlier backfill of the
+	// callsign: only reports after it are retrieved. See BackfillPlan.Resume.
+	LastSequenceNumber uint64
+}
+
+// BackfillPlan is a schedule of queries that retrieves the reports of several
+// callsigns over the same window without exceeding a query rate.
+type BackfillPlan struct {
+	Lookback time.Duration
+	Interval time.Duration
+	Steps    []BackfillStep
+}
+
+// Duration returns how long the plan takes to run, not counting the time the
+// queries themselves take.
+func (p *BackfillPlan) Duration() time.Duration {
+	if len(p.Steps) == 0 {
+		return 0
+	}
+	return p.Steps[len(p.Steps)-1].At
+}
+
+// Resume sets the LastSequenceNumber of each step from last, which maps
+// callsigns to the BackfillProgress.LastSequenceNumber reported by an earlier
+// backfill, so that the plan picks up where that one stopped. Callsigns are
+// matched ignoring case; steps of callsigns not in last are left alone.
+func (p *BackfillPlan) Resume(last map[string]uint64) {
+	seqs := make(map[string]uint64, len(last))
+	for cs, n := range last {
+		seqs[strings.ToUpper(strings.TrimSpace(cs))] = n
+	}
+	for i := range p.Steps {
+		if n, ok := seqs[strings.ToUpper(p.Steps[i].Callsign)]; ok {
+			p.Steps[i].LastSequenceNumber = n
+		}
+	}
+}
+
+// PlanBackfill plans the queries to retrieve the reports of callsigns over the
+// last lookback, one query per callsign, interval apart. Callsigns are queried
+// in the order given, ignoring repeats and case. A zero interval uses
+// DefaultBackfillInterval.
+//
+// Because each query runs later than the one before, it has to reach further
+// back to cover the same window, but PSK Reporter only keeps 24 hours of
+// reports. Queries that would need to reach further back than that reach back
+// 24 hours instead, and the part of the window they can't cover is recorded in
+// the step's Missed; use a shorter lookback or split the callsigns between
+// several backfills to avoid it.
+func PlanBackfill(callsigns []string, lookback, interval time.Duration) (*BackfillPlan, error) {
+	if lookback <= 0 || lookback > maxLookback {
+		return nil, fmt.Errorf("backfill lookback %s must be positive and no more than 24 hours", lookback)
+	}
+	if interval < 0 {
+		return nil, fmt.Errorf("backfill interval %s must not be negative", interval)
+	}
+	if interval == 0 {
+		interval = DefaultBackfillInterval
+	}
+
+	p := &BackfillPlan{Lookback: lookback, Interval: interval}
+	seen := make(map[string]bool, len(callsigns))
+	for _, cs := range callsigns {
+		cs = strings.TrimSpace(cs)
+		key := strings.ToUpper(cs)
+		if cs == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		at := time.Duration(len(p.Steps)) * interval
+		reach, missed := backfillReach(lookback + at)
+		p.Steps = append(p.Steps, BackfillStep{
+			Callsign:         cs,
+			At:               at,
+			FlowStartSeconds: -int(math.Ceil(reach.Seconds())),
+			Missed:           missed,
+		})
+	}
+	if len(p.Steps) == 0 {
+		return nil, errBackfillNoCallsigns
+	}
+	return p, nil
+}
+
+// backfillReach caps how far back a query needs to reach at the 24 hours PSK
+// Reporter keeps, returning the capped reach and how much was cut off.
+func backfillReach(need time.Duration) (reach, missed time.Duration) {
+	if need > maxLookback {
+		return maxLookback, need - maxLookback
+	}
+	return need, 0
+}
+
+// BackfillProgress reports on a running backfill after each query.
+type BackfillProgress struct {
+	Step     int // 1-based
+	Steps    int
+	Callsign string
+	Spots    int // spots written for this callsign
+	Total    int // spots written so far
+
+	// Queries is how many queries have been made for this callsign. A query
+	// that comes back with as many reports as the report limit is followed by
+	// another, so there may be more than one.
+	Queries int
+
+	// LastSequenceNumber is the last sequence number PSK Reporter returned for
+	// this callsign. Pass it to BackfillPlan.Resume to continue from here.
+	LastSequenceNumber uint64
+
+	// Missed is how much of the start of the window was older than 24 hours
+	// when the callsign was queried, and so wasn't retrieved.
+	Missed time.Duration
+
+	Elapsed time.Duration
+
+	// ETA is the estimated time until the backfill is done.
+	ETA time.Duration
+}
+
+// Backfill runs plan, writing the spots of each callsign to sink. Each query
+// waits for its turn in the plan, and reaches back to the start of the window,
+// which is when Backfill was called less the plan's lookback, or 24 hours if
+// that is further; spots from before the window are dropped. A query that
+// comes back with as many reports as its report limit is followed, after the
+// plan's interval, by one for the reports after the last sequence number it
+// returned, and later steps are pushed back to keep to the interval. opts are
+// applied to every query, before the callsign, flowStartSeconds and
+// lastseqno. If progress isn't nil it is called after each query. The sink is
+// flushed after each query but not closed.
+func (c *Client) Backfill(ctx context.Context, plan *BackfillPlan, sink Sink, progress func(BackfillProgress), opts ...QueryOption) error {
+	start := timeNow()
+	from := start.Add(-plan.Lookback)
+	total := 0
+	var delay time.Duration // how far follow-up queries have pushed the plan back
+	for i, step := range plan.Steps {
+		seq := step.LastSequenceNumber
+		spots := 0
+		for queries := 1; ; queries++ {
+			if wait := start.Add(step.At + delay).Sub(timeNow()); wait > 0 {
+				t := time.NewTimer(wait)
+				select {
+				case <-ctx.Done():
+					t.Stop()
+					return ctx.Err()
+				case <-t.C:
+				}
+			}
+
+			// The previous queries may have run long, so work out how far
+			// back this one has to reach now rather than trusting the plan.
+			reach, missed := backfillReach(timeNow().Sub(from))
+			stepOpts := append(append([]QueryOption(nil), opts...),
+				WithCallsign(step.Callsign),
+				WithFlowStartSeconds(-int(math.Ceil(reach.Seconds()))),
+			)
+			if seq != 0 {
+				stepOpts = append(stepOpts, WithLastSequenceNumberUint64(seq))
+			}
+
+			var info streamInfo
+			ws := &windowSink{Sink: sink, from: from}
+			if _, err := c.queryToSink(ctx, ws, &info, stepOpts); err != nil {
+				return fmt.Errorf("backfill of %s: %w", step.Callsign, err)
+			}
+			spots += ws.n
+			total += ws.n
+
+			// Only follow up if the response was cut short and moved the
+			// sequence number on, so a server that ignores lastseqno can't
+			// keep the backfill going forever.
+			more := false
+			if n, err := (LastSequenceNumber{Value: info.lastSequenceNumber}).Uint64(); err == nil && n > seq {
+				seq = n
+				more = info.limit > 0 && info.reports >= info.limit
+			}
+			if more {
+				delay += plan.Interval
+			}
+
+			if progress != nil {
+				elapsed := timeNow().Sub(start)
+				eta := time.Duration(0)
+				if next := i + 1; more || next < len(plan.Steps) {
+					nextAt := step.At
+					if !more {
+						nextAt = plan.Steps[next].At
+					}
+					eta = plan.Duration() - nextAt
+					if wait := start.Add(nextAt + delay).Sub(timeNow()); wait > 0 {
+						eta += wait
+					}
+				}
+				progress(BackfillProgress{
+					Step:               i + 1,
+					Steps:              len(plan.Steps),
+					Callsign:           step.Callsign,
+					Spots:              spots,
+					Total:              total,
+					Queries:            queries,
+					LastSequenceNumber: seq,
+					Missed:             missed,
+					Elapsed:            elapsed,
+					ETA:                eta,
+				})
+			}
+			if !more {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// windowSink drops spots from before a backfill's window, counting the spots
+// it writes.
+type windowSink struct {
+	Sink
+	from time.Time
+	n    int
+}
+
+func (s *windowSink) Write(ctx context.Context, spots []Spot) error {
+	kept := spots[:0]
+	for _, spot := range spots {
+		if spot.Time.IsZero() || !spot.Time.Before(s.from) {
+			kept = append(kept, spot)
+		}
+	}
+	if len(kept) == 0 {
+		return nil
+	}
+	if err := s.Sink.Write(ctx, kept); err != nil {
+		return err
+	}
+	s.n += len(kept)
+	return nil
+}
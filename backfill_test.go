@@ -0,0 +1,167 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanBackfill(t *testing.T) {
+	p, err := PlanBackfill([]string{"AG6K", " w5cj", "ag6k", "", "N7HPX"}, time.Hour, 0)
+	require.NoError(t, err)
+	require.Equal(t, DefaultBackfillInterval, p.Interval)
+	require.Equal(t, []BackfillStep{
+		{Callsign: "AG6K", At: 0, FlowStartSeconds: -3600},
+		{Callsign: "w5cj", At: 5 * time.Minute, FlowStartSeconds: -3900},
+		{Callsign: "N7HPX", At: 10 * time.Minute, FlowStartSeconds: -4200},
+	}, p.Steps)
+	require.Equal(t, 10*time.Minute, p.Duration())
+
+	p, err = PlanBackfill([]string{"AG6K", "W5CJ"}, 24*time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, []BackfillStep{
+		{Callsign: "AG6K", At: 0, FlowStartSeconds: -86400},
+		{Callsign: "W5CJ", At: time.Minute, FlowStartSeconds: -86400, Missed: time.Minute},
+	}, p.Steps)
+
+	p.Resume(map[string]uint64{"w5cj": 42, "N7HPX": 7})
+	require.Zero(t, p.Steps[0].LastSequenceNumber)
+	require.Equal(t, uint64(42), p.Steps[1].LastSequenceNumber)
+
+	_, err = PlanBackfill(nil, time.Hour, 0)
+	require.Equal(t, errBackfillNoCallsigns, err)
+	_, err = PlanBackfill([]string{"AG6K"}, 25*time.Hour, 0)
+	require.Error(t, err)
+	_, err = PlanBackfill([]string{"AG6K"}, time.Hour, -time.Second)
+	require.Error(t, err)
+}
+
+func TestBackfill(t *testing.T) {
+	now := time.Unix(1599163380, 0).UTC()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var (
+		mu      sync.Mutex
+		queries [][2]string
+	)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		mu.Lock()
+		queries = append(queries, [2]string{q.Get("callsign"), q.Get("flowStartSeconds")})
+		mu.Unlock()
+
+		report := func(minutesAgo int) ReceptionReport {
+			return ReceptionReport{
+				SenderCallsign:   q.Get("callsign"),
+				ReceiverCallsign: "K1ABC",
+				Frequency:        "14074000",
+				FlowStartSeconds: strconv.FormatInt(now.Add(-time.Duration(minutesAgo)*time.Minute).Unix(), 10),
+			}
+		}
+		resp := Response{ReceptionReports: []ReceptionReport{report(10), report(90)}}
+		require.NoError(t, xml.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(svr.Close)
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	plan, err := PlanBackfill([]string{"AG6K", "W5CJ"}, time.Hour, 10*time.Millisecond)
+	require.NoError(t, err)
+
+	var (
+		sink     recordingSink
+		progress []BackfillProgress
+	)
+	err = c.Backfill(context.Background(), plan, &sink, func(p BackfillProgress) {
+		progress = append(progress, p)
+	}, WithMode("FT8"))
+	require.NoError(t, err)
+
+	require.Equal(t, [][2]string{{"AG6K", "-3600"}, {"W5CJ", "-3600"}}, queries)
+	require.Len(t, sink.spots, 2)
+	require.Equal(t, "AG6K", sink.spots[0].SenderCallsign)
+	require.Equal(t, "W5CJ", sink.spots[1].SenderCallsign)
+	require.Equal(t, 2, sink.flushes)
+
+	require.Len(t, progress, 2)
+	require.Equal(t, BackfillProgress{Step: 1, Steps: 2, Callsign: "AG6K", Spots: 1, Total: 1, Queries: 1, ETA: 10 * time.Millisecond}, progress[0])
+	require.Equal(t, BackfillProgress{Step: 2, Steps: 2, Callsign: "W5CJ", Spots: 1, Total: 2, Queries: 1}, progress[1])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	plan, err = PlanBackfill([]string{"AG6K", "W5CJ"}, time.Hour, time.Hour)
+	require.NoError(t, err)
+	err = c.Backfill(ctx, plan, &sink, nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestBackfillFollowUp(t *testing.T) {
+	now := time.Unix(1599163380, 0).UTC()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	// The server holds five reports for AG6K with sequence numbers 11 to 15,
+	// returning up to rptlimit of those after lastseqno.
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		q := req.URL.Query()
+		mu.Lock()
+		queries = append(queries, q.Get("callsign")+" "+q.Get("lastseqno"))
+		mu.Unlock()
+
+		limit, _ := strconv.Atoi(q.Get("rptlimit"))
+		after, _ := strconv.ParseUint(q.Get("lastseqno"), 10, 64)
+		resp := Response{LastSequenceNumber: LastSequenceNumber{Value: strconv.FormatUint(after, 10)}}
+		for seq := uint64(11); seq <= 15 && q.Get("callsign") == "AG6K"; seq++ {
+			if seq <= after || (limit > 0 && len(resp.ReceptionReports) == limit) {
+				continue
+			}
+			resp.ReceptionReports = append(resp.ReceptionReports, ReceptionReport{
+				SenderCallsign:   "AG6K",
+				Frequency:        "14074000",
+				FlowStartSeconds: strconv.FormatInt(now.Add(-time.Minute).Unix(), 10),
+			})
+			resp.LastSequenceNumber.Value = strconv.FormatUint(seq, 10)
+		}
+		require.NoError(t, xml.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(svr.Close)
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	plan, err := PlanBackfill([]string{"AG6K", "W5CJ"}, time.Hour, time.Millisecond)
+	require.NoError(t, err)
+	plan.Resume(map[string]uint64{"AG6K": 10})
+
+	var (
+		sink     recordingSink
+		progress []BackfillProgress
+	)
+	err = c.Backfill(context.Background(), plan, &sink, func(p BackfillProgress) {
+		progress = append(progress, p)
+	}, WithReportLimit(2))
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"AG6K 10", "AG6K 12", "AG6K 14", "W5CJ "}, queries)
+	require.Len(t, sink.spots, 5)
+	require.Len(t, progress, 4)
+	last := progress[2]
+	require.Equal(t, "AG6K", last.Callsign)
+	require.Equal(t, 3, last.Queries)
+	require.Equal(t, 5, last.Spots)
+	require.Equal(t, uint64(15), last.LastSequenceNumber)
+	require.Equal(t, 1, progress[3].Queries)
+}
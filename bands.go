@@ -0,0 +1,204 @@
+package pskreporter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Band is an amateur radio band, identified by its conventional name (e.g. "20m").
+type Band string
+
+// The amateur bands known to this package.
+const (
+	Band2200m Band = "2200m"
+	Band630m  Band = "630m"
+	Band160m  Band = "160m"
+	Band80m   Band = "80m"
+	Band60m   Band = "60m"
+	Band40m   Band = "40m"
+	Band30m   Band = "30m"
+	Band20m   Band = "20m"
+	Band17m   Band = "17m"
+	Band15m   Band = "15m"
+	Band12m   Band = "12m"
+	Band10m   Band = "10m"
+	Band6m    Band = "6m"
+	Band4m    Band = "4m"
+	Band2m    Band = "2m"
+	Band125cm Band = "1.25m"
+	Band70cm  Band = "70cm"
+	Band23cm  Band = "23cm"
+)
+
+type bandRange struct {
+	band         Band
+	lower, upper int64
+}
+
+// bandPlan holds the band edges in Hz, ordered by frequency. Where allocations
+// differ between countries, the widest commonly used range is listed.
+var bandPlan = []bandRange{
+	{Band2200m, 135700, 137800},
+	{Band630m, 472000, 479000},
+	{Band160m, 1800000, 2000000},
+	{Band80m, 3500000, 4000000},
+	{Band60m, 5250000, 5450000},
+	{Band40m, 7000000, 7300000},
+	{Band30m, 10100000, 10150000},
+	{Band20m, 14000000, 14350000},
+	{Band17m, 18068000, 18168000},
+	{Band15m, 21000000, 21450000},
+	{Band12m, 24890000, 24990000},
+	{Band10m, 28000000, 29700000},
+	{Band6m, 50000000, 54000000},
+	{Band4m, 70000000, 71000000},
+	{Band2m, 144000000, 148000000},
+	{Band125cm, 222000000, 225000000},
+	{Band70cm, 420000000, 450000000},
+	{Band23cm, 1240000000, 1300000000},
+}
+
+// Bands returns all known bands ordered by frequency.
+func Bands() []Band {
+	bands := make([]Band, 0, len(bandPlan))
+	for _, r := range bandPlan {
+		bands = append(bands, r.band)
+	}
+	return bands
+}
+
+// BandForFrequency returns the band containing the given frequency in Hz.
+func BandForFrequency(hz int64) (Band, bool) {
+	for _, r := range bandPlan {
+		if hz >= r.lower && hz <= r.upper {
+			return r.band, true
+		}
+	}
+	return "", false
+}
+
+// FrequencyRange returns the lower and upper edges of the band in Hz.
+func (b Band) FrequencyRange() (lower, upper int64, ok bool) {
+	for _, r := range bandPlan {
+		if r.band == b {
+			return r.lower, r.upper, true
+		}
+	}
+	return 0, 0, false
+}
+
+// IARURegion is an International Amateur Radio Union region, whose band plans
+// differ in places, particularly on 80m, 60m and 40m.
+type IARURegion int
+
+// The IARU regions. RegionAny uses the widest commonly used range of each
+// band, as BandForFrequency and FrequencyRange do.
+const (
+	RegionAny IARURegion = iota
+	Region1              // Europe, Africa, the Middle East and northern Asia
+	Region2              // the Americas
+	Region3              // the rest of Asia and the Pacific
+)
+
+// regionBandPlans override the edges of the bands that differ between regions.
+// Bands with a zero range aren't allocated in the region.
+var regionBandPlans = map[IARURegion]map[Band]bandRange{
+	Region1: {
+		Band160m:  {Band160m, 1810000, 2000000},
+		Band80m:   {Band80m, 3500000, 3800000},
+		Band60m:   {Band60m, 5351500, 5366500},
+		Band40m:   {Band40m, 7000000, 7200000},
+		Band6m:    {Band6m, 50000000, 52000000},
+		Band4m:    {Band4m, 70000000, 70500000},
+		Band2m:    {Band2m, 144000000, 146000000},
+		Band125cm: {},
+		Band70cm:  {Band70cm, 430000000, 440000000},
+	},
+	Region2: {
+		Band60m: {Band60m, 5330500, 5406400},
+		Band4m:  {},
+	},
+	Region3: {
+		Band80m:   {Band80m, 3500000, 3900000},
+		Band60m:   {Band60m, 5351500, 5366500},
+		Band40m:   {Band40m, 7000000, 7200000},
+		Band4m:    {},
+		Band125cm: {},
+		Band70cm:  {Band70cm, 430000000, 440000000},
+	},
+}
+
+// bandPlanFor returns the band edges in region, ordered by frequency.
+func bandPlanFor(region IARURegion) []bandRange {
+	overrides, ok := regionBandPlans[region]
+	if !ok {
+		return bandPlan
+	}
+	plan := make([]bandRange, 0, len(bandPlan))
+	for _, r := range bandPlan {
+		if o, ok := overrides[r.band]; ok {
+			if o.band == "" {
+				continue
+			}
+			r = o
+		}
+		plan = append(plan, r)
+	}
+	return plan
+}
+
+// BandForFrequencyIn returns the band containing the given frequency in Hz
+// under the band plan of region.
+func BandForFrequencyIn(region IARURegion, hz int64) (Band, bool) {
+	for _, r := range bandPlanFor(region) {
+		if hz >= r.lower && hz <= r.upper {
+			return r.band, true
+		}
+	}
+	return "", false
+}
+
+// FrequencyRangeIn returns the lower and upper edges of the band in Hz under
+// the band plan of region, or false if the band isn't allocated there.
+func (b Band) FrequencyRangeIn(region IARURegion) (lower, upper int64, ok bool) {
+	for _, r := range bandPlanFor(region) {
+		if r.band == b {
+			return r.lower, r.upper, true
+		}
+	}
+	return 0, 0, false
+}
+
+// FrequencyRange is a range of frequencies in Hz, including both edges.
+type FrequencyRange struct {
+	Lower, Upper int64
+}
+
+// String returns the range as the query API writes it, e.g.
+// "14000000-14350000".
+func (r FrequencyRange) String() string {
+	return fmt.Sprintf("%d-%d", r.Lower, r.Upper)
+}
+
+// Overlaps reports whether r and o have any frequency in common.
+func (r FrequencyRange) Overlaps(o FrequencyRange) bool {
+	return r.Lower <= o.Upper && o.Lower <= r.Upper
+}
+
+// parseFrequencyRange parses a range written by FrequencyRange.String.
+func parseFrequencyRange(s string) (FrequencyRange, bool) {
+	lower, upper, ok := strings.Cut(s, "-")
+	if !ok {
+		return FrequencyRange{}, false
+	}
+	var r FrequencyRange
+	var err error
+	if r.Lower, err = strconv.ParseInt(lower, 10, 64); err != nil {
+		return FrequencyRange{}, false
+	}
+	if r.Upper, err = strconv.ParseInt(upper, 10, 64); err != nil {
+		return FrequencyRange{}, false
+	}
+	return r, true
+}
@@ -0,0 +1,96 @@
+package pskreporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBandForFrequency(t *testing.T) {
+	tests := []struct {
+		hz   int64
+		band Band
+		ok   bool
+	}{
+		{14074000, Band20m, true},
+		{14000000, Band20m, true},
+		{14350000, Band20m, true},
+		{7074000, Band40m, true},
+		{432096799, Band70cm, true},
+		{1296600000, Band23cm, true},
+		{13999999, "", false},
+		{0, "", false},
+	}
+
+	for _, tt := range tests {
+		b, ok := BandForFrequency(tt.hz)
+		require.Equal(t, tt.ok, ok, tt.hz)
+		require.Equal(t, tt.band, b, tt.hz)
+	}
+}
+
+func TestBandFrequencyRange(t *testing.T) {
+	lower, upper, ok := Band20m.FrequencyRange()
+	require.True(t, ok)
+	require.Equal(t, int64(14000000), lower)
+	require.Equal(t, int64(14350000), upper)
+
+	_, _, ok = Band("11m").FrequencyRange()
+	require.False(t, ok)
+}
+
+func TestBands(t *testing.T) {
+	bands := Bands()
+	require.Len(t, bands, len(bandPlan))
+	require.Equal(t, Band2200m, bands[0])
+	require.Equal(t, Band23cm, bands[len(bands)-1])
+}
+
+func TestBandForFrequencyIn(t *testing.T) {
+	tests := []struct {
+		region IARURegion
+		hz     int64
+		band   Band
+		ok     bool
+	}{
+		{RegionAny, 7250000, Band40m, true},
+		{Region1, 7250000, "", false},
+		{Region2, 7250000, Band40m, true},
+		{Region3, 7150000, Band40m, true},
+		{Region1, 3850000, "", false},
+		{Region2, 3850000, Band80m, true},
+		{Region3, 3850000, Band80m, true},
+		{Region1, 5357000, Band60m, true},
+		{Region1, 5332000, "", false},
+		{Region2, 5332000, Band60m, true},
+		{Region1, 70100000, Band4m, true},
+		{Region2, 70100000, "", false},
+		{Region1, 14074000, Band20m, true},
+	}
+
+	for _, tt := range tests {
+		b, ok := BandForFrequencyIn(tt.region, tt.hz)
+		require.Equal(t, tt.ok, ok, "%d %d", tt.region, tt.hz)
+		require.Equal(t, tt.band, b, "%d %d", tt.region, tt.hz)
+	}
+}
+
+func TestBandFrequencyRangeIn(t *testing.T) {
+	lower, upper, ok := Band40m.FrequencyRangeIn(Region1)
+	require.True(t, ok)
+	require.Equal(t, int64(7000000), lower)
+	require.Equal(t, int64(7200000), upper)
+
+	lower, upper, ok = Band40m.FrequencyRangeIn(RegionAny)
+	require.True(t, ok)
+	wantLower, wantUpper, _ := Band40m.FrequencyRange()
+	require.Equal(t, wantLower, lower)
+	require.Equal(t, wantUpper, upper)
+
+	_, _, ok = Band4m.FrequencyRangeIn(Region2)
+	require.False(t, ok)
+	_, _, ok = Band125cm.FrequencyRangeIn(Region3)
+	require.False(t, ok)
+
+	require.Len(t, bandPlanFor(Region2), len(bandPlan)-1)
+}
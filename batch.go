@@ -0,0 +1,221 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var errBatchWriterClosed = errors.New("batch writer is closed")
+
+// Defaults for a BatchWriter.
+const (
+	DefaultBatchSize     = 500
+	DefaultFlushInterval = 5 * time.Second
+	DefaultQueueSize     = 10000
+)
+
+// BatchOption is used to configure a BatchWriter.
+type BatchOption func(*batchOptions) error
+
+type batchOptions struct {
+	size      int
+	interval  time.Duration
+	queueSize int
+}
+
+// WithBatchSize sets the number of spots that triggers a write to the store.
+func WithBatchSize(n int) BatchOption {
+	return func(o *batchOptions) error {
+		if n < 1 {
+			return errors.New("batch size must be positive")
+		}
+		o.size = n
+		return nil
+	}
+}
+
+// WithFlushInterval sets how often pending spots are written to the store
+// even if the batch isn't full.
+func WithFlushInterval(d time.Duration) BatchOption {
+	return func(o *batchOptions) error {
+		if d <= 0 {
+			return errors.New("flush interval must be positive")
+		}
+		o.interval = d
+		return nil
+	}
+}
+
+// WithQueueSize sets how many spots may be queued before Write blocks.
+func WithQueueSize(n int) BatchOption {
+	return func(o *batchOptions) error {
+		if n < 1 {
+			return errors.New("queue size must be positive")
+		}
+		o.queueSize = n
+		return nil
+	}
+}
+
+// BatchWriter is a Sink that groups spots from any number of concurrent
+// producers into batches before putting them into a Store. Batches are written
+// when they reach the batch size or the flush interval elapses. When the queue
+// is full, Write blocks until there is room or its context is done.
+type BatchWriter struct {
+	store    Store
+	size     int
+	interval time.Duration
+	queue    chan Spot
+	flushReq chan chan struct{}
+	done     chan struct{}
+
+	mu     sync.RWMutex // held for writing while closing the queue
+	closed bool
+
+	errMu sync.Mutex
+	err   error // first write error since the last Flush
+}
+
+// NewBatchWriter creates a BatchWriter that puts spots into store. Closing the
+// writer closes the store.
+func NewBatchWriter(store Store, opts ...BatchOption) (*BatchWriter, error) {
+	o := batchOptions{
+		size:      DefaultBatchSize,
+		interval:  DefaultFlushInterval,
+		queueSize: DefaultQueueSize,
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	b := &BatchWriter{
+		store:    store,
+		size:     o.size,
+		interval: o.interval,
+		queue:    make(chan Spot, o.queueSize),
+		flushReq: make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b, nil
+}
+
+// Write queues spots to be put into the store.
+func (b *BatchWriter) Write(ctx context.Context, spots []Spot) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return errBatchWriterClosed
+	}
+
+	for _, s := range spots {
+		select {
+		case b.queue <- s:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Flush puts every spot queued so far into the store and returns the first
+// error encountered writing to the store since the last Flush.
+func (b *BatchWriter) Flush() error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return errBatchWriterClosed
+	}
+	reply := make(chan struct{})
+	b.flushReq <- reply
+	b.mu.RUnlock()
+
+	<-reply
+	return b.takeErr()
+}
+
+// Close writes any queued spots, stops the writer and closes the store.
+func (b *BatchWriter) Close() error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return errBatchWriterClosed
+	}
+	b.closed = true
+	close(b.queue)
+	b.mu.Unlock()
+
+	<-b.done
+	err := b.takeErr()
+	if cerr := b.store.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (b *BatchWriter) run() {
+	defer close(b.done)
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	pending := make([]Spot, 0, b.size)
+	flush := func() {
+		if len(pending) == 0 {
+			return
+		}
+		if err := b.store.Put(context.Background(), pending); err != nil {
+			b.errMu.Lock()
+			if b.err == nil {
+				b.err = err
+			}
+			b.errMu.Unlock()
+		}
+		pending = make([]Spot, 0, b.size)
+	}
+
+	for {
+		select {
+		case s, ok := <-b.queue:
+			if !ok {
+				flush()
+				return
+			}
+			pending = append(pending, s)
+			if len(pending) >= b.size {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case reply := <-b.flushReq:
+			// Anything Written before Flush was called is already in the
+			// queue's buffer.
+		drain:
+			for {
+				select {
+				case s, ok := <-b.queue:
+					if !ok {
+						break drain
+					}
+					pending = append(pending, s)
+				default:
+					break drain
+				}
+			}
+			flush()
+			close(reply)
+		}
+	}
+}
+
+func (b *BatchWriter) takeErr() error {
+	b.errMu.Lock()
+	defer b.errMu.Unlock()
+	err := b.err
+	b.err = nil
+	return err
+}
@@ -0,0 +1,114 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// blockingStore is a MemoryStore whose Put waits on release and can be made to
+// fail.
+type blockingStore struct {
+	*MemoryStore
+	release chan struct{}
+	err     error
+}
+
+func (s *blockingStore) Put(ctx context.Context, spots []Spot) error {
+	<-s.release
+	if s.err != nil {
+		return s.err
+	}
+	return s.MemoryStore.Put(ctx, spots)
+}
+
+func storedCount(t *testing.T, s Store) int {
+	spots, err := s.QueryByTimeRange(context.Background(), time.Time{}, time.Time{})
+	require.NoError(t, err)
+	return len(spots)
+}
+
+func TestBatchWriter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("concurrent producers", func(t *testing.T) {
+		store := NewMemoryStore()
+		b, err := NewBatchWriter(store, WithBatchSize(7), WithQueueSize(3))
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					if err := b.Write(ctx, testSpots); err != nil {
+						t.Error(err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		require.NoError(t, b.Flush())
+		require.Equal(t, 10*50*len(testSpots), storedCount(t, store))
+		require.NoError(t, b.Close())
+		require.Equal(t, errBatchWriterClosed, b.Write(ctx, testSpots))
+		require.Equal(t, errBatchWriterClosed, b.Flush())
+	})
+
+	t.Run("flush interval", func(t *testing.T) {
+		store := NewMemoryStore()
+		b, err := NewBatchWriter(store, WithFlushInterval(time.Millisecond))
+		require.NoError(t, err)
+		defer b.Close()
+
+		require.NoError(t, b.Write(ctx, testSpots))
+		require.Eventually(t, func() bool {
+			return storedCount(t, store) == len(testSpots)
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("backpressure", func(t *testing.T) {
+		store := &blockingStore{MemoryStore: NewMemoryStore(), release: make(chan struct{})}
+		b, err := NewBatchWriter(store, WithBatchSize(1), WithQueueSize(1))
+		require.NoError(t, err)
+
+		// One spot is held by the blocked Put and one fills the queue, so
+		// the third can't be accepted.
+		tctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		err = b.Write(tctx, []Spot{{}, {}, {}})
+		require.Equal(t, context.DeadlineExceeded, err)
+
+		close(store.release)
+		require.NoError(t, b.Flush())
+		require.Equal(t, 2, storedCount(t, store))
+		require.NoError(t, b.Close())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		store := &blockingStore{MemoryStore: NewMemoryStore(), release: make(chan struct{}), err: errors.New("disk full")}
+		close(store.release)
+		b, err := NewBatchWriter(store)
+		require.NoError(t, err)
+
+		require.NoError(t, b.Write(ctx, testSpots))
+		require.EqualError(t, b.Flush(), "disk full")
+		require.NoError(t, b.Flush())
+
+		require.NoError(t, b.Write(ctx, testSpots))
+		require.EqualError(t, b.Close(), "disk full")
+	})
+
+	t.Run("options", func(t *testing.T) {
+		for _, opt := range []BatchOption{WithBatchSize(0), WithFlushInterval(0), WithQueueSize(0)} {
+			_, err := NewBatchWriter(NewMemoryStore(), opt)
+			require.Error(t, err)
+		}
+	})
+}
@@ -0,0 +1,281 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BeaconAlert is raised when a beacon stops being reported, or is reported
+// again after having been silent.
+type BeaconAlert struct {
+	Callsign  string
+	Silent    bool      // true if the beacon went silent, false if it recovered
+	LastHeard time.Time // zero if the beacon has never been heard
+	At        time.Time // when the change was detected
+}
+
+// BeaconStats are the uptime and coverage statistics for a beacon.
+type BeaconStats struct {
+	Callsign    string
+	Silent      bool
+	FirstHeard  time.Time
+	LastHeard   time.Time
+	Spots       int
+	Receivers   int     // unique receivers
+	Bands       []Band  // ordered by frequency
+	MaxDistance float64 // kilometers
+	Uptime      float64 // fraction of the monitored time the beacon was not silent
+}
+
+type beaconState struct {
+	callsign    string
+	silent      bool
+	silentSince time.Time
+	silentTotal time.Duration
+	firstHeard  time.Time
+	lastHeard   time.Time
+	atLastHeard map[string]bool // keys of the spots observed at lastHeard
+	spots       int
+	receivers   map[string]bool
+	bands       map[Band]bool
+	maxDistance float64
+}
+
+// BeaconMonitor tracks a list of beacons and detects when any of them stops
+// being reported for longer than a threshold. It is safe for concurrent use.
+type BeaconMonitor struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	start     time.Time
+	beacons   map[string]*beaconState
+	order     []string
+}
+
+// NewBeaconMonitor creates a monitor for the given beacon callsigns. A beacon
+// is considered silent once it hasn't been reported for longer than threshold.
+// Uptime is measured from start.
+func NewBeaconMonitor(callsigns []string, threshold time.Duration, start time.Time) *BeaconMonitor {
+	m := &BeaconMonitor{
+		threshold: threshold,
+		start:     start,
+		beacons:   make(map[string]*beaconState, len(callsigns)),
+	}
+	for _, call := range callsigns {
+		key := strings.ToUpper(call)
+		if _, ok := m.beacons[key]; ok {
+			continue
+		}
+		m.beacons[key] = &beaconState{
+			callsign:    call,
+			atLastHeard: make(map[string]bool),
+			receivers:   make(map[string]bool),
+			bands:       make(map[Band]bool),
+		}
+		m.order = append(m.order, key)
+	}
+	return m
+}
+
+// Observe records the spots of any monitored beacons. Spots of other senders
+// are ignored. Spots already observed should not be passed in again, as they
+// would be counted twice.
+func (m *BeaconMonitor) Observe(spots []Spot) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i := range spots {
+		s := &spots[i]
+		b, ok := m.beacons[strings.ToUpper(s.SenderCallsign)]
+		if !ok {
+			continue
+		}
+
+		b.spots++
+		if b.firstHeard.IsZero() || s.Time.Before(b.firstHeard) {
+			b.firstHeard = s.Time
+		}
+		switch {
+		case s.Time.After(b.lastHeard):
+			b.lastHeard = s.Time
+			b.atLastHeard = map[string]bool{beaconSpotKey(s): true}
+		case s.Time.Equal(b.lastHeard):
+			b.atLastHeard[beaconSpotKey(s)] = true
+		}
+		if s.ReceiverCallsign != "" {
+			b.receivers[strings.ToUpper(s.ReceiverCallsign)] = true
+		}
+		if band, ok := spotBand(s); ok {
+			b.bands[band] = true
+		}
+		if d, ok := spotDistance(s); ok && d > b.maxDistance {
+			b.maxDistance = d
+		}
+	}
+}
+
+// Check determines which beacons have gone silent or recovered as of now, and
+// returns an alert for each change since the previous check.
+func (m *BeaconMonitor) Check(now time.Time) []BeaconAlert {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var alerts []BeaconAlert
+	for _, key := range m.order {
+		b := m.beacons[key]
+
+		heard := b.lastHeard
+		if heard.Before(m.start) {
+			heard = m.start
+		}
+		silent := now.Sub(heard) > m.threshold
+
+		switch {
+		case silent && !b.silent:
+			b.silent = true
+			b.silentSince = heard.Add(m.threshold)
+		case !silent && b.silent:
+			b.silent = false
+			if heard.After(b.silentSince) {
+				b.silentTotal += heard.Sub(b.silentSince)
+			}
+		default:
+			continue
+		}
+
+		alerts = append(alerts, BeaconAlert{
+			Callsign:  b.callsign,
+			Silent:    silent,
+			LastHeard: b.lastHeard,
+			At:        now,
+		})
+	}
+	return alerts
+}
+
+// Stats returns the statistics for each monitored beacon as of now, in the
+// order the beacons were given to NewBeaconMonitor.
+func (m *BeaconMonitor) Stats(now time.Time) []BeaconStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]BeaconStats, 0, len(m.order))
+	for _, key := range m.order {
+		b := m.beacons[key]
+
+		st := BeaconStats{
+			Callsign:    b.callsign,
+			Silent:      b.silent,
+			FirstHeard:  b.firstHeard,
+			LastHeard:   b.lastHeard,
+			Spots:       b.spots,
+			Receivers:   len(b.receivers),
+			MaxDistance: b.maxDistance,
+		}
+		for _, band := range Bands() {
+			if b.bands[band] {
+				st.Bands = append(st.Bands, band)
+			}
+		}
+
+		if total := now.Sub(m.start); total > 0 {
+			silent := b.silentTotal
+			if b.silent && now.After(b.silentSince) {
+				silent += now.Sub(b.silentSince)
+			}
+			st.Uptime = 1 - float64(silent)/float64(total)
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+// beaconSpotKey identifies a spot among those of a beacon at the same time.
+func beaconSpotKey(s *Spot) string {
+	return strings.ToUpper(s.ReceiverCallsign) + "/" + strconv.FormatInt(s.Frequency, 10)
+}
+
+// Poll queries the reception reports of every monitored beacon over the
+// monitor's threshold, and observes the beacon's spots that are newer than the
+// last report seen for it, or as new but from another receiver or frequency.
+func (m *BeaconMonitor) Poll(ctx context.Context, c *Client) error {
+	m.mu.Lock()
+	type target struct {
+		callsign  string
+		lastHeard time.Time
+		seen      map[string]bool
+	}
+	targets := make([]target, 0, len(m.order))
+	for _, key := range m.order {
+		b := m.beacons[key]
+		seen := make(map[string]bool, len(b.atLastHeard))
+		for k := range b.atLastHeard {
+			seen[k] = true
+		}
+		targets = append(targets, target{callsign: b.callsign, lastHeard: b.lastHeard, seen: seen})
+	}
+	m.mu.Unlock()
+
+	window := m.threshold
+	if window > 24*time.Hour {
+		window = 24 * time.Hour
+	}
+
+	for _, t := range targets {
+		spots, err := c.QuerySpotsContext(ctx,
+			WithSenderCallsign(t.callsign),
+			WithFlowStartSeconds(-int(window/time.Second)),
+			WithRROnly(1),
+		)
+		if err != nil {
+			return err
+		}
+
+		fresh := spots[:0]
+		for _, s := range spots {
+			if !strings.EqualFold(s.SenderCallsign, t.callsign) || s.Time.Before(t.lastHeard) {
+				continue
+			}
+			if s.Time.Equal(t.lastHeard) && t.seen[beaconSpotKey(&s)] {
+				continue
+			}
+			fresh = append(fresh, s)
+		}
+		m.Observe(fresh)
+	}
+	return nil
+}
+
+// Run polls the beacons every interval until ctx is done, calling alert for
+// every change detected. Errors from polling are passed to onError, if not nil,
+// and do not stop the monitor. The interval must be positive.
+func (m *BeaconMonitor) Run(ctx context.Context, c *Client, interval time.Duration, alert func(BeaconAlert), onError func(error)) error {
+	if interval <= 0 {
+		return errors.New("beacon poll interval must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Poll(ctx, c); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if onError != nil {
+				onError(err)
+			}
+		}
+		for _, a := range m.Check(time.Now()) {
+			alert(a)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
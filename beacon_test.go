@@ -0,0 +1,116 @@
+package pskreporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeaconMonitor(t *testing.T) {
+	start := time.Date(2020, 9, 3, 20, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return start.Add(time.Duration(m) * time.Minute) }
+
+	m := NewBeaconMonitor([]string{"K1ABC", "W1XYZ", "k1abc"}, 30*time.Minute, start)
+
+	m.Observe([]Spot{
+		{SenderCallsign: "k1abc", ReceiverCallsign: "N7HPX", Frequency: 14097100, Distance: 1000, Time: at(5)},
+		{SenderCallsign: "K1ABC", ReceiverCallsign: "W5CJ", Frequency: 7040100, Distance: 3000, Time: at(10)},
+		{SenderCallsign: "K1ABC", ReceiverCallsign: "n7hpx", Frequency: 14097100, Distance: 1000, Time: at(12)},
+		{SenderCallsign: "N0TME", ReceiverCallsign: "W5CJ", Time: at(10)},
+	})
+
+	require.Empty(t, m.Check(at(20)))
+
+	// W1XYZ has never been heard, so it goes silent 30 minutes after the
+	// start. K1ABC goes silent 30 minutes after it was last heard.
+	require.Equal(t, []BeaconAlert{{Callsign: "W1XYZ", Silent: true, At: at(31)}}, m.Check(at(31)))
+	require.Equal(t, []BeaconAlert{{Callsign: "K1ABC", Silent: true, LastHeard: at(12), At: at(43)}}, m.Check(at(43)))
+	require.Empty(t, m.Check(at(50)))
+
+	m.Observe([]Spot{{SenderCallsign: "K1ABC", ReceiverCallsign: "W5CJ", Frequency: 7040100, Time: at(52)}})
+	require.Equal(t, []BeaconAlert{{Callsign: "K1ABC", LastHeard: at(52), At: at(55)}}, m.Check(at(55)))
+
+	stats := m.Stats(at(100))
+	require.Len(t, stats, 2)
+	require.Equal(t, BeaconStats{
+		Callsign:    "K1ABC",
+		FirstHeard:  at(5),
+		LastHeard:   at(52),
+		Spots:       4,
+		Receivers:   2,
+		Bands:       []Band{Band40m, Band20m},
+		MaxDistance: 3000,
+		Uptime:      0.9, // silent from 42 to 52
+	}, stats[0])
+	require.Equal(t, "W1XYZ", stats[1].Callsign)
+	require.True(t, stats[1].Silent)
+	require.Zero(t, stats[1].Spots)
+	require.InDelta(t, 0.3, stats[1].Uptime, 0.000001)
+}
+
+func TestBeaconMonitorPoll(t *testing.T) {
+	var queried, windows []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		queried = append(queried, req.URL.Query().Get("senderCallsign"))
+		windows = append(windows, req.URL.Query().Get("flowStartSeconds"))
+		w.Write([]byte(testHeardXML))
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	m := NewBeaconMonitor([]string{"AG6K", "K1ABC"}, time.Hour, time.Unix(1599163000, 0))
+	require.NoError(t, m.Poll(context.Background(), c))
+	require.Equal(t, []string{"AG6K", "K1ABC"}, queried)
+	require.Equal(t, []string{"-3600", "-3600"}, windows)
+
+	// Polling again doesn't count the same spots twice.
+	require.NoError(t, m.Poll(context.Background(), c))
+
+	stats := m.Stats(time.Unix(1599164000, 0))
+	require.Equal(t, 4, stats[0].Spots)
+	require.Equal(t, 3, stats[0].Receivers)
+	require.Equal(t, 1, stats[1].Spots)
+
+	t.Run("same second", func(t *testing.T) {
+		// A new receiver reports the beacon in the same second as its last
+		// spot, along with a repeat of that spot.
+		body := strings.Replace(testHeardXML, "</receptionReports>", `<receptionReport receiverCallsign="K7XYZ" senderCallsign="AG6K" frequency="7075311" flowStartSeconds="1599163980" mode="FT8" />
+</receptionReports>`, 1)
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(body))
+		}))
+		defer svr.Close()
+		c, err := New(WithBaseURL(svr.URL))
+		require.NoError(t, err)
+
+		require.NoError(t, m.Poll(context.Background(), c))
+		stats := m.Stats(time.Unix(1599164000, 0))
+		require.Equal(t, 5, stats[0].Spots)
+		require.Equal(t, 4, stats[0].Receivers)
+
+		require.NoError(t, m.Poll(context.Background(), c))
+		require.Equal(t, 5, m.Stats(time.Unix(1599164000, 0))[0].Spots)
+	})
+
+	t.Run("run", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var alerts []BeaconAlert
+		err := m.Run(ctx, c, time.Hour, func(a BeaconAlert) {
+			alerts = append(alerts, a)
+			cancel()
+		}, nil)
+		require.Equal(t, context.Canceled, err)
+		require.Len(t, alerts, 2)
+		require.True(t, alerts[0].Silent)
+
+		err = m.Run(context.Background(), c, 0, func(BeaconAlert) {}, nil)
+		require.Error(t, err)
+	})
+}
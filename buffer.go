@@ -0,0 +1,101 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var errSpotBufferSize = badOption(errors.New("spot buffer size must be positive"))
+
+// SpotBuffer is a ring buffer holding the most recent spots written to it, so
+// that consumers joining late can be given some immediate context. It is a
+// Sink, and is safe for concurrent use.
+type SpotBuffer struct {
+	mu    sync.RWMutex
+	spots []Spot
+	next  int // index the next spot is written to
+	full  bool
+}
+
+// NewSpotBuffer creates a SpotBuffer that retains the last n spots, which
+// must be at least 1.
+func NewSpotBuffer(n int) (*SpotBuffer, error) {
+	if n < 1 {
+		return nil, errSpotBufferSize
+	}
+	return &SpotBuffer{spots: make([]Spot, n)}, nil
+}
+
+// Write adds spots to the buffer, overwriting the oldest once it is full.
+func (b *SpotBuffer) Write(ctx context.Context, spots []Spot) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Only the last len(b.spots) of a large write can be retained.
+	if len(spots) > len(b.spots) {
+		spots = spots[len(spots)-len(b.spots):]
+	}
+	for _, s := range spots {
+		b.spots[b.next] = s
+		b.next++
+		if b.next == len(b.spots) {
+			b.next = 0
+			b.full = true
+		}
+	}
+	return nil
+}
+
+// Flush does nothing, as spots are never buffered on their way in.
+func (b *SpotBuffer) Flush() error {
+	return nil
+}
+
+// Close does nothing. The buffered spots remain readable.
+func (b *SpotBuffer) Close() error {
+	return nil
+}
+
+// Len returns the number of spots in the buffer.
+func (b *SpotBuffer) Len() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.full {
+		return len(b.spots)
+	}
+	return b.next
+}
+
+// Snapshot returns a copy of the spots in the buffer, in the order they were
+// written.
+func (b *SpotBuffer) Snapshot() []Spot {
+	return b.Range(time.Time{}, time.Time{})
+}
+
+// Range returns a copy of the spots in the buffer heard in [start, end), in
+// the order they were written. A zero start or end leaves that side of the
+// range unbounded.
+func (b *SpotBuffer) Range(start, end time.Time) []Spot {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var result []Spot
+	add := func(spots []Spot) {
+		for _, s := range spots {
+			if !start.IsZero() && s.Time.Before(start) {
+				continue
+			}
+			if !end.IsZero() && !s.Time.Before(end) {
+				continue
+			}
+			result = append(result, s)
+		}
+	}
+	if b.full {
+		add(b.spots[b.next:])
+	}
+	add(b.spots[:b.next])
+	return result
+}
@@ -0,0 +1,76 @@
+package pskreporter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpotBuffer(t *testing.T) {
+	ctx := context.Background()
+	base := time.Unix(1599163200, 0).UTC()
+	spots := make([]Spot, 5)
+	for i := range spots {
+		spots[i] = Spot{SenderCallsign: "AG6K", Frequency: int64(i), Time: base.Add(time.Duration(i) * time.Minute)}
+	}
+
+	b, err := NewSpotBuffer(3)
+	require.NoError(t, err)
+	var _ Sink = b
+	require.Zero(t, b.Len())
+	require.Empty(t, b.Snapshot())
+
+	require.NoError(t, b.Write(ctx, spots[:2]))
+	require.Equal(t, 2, b.Len())
+	require.Equal(t, spots[:2], b.Snapshot())
+
+	require.NoError(t, b.Write(ctx, spots[2:4]))
+	require.Equal(t, 3, b.Len())
+	require.Equal(t, spots[1:4], b.Snapshot())
+
+	require.Equal(t, spots[2:3], b.Range(base.Add(2*time.Minute), base.Add(3*time.Minute)))
+	require.Equal(t, spots[2:4], b.Range(base.Add(2*time.Minute), time.Time{}))
+	require.Equal(t, spots[1:3], b.Range(time.Time{}, base.Add(3*time.Minute)))
+
+	// A write larger than the buffer keeps its tail.
+	require.NoError(t, b.Write(ctx, spots))
+	require.Equal(t, spots[2:], b.Snapshot())
+
+	// Snapshots are copies.
+	snap := b.Snapshot()
+	snap[0].SenderCallsign = "W5CJ"
+	require.Equal(t, "AG6K", b.Snapshot()[0].SenderCallsign)
+
+	require.NoError(t, b.Flush())
+	require.NoError(t, b.Close())
+	require.Equal(t, 3, b.Len())
+
+	_, err = NewSpotBuffer(0)
+	require.ErrorIs(t, err, ErrBadOption)
+}
+
+func TestSpotBufferConcurrent(t *testing.T) {
+	b, err := NewSpotBuffer(100)
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Write(context.Background(), testSpots)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				b.Snapshot()
+			}
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 100, b.Len())
+}
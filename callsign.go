@@ -0,0 +1,23 @@
+package pskreporter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// callsignPattern matches a callsign: an optional prefix designator such as
+// "VE3/", a base callsign made of a prefix, a digit and a suffix ending in a
+// letter, and an optional suffix designator such as "/P" or "/QRP".
+var callsignPattern = regexp.MustCompile(`^(?:[A-Z0-9]{1,4}/)?[A-Z0-9]{1,3}[0-9][A-Z0-9]{0,3}[A-Z](?:/[A-Z0-9]{1,4})?$`)
+
+// ValidateCallsign returns an error if s isn't shaped like an amateur
+// callsign, such as "AG6K", "2E0ABC", "EA8/AG6K" or "AG6K/P". Case is
+// ignored. It only checks the syntax, not whether the callsign has been
+// issued.
+func ValidateCallsign(s string) error {
+	if !callsignPattern.MatchString(strings.ToUpper(s)) {
+		return fmt.Errorf("invalid callsign %q", s)
+	}
+	return nil
+}
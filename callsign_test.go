@@ -0,0 +1,16 @@
+package pskreporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateCallsign(t *testing.T) {
+	for _, s := range []string{"AG6K", "w5cj", "2E0ABC", "K1A", "9A1AA", "3DA0RU", "EA8/AG6K", "AG6K/P", "VE3/AG6K/QRP", "W5CJ/7"} {
+		require.NoError(t, ValidateCallsign(s), s)
+	}
+	for _, s := range []string{"", "AG6", "AGK", "AG 6K", "AG6K/", "/AG6K", "AG6K/PORTABLE", "AG6K-1", "ABCD1EFGHI"} {
+		require.Error(t, ValidateCallsign(s), s)
+	}
+}
@@ -0,0 +1,166 @@
+package pskreporter
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Condition is a coarse rating of how well a band is supporting propagation.
+type Condition int
+
+// The band conditions, from worst to best.
+const (
+	ConditionDead Condition = iota
+	ConditionPoor
+	ConditionFair
+	ConditionGood
+	ConditionOpen
+)
+
+var conditionNames = []string{"dead", "poor", "fair", "good", "open"}
+
+// String returns the lower case name of the condition.
+func (c Condition) String() string {
+	if c < 0 || int(c) >= len(conditionNames) {
+		return "unknown"
+	}
+	return conditionNames[c]
+}
+
+// MarshalText implements encoding.TextMarshaler so conditions are encoded by
+// name in JSON.
+func (c Condition) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// BandScore is the result of scoring the recent activity on one band.
+type BandScore struct {
+	Band           Band      `json:"band"`
+	Spots          int       `json:"spots"`
+	SpotsPerMinute float64   `json:"spotsPerMinute"`
+	MedianDistance float64   `json:"medianDistance"` // kilometers
+	UniqueDXCC     int       `json:"uniqueDXCC"`
+	Score          float64   `json:"score"` // 0 to 1
+	Condition      Condition `json:"condition"`
+}
+
+// BandScorer rates band conditions from recent spots. Each of the spot rate,
+// the median distance, and the number of unique DXCC entities is scaled
+// against the level considered fully open, capped at 1, and the three are
+// averaged into a score between 0 and 1. The zero value uses sensible defaults.
+type BandScorer struct {
+	// Window is how far back from now spots are considered. Defaults to 15
+	// minutes.
+	Window time.Duration
+
+	// OpenRate is the number of spots per minute considered fully open.
+	// Defaults to 10.
+	OpenRate float64
+
+	// OpenDistance is the median distance in kilometers considered fully open.
+	// Defaults to 5000.
+	OpenDistance float64
+
+	// OpenDXCC is the number of unique DXCC entities considered fully open.
+	// Defaults to 10.
+	OpenDXCC int
+}
+
+// Score rates every band with at least one spot within the scorer's window
+// ending at now. The scores are ordered by band frequency.
+func (bs BandScorer) Score(spots []Spot, now time.Time) []BandScore {
+	window := bs.Window
+	if window <= 0 {
+		window = 15 * time.Minute
+	}
+	openRate := bs.OpenRate
+	if openRate <= 0 {
+		openRate = 10
+	}
+	openDistance := bs.OpenDistance
+	if openDistance <= 0 {
+		openDistance = 5000
+	}
+	openDXCC := bs.OpenDXCC
+	if openDXCC <= 0 {
+		openDXCC = 10
+	}
+
+	type acc struct {
+		spots     int
+		distances []float64
+		dxcc      map[string]bool
+	}
+	byBand := make(map[Band]*acc)
+	start := now.Add(-window)
+	for i := range spots {
+		s := &spots[i]
+		if s.Time.Before(start) || s.Time.After(now) {
+			continue
+		}
+		b, ok := spotBand(s)
+		if !ok {
+			continue
+		}
+
+		a, ok := byBand[b]
+		if !ok {
+			a = &acc{dxcc: make(map[string]bool)}
+			byBand[b] = a
+		}
+		a.spots++
+		if d, ok := spotDistance(s); ok {
+			a.distances = append(a.distances, d)
+		}
+		for _, dxcc := range []string{s.SenderDXCC, s.ReceiverDXCC} {
+			if dxcc != "" {
+				a.dxcc[strings.ToUpper(dxcc)] = true
+			}
+		}
+	}
+
+	var scores []BandScore
+	for _, b := range Bands() {
+		a, ok := byBand[b]
+		if !ok {
+			continue
+		}
+
+		sort.Float64s(a.distances)
+		score := BandScore{
+			Band:           b,
+			Spots:          a.spots,
+			SpotsPerMinute: float64(a.spots) / window.Minutes(),
+			MedianDistance: percentile(a.distances, 50),
+			UniqueDXCC:     len(a.dxcc),
+		}
+		score.Score = (capOne(score.SpotsPerMinute/openRate) +
+			capOne(score.MedianDistance/openDistance) +
+			capOne(float64(score.UniqueDXCC)/float64(openDXCC))) / 3
+		score.Condition = conditionForScore(score.Score)
+		scores = append(scores, score)
+	}
+	return scores
+}
+
+func conditionForScore(score float64) Condition {
+	switch {
+	case score >= 0.75:
+		return ConditionOpen
+	case score >= 0.5:
+		return ConditionGood
+	case score >= 0.25:
+		return ConditionFair
+	case score > 0.05:
+		return ConditionPoor
+	}
+	return ConditionDead
+}
+
+func capOne(f float64) float64 {
+	if f > 1 {
+		return 1
+	}
+	return f
+}
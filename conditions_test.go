@@ -0,0 +1,77 @@
+package pskreporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBandScorer(t *testing.T) {
+	now := time.Date(2020, 9, 3, 20, 0, 0, 0, time.UTC)
+
+	var spots []Spot
+	// 20m: 150 spots in the last 15 minutes from 12 countries, 6000km away.
+	for i := 0; i < 150; i++ {
+		spots = append(spots, Spot{
+			Band:         Band20m,
+			Distance:     6000,
+			ReceiverDXCC: fmt.Sprintf("Country %d", i%12),
+			Time:         now.Add(-time.Duration(i) * 5 * time.Second),
+		})
+	}
+	// 40m: 15 spots, 1000km away from 2 countries.
+	for i := 0; i < 15; i++ {
+		spots = append(spots, Spot{
+			Frequency:    7074000,
+			Distance:     1000,
+			ReceiverDXCC: fmt.Sprintf("Country %d", i%2),
+			Time:         now.Add(-time.Duration(i) * time.Minute),
+		})
+	}
+	// 10m: a single local spot, and an old spot outside of the window.
+	spots = append(spots,
+		Spot{Band: Band10m, Distance: 50, Time: now.Add(-time.Minute)},
+		Spot{Band: Band10m, Distance: 10000, Time: now.Add(-time.Hour)},
+	)
+
+	scores := BandScorer{}.Score(spots, now)
+	require.Len(t, scores, 3)
+
+	require.Equal(t, Band40m, scores[0].Band)
+	require.Equal(t, 15, scores[0].Spots)
+	require.Equal(t, 1.0, scores[0].SpotsPerMinute)
+	require.Equal(t, 1000.0, scores[0].MedianDistance)
+	require.Equal(t, 2, scores[0].UniqueDXCC)
+	require.InDelta(t, (0.1+0.2+0.2)/3, scores[0].Score, 0.000001)
+	require.Equal(t, ConditionPoor, scores[0].Condition)
+
+	require.Equal(t, Band20m, scores[1].Band)
+	require.Equal(t, 1.0, scores[1].Score)
+	require.Equal(t, ConditionOpen, scores[1].Condition)
+
+	require.Equal(t, Band10m, scores[2].Band)
+	require.Equal(t, 1, scores[2].Spots)
+	require.Equal(t, ConditionDead, scores[2].Condition)
+
+	t.Run("custom thresholds", func(t *testing.T) {
+		scores := BandScorer{Window: time.Hour, OpenRate: 0.25, OpenDistance: 1000, OpenDXCC: 2}.Score(spots, now)
+		require.Equal(t, Band40m, scores[0].Band)
+		require.Equal(t, ConditionOpen, scores[0].Condition)
+		require.Equal(t, Band10m, scores[2].Band)
+		require.Equal(t, 2, scores[2].Spots)
+		require.Equal(t, ConditionFair, scores[2].Condition)
+	})
+}
+
+func TestCondition(t *testing.T) {
+	require.Equal(t, "dead", ConditionDead.String())
+	require.Equal(t, "open", ConditionOpen.String())
+	require.Equal(t, "unknown", Condition(42).String())
+
+	b, err := json.Marshal(BandScore{Condition: ConditionFair})
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"condition":"fair"`)
+}
@@ -0,0 +1,71 @@
+package pskreporter
+
+import "time"
+
+// Config holds the Client settings that applications usually expose to their
+// users. It can be loaded from JSON or YAML. Zero values leave the defaults
+// in place.
+type Config struct {
+	BaseURL    string      `json:"baseURL,omitempty" yaml:"baseURL,omitempty"`
+	AppContact string      `json:"appContact,omitempty" yaml:"appContact,omitempty"`
+	Cache      CacheConfig `json:"cache,omitempty" yaml:"cache,omitempty"`
+
+	// MinQueryInterval is the least time to leave between queries sent to
+	// the server. Queries made sooner wait.
+	MinQueryInterval Duration `json:"minQueryInterval,omitempty" yaml:"minQueryInterval,omitempty"`
+}
+
+// CacheConfig configures the response cache.
+type CacheConfig struct {
+	// Dir turns on caching. The directory must already exist.
+	Dir string `json:"dir,omitempty" yaml:"dir,omitempty"`
+
+	// TTL is how long a response is served from the cache.
+	TTL Duration `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
+
+// Duration is a time.Duration that is written and read as a string such as
+// "5m30s" in configuration files.
+type Duration time.Duration
+
+// MarshalText implements encoding.TextMarshaler.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// Options returns the ClientOptions equivalent to cfg.
+func (cfg Config) Options() []ClientOption {
+	var opts []ClientOption
+	if cfg.BaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.AppContact != "" {
+		opts = append(opts, WithDefaultAppContact(cfg.AppContact))
+	}
+	if cfg.Cache.Dir != "" {
+		opts = append(opts, WithCacheDir(cfg.Cache.Dir))
+	}
+	if cfg.Cache.TTL != 0 {
+		opts = append(opts, WithCacheDuration(time.Duration(cfg.Cache.TTL)))
+	}
+	if cfg.MinQueryInterval != 0 {
+		opts = append(opts, WithMinQueryInterval(time.Duration(cfg.MinQueryInterval)))
+	}
+	return opts
+}
+
+// NewFromConfig instantiates a new Client from cfg. opts are applied after
+// cfg so they take precedence.
+func NewFromConfig(cfg Config, opts ...ClientOption) (*Client, error) {
+	return New(append(cfg.Options(), opts...)...)
+}
@@ -0,0 +1,50 @@
+package pskreporter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig(t *testing.T) {
+	var cfg Config
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"baseURL": "http://localhost:8080/query",
+		"appContact": "me@example.com",
+		"cache": {"dir": "/tmp", "ttl": "5m"},
+		"minQueryInterval": "5m"
+	}`), &cfg))
+	require.Equal(t, Config{
+		BaseURL:          "http://localhost:8080/query",
+		AppContact:       "me@example.com",
+		Cache:            CacheConfig{Dir: "/tmp", TTL: Duration(5 * time.Minute)},
+		MinQueryInterval: Duration(5 * time.Minute),
+	}, cfg)
+
+	b, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"baseURL": "http://localhost:8080/query",
+		"appContact": "me@example.com",
+		"cache": {"dir": "/tmp", "ttl": "5m0s"},
+		"minQueryInterval": "5m0s"
+	}`, string(b))
+
+	c, err := NewFromConfig(cfg, WithCacheDuration(time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, cfg.BaseURL, c.baseURL)
+	require.Equal(t, cfg.AppContact, c.appContact)
+	require.Equal(t, "/tmp", c.cacheDir)
+	require.Equal(t, time.Minute, c.cacheDuration)
+	require.Equal(t, 5*time.Minute, c.limiter.interval)
+
+	c, err = NewFromConfig(Config{})
+	require.NoError(t, err)
+	require.Equal(t, queryURL, c.baseURL)
+
+	require.Error(t, json.Unmarshal([]byte(`{"cache": {"ttl": "soon"}}`), &cfg))
+	_, err = NewFromConfig(Config{Cache: CacheConfig{TTL: Duration(-time.Second)}})
+	require.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package pskreporter
+
+import "strings"
+
+// Continent abbreviations, as used by DXCC.
+const (
+	ContinentAfrica       = "AF"
+	ContinentAntarctica   = "AN"
+	ContinentAsia         = "AS"
+	ContinentEurope       = "EU"
+	ContinentNorthAmerica = "NA"
+	ContinentOceania      = "OC"
+	ContinentSouthAmerica = "SA"
+)
+
+// dxccContinents maps the ADIF codes of the more commonly heard DXCC entities
+// to their continents.
+var dxccContinents = map[string]string{
+	// North America
+	"1": ContinentNorthAmerica, "6": ContinentNorthAmerica, "50": ContinentNorthAmerica,
+	"60": ContinentNorthAmerica, "62": ContinentNorthAmerica, "64": ContinentNorthAmerica,
+	"66": ContinentNorthAmerica, "70": ContinentNorthAmerica, "72": ContinentNorthAmerica,
+	"74": ContinentNorthAmerica, "76": ContinentNorthAmerica, "77": ContinentNorthAmerica,
+	"78": ContinentNorthAmerica, "79": ContinentNorthAmerica, "80": ContinentNorthAmerica,
+	"82": ContinentNorthAmerica, "84": ContinentNorthAmerica, "86": ContinentNorthAmerica,
+	"88": ContinentNorthAmerica, "94": ContinentNorthAmerica, "95": ContinentNorthAmerica,
+	"97": ContinentNorthAmerica, "98": ContinentNorthAmerica, "202": ContinentNorthAmerica,
+	"237": ContinentNorthAmerica, "249": ContinentNorthAmerica, "285": ContinentNorthAmerica,
+	"291": ContinentNorthAmerica, "308": ContinentNorthAmerica,
+
+	// South America
+	"63": ContinentSouthAmerica, "71": ContinentSouthAmerica, "90": ContinentSouthAmerica,
+	"91": ContinentSouthAmerica, "100": ContinentSouthAmerica, "104": ContinentSouthAmerica,
+	"108": ContinentSouthAmerica, "112": ContinentSouthAmerica, "116": ContinentSouthAmerica,
+	"120": ContinentSouthAmerica, "129": ContinentSouthAmerica, "132": ContinentSouthAmerica,
+	"136": ContinentSouthAmerica, "140": ContinentSouthAmerica, "141": ContinentSouthAmerica,
+	"144": ContinentSouthAmerica, "148": ContinentSouthAmerica, "517": ContinentSouthAmerica,
+	"520": ContinentSouthAmerica,
+
+	// Europe
+	"5": ContinentEurope, "7": ContinentEurope, "21": ContinentEurope, "27": ContinentEurope,
+	"40": ContinentEurope, "52": ContinentEurope, "54": ContinentEurope, "106": ContinentEurope,
+	"114": ContinentEurope, "118": ContinentEurope, "122": ContinentEurope, "126": ContinentEurope,
+	"145": ContinentEurope, "146": ContinentEurope, "149": ContinentEurope, "179": ContinentEurope,
+	"203": ContinentEurope, "206": ContinentEurope, "209": ContinentEurope, "212": ContinentEurope,
+	"214": ContinentEurope, "221": ContinentEurope, "222": ContinentEurope, "223": ContinentEurope,
+	"224": ContinentEurope, "225": ContinentEurope, "227": ContinentEurope, "230": ContinentEurope,
+	"233": ContinentEurope, "236": ContinentEurope, "239": ContinentEurope, "242": ContinentEurope,
+	"245": ContinentEurope, "248": ContinentEurope, "251": ContinentEurope, "254": ContinentEurope,
+	"257": ContinentEurope, "259": ContinentEurope, "260": ContinentEurope, "263": ContinentEurope,
+	"265": ContinentEurope, "266": ContinentEurope, "269": ContinentEurope, "272": ContinentEurope,
+	"275": ContinentEurope, "278": ContinentEurope, "279": ContinentEurope, "281": ContinentEurope,
+	"284": ContinentEurope, "287": ContinentEurope, "288": ContinentEurope, "294": ContinentEurope,
+	"295": ContinentEurope, "296": ContinentEurope, "497": ContinentEurope, "499": ContinentEurope,
+	"501": ContinentEurope, "502": ContinentEurope, "503": ContinentEurope, "504": ContinentEurope,
+	"514": ContinentEurope,
+
+	// Africa
+	"29": ContinentAfrica, "32": ContinentAfrica, "165": ContinentAfrica, "256": ContinentAfrica,
+	"400": ContinentAfrica, "424": ContinentAfrica, "430": ContinentAfrica, "436": ContinentAfrica,
+	"438": ContinentAfrica, "446": ContinentAfrica, "450": ContinentAfrica, "453": ContinentAfrica,
+	"456": ContinentAfrica, "462": ContinentAfrica, "464": ContinentAfrica, "474": ContinentAfrica,
+	"478": ContinentAfrica,
+
+	// Asia
+	"14": ContinentAsia, "15": ContinentAsia, "18": ContinentAsia, "75": ContinentAsia,
+	"130": ContinentAsia, "137": ContinentAsia, "215": ContinentAsia, "292": ContinentAsia,
+	"293": ContinentAsia, "299": ContinentAsia, "304": ContinentAsia, "305": ContinentAsia,
+	"315": ContinentAsia, "318": ContinentAsia, "321": ContinentAsia, "324": ContinentAsia,
+	"330": ContinentAsia, "336": ContinentAsia, "339": ContinentAsia, "342": ContinentAsia,
+	"348": ContinentAsia, "354": ContinentAsia, "363": ContinentAsia, "369": ContinentAsia,
+	"370": ContinentAsia, "372": ContinentAsia, "376": ContinentAsia, "378": ContinentAsia,
+	"381": ContinentAsia, "386": ContinentAsia, "387": ContinentAsia, "390": ContinentAsia,
+
+	// Oceania
+	"46": ContinentOceania, "103": ContinentOceania, "110": ContinentOceania, "150": ContinentOceania,
+	"162": ContinentOceania, "163": ContinentOceania, "170": ContinentOceania, "175": ContinentOceania,
+	"176": ContinentOceania, "327": ContinentOceania, "345": ContinentOceania, "375": ContinentOceania,
+
+	// Antarctica
+	"13": ContinentAntarctica,
+}
+
+// DXCCContinent returns the continent of the DXCC entity with the given ADIF
+// code, such as "291" for the United States. Only the more commonly heard
+// entities are known.
+func DXCCContinent(code string) (string, bool) {
+	c, ok := dxccContinents[strings.TrimSpace(code)]
+	return c, ok
+}
+
+// ContinentEnricher sets Spot.SenderContinent and Spot.ReceiverContinent from
+// the DXCC codes of the sender and receiver. Ends without a known DXCC code
+// are left untouched.
+func ContinentEnricher() Enricher {
+	return EnricherFunc(func(s *Spot) error {
+		if c, ok := DXCCContinent(s.SenderDXCCCode); ok {
+			s.SenderContinent = c
+		}
+		if c, ok := DXCCContinent(s.ReceiverDXCCCode); ok {
+			s.ReceiverContinent = c
+		}
+		return nil
+	})
+}
@@ -0,0 +1,39 @@
+package pskreporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDXCCContinent(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"291", ContinentNorthAmerica},
+		{"100", ContinentSouthAmerica},
+		{"230", ContinentEurope},
+		{" 462 ", ContinentAfrica},
+		{"339", ContinentAsia},
+		{"150", ContinentOceania},
+		{"13", ContinentAntarctica},
+	}
+	for _, tt := range tests {
+		got, ok := DXCCContinent(tt.code)
+		require.True(t, ok, tt.code)
+		require.Equal(t, tt.want, got, tt.code)
+	}
+
+	_, ok := DXCCContinent("")
+	require.False(t, ok)
+	_, ok = DXCCContinent("99999")
+	require.False(t, ok)
+}
+
+func TestContinentEnricher(t *testing.T) {
+	s := Spot{SenderDXCCCode: "291", ReceiverDXCCCode: "unknown"}
+	require.NoError(t, ContinentEnricher().Enrich(&s))
+	require.Equal(t, ContinentNorthAmerica, s.SenderContinent)
+	require.Empty(t, s.ReceiverContinent)
+}
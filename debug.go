@@ -0,0 +1,149 @@
+package pskreporter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// DefaultDebugBodyLimit is how many bytes of each response body
+// WithDebugWriter dumps unless WithDebugBodyLimit is used.
+const DefaultDebugBodyLimit = 4096
+
+// WithDebugWriter dumps every request the client sends to w, along with the
+// response's status, headers and the start of its body, for troubleshooting
+// queries. The dump includes the full URL, app contact and all. Bodies are
+// dumped after decompression, up to DefaultDebugBodyLimit bytes. Responses
+// answered from the cache aren't dumped. A nil w turns dumping off.
+func WithDebugWriter(w io.Writer) ClientOption {
+	return func(o *clientOptions) error {
+		if w == nil {
+			o.debug = nil
+			return nil
+		}
+		limit := DefaultDebugBodyLimit
+		if o.debug != nil {
+			limit = o.debug.limit
+		}
+		o.debug = &debugDumper{w: w, limit: limit}
+		return nil
+	}
+}
+
+// WithDebugBodyLimit sets how many bytes of each response body
+// WithDebugWriter dumps. Zero leaves bodies out, and a negative limit dumps
+// them whole. It must come after WithDebugWriter.
+func WithDebugBodyLimit(n int) ClientOption {
+	return func(o *clientOptions) error {
+		if o.debug == nil {
+			return badOption(errors.New("WithDebugBodyLimit needs WithDebugWriter"))
+		}
+		o.debug = &debugDumper{w: o.debug.w, limit: n}
+		return nil
+	}
+}
+
+// debugDumper writes request and response dumps. It is safe for concurrent
+// use, and each exchange is written in one piece.
+type debugDumper struct {
+	mu    sync.Mutex
+	w     io.Writer
+	limit int
+}
+
+// dump writes req, resp and the start of body, returning a reader that still
+// yields the whole body.
+func (d *debugDumper) dump(req *http.Request, resp *http.Response, body io.ReadCloser) io.ReadCloser {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "> %s %s\n", req.Method, req.URL)
+	writeHeaders(&buf, "> ", req.Header)
+	fmt.Fprintf(&buf, "< %s\n", resp.Status)
+	writeHeaders(&buf, "< ", resp.Header)
+
+	var prefix []byte
+	var readErr error
+	switch {
+	case d.limit < 0:
+		prefix, readErr = io.ReadAll(body)
+	case d.limit > 0:
+		prefix, readErr = io.ReadAll(io.LimitReader(body, int64(d.limit)+1))
+	}
+	if len(prefix) > 0 || readErr != nil {
+		buf.WriteString("<\n")
+		shown := prefix
+		if d.limit >= 0 && len(shown) > d.limit {
+			shown = shown[:d.limit]
+		}
+		buf.Write(shown)
+		if len(shown) < len(prefix) {
+			buf.WriteString("\n... (truncated)")
+		}
+		if readErr != nil {
+			fmt.Fprintf(&buf, "\n... (error reading body: %v)", readErr)
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("\n")
+
+	d.mu.Lock()
+	d.w.Write(buf.Bytes())
+	d.mu.Unlock()
+
+	if prefix == nil && readErr == nil {
+		return body
+	}
+	return &prefixedBody{Reader: io.MultiReader(bytes.NewReader(prefix), errReader{readErr}, body), body: body}
+}
+
+// dumpError writes req and the error it failed with.
+func (d *debugDumper) dumpError(req *http.Request, err error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "> %s %s\n", req.Method, req.URL)
+	writeHeaders(&buf, "> ", req.Header)
+	fmt.Fprintf(&buf, "! %v\n\n", err)
+
+	d.mu.Lock()
+	d.w.Write(buf.Bytes())
+	d.mu.Unlock()
+}
+
+func writeHeaders(buf *bytes.Buffer, marker string, h http.Header) {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		for _, v := range h[k] {
+			fmt.Fprintf(buf, "%s%s: %s\n", marker, k, v)
+		}
+	}
+}
+
+// prefixedBody is a body part of which has already been read.
+type prefixedBody struct {
+	io.Reader
+	body io.Closer
+	size int64 // the length of the whole body if known, or 0 or -1
+}
+
+func (b *prefixedBody) Close() error {
+	return b.body.Close()
+}
+
+// errReader returns err once the data before it has been read, or nothing if
+// err is nil.
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return 0, io.EOF
+}
@@ -0,0 +1,87 @@
+package pskreporter
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugWriter(t *testing.T) {
+	status := http.StatusOK
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		w.WriteHeader(status)
+		w.Write([]byte(`<receptionReports><lastSequenceNumber value="42"/></receptionReports>`))
+	}))
+	defer svr.Close()
+
+	var buf bytes.Buffer
+	c, err := New(WithBaseURL(svr.URL), WithDebugWriter(&buf))
+	require.NoError(t, err)
+
+	r, err := c.Query(WithCallsign("AG6K"))
+	require.NoError(t, err)
+	require.Equal(t, "42", r.LastSequenceNumber.Value)
+
+	dump := buf.String()
+	require.Contains(t, dump, "> GET "+svr.URL+"?callsign=AG6K\n")
+	require.Contains(t, dump, "> User-Agent: "+DefaultUserAgent+"\n")
+	require.Contains(t, dump, "< 200 OK\n")
+	require.Contains(t, dump, "< Content-Type: text/xml\n")
+	require.Contains(t, dump, `<lastSequenceNumber value="42"/>`)
+	require.NotContains(t, dump, "truncated")
+
+	t.Run("truncated", func(t *testing.T) {
+		buf.Reset()
+		d, err := c.With(WithDebugBodyLimit(10))
+		require.NoError(t, err)
+		r, err := d.Query()
+		require.NoError(t, err)
+		require.Equal(t, "42", r.LastSequenceNumber.Value)
+		require.Contains(t, buf.String(), "<\n<reception\n... (truncated)\n")
+	})
+
+	t.Run("no body", func(t *testing.T) {
+		buf.Reset()
+		d, err := c.With(WithDebugBodyLimit(0))
+		require.NoError(t, err)
+		_, err = d.Query()
+		require.NoError(t, err)
+		require.NotContains(t, buf.String(), "receptionReports")
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		buf.Reset()
+		status = http.StatusInternalServerError
+		defer func() { status = http.StatusOK }()
+		_, err := c.Query()
+		require.Error(t, err)
+		require.Contains(t, buf.String(), "< 500 Internal Server Error\n")
+		require.Contains(t, buf.String(), "<receptionReports>")
+	})
+
+	t.Run("request error", func(t *testing.T) {
+		buf.Reset()
+		d, err := c.With(WithHTTPClient(&doerError{}))
+		require.NoError(t, err)
+		_, err = d.Query()
+		require.Error(t, err)
+		require.True(t, strings.HasSuffix(buf.String(), "! error\n\n"))
+	})
+
+	t.Run("off", func(t *testing.T) {
+		buf.Reset()
+		d, err := c.With(WithDebugWriter(nil))
+		require.NoError(t, err)
+		_, err = d.Query()
+		require.NoError(t, err)
+		require.Empty(t, buf.String())
+
+		_, err = New(WithDebugBodyLimit(10))
+		require.Error(t, err)
+	})
+}
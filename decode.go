@@ -0,0 +1,645 @@
+package pskreporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// decodeResponse decodes a query response. Responses from PSK Reporter use a
+// small subset of XML, which is scanned directly from b: attribute values are
+// sliced out without building tokens, and values that repeat across elements,
+// like modes and DXCC names, are interned so each distinct value is allocated
+// once. Anything outside that subset is handed to encoding/xml, so the result
+// is always the same as xml.Unmarshal's, with errors wrapped in a DecodeError.
+func decodeResponse(b []byte) (*Response, error) {
+	return decodeLimited(b, decodeLimits{})
+}
+
+// decodeLimited is decodeResponse, keeping only what lim allows. The scanner
+// drops what lim doesn't keep as it goes, so it is never allocated.
+func decodeLimited(b []byte, lim decodeLimits) (*Response, error) {
+	s := responseScanner{b: b, intern: make(map[string]string), limits: lim}
+	if r, ok := s.scan(); ok {
+		return r, nil
+	}
+
+	var r Response
+	d := xml.NewDecoder(bytes.NewReader(b))
+	if err := d.Decode(&r); err != nil {
+		return nil, &DecodeError{Offset: d.InputOffset(), Err: err}
+	}
+	lim.apply(&r)
+	return &r, nil
+}
+
+// decodeLimits bound what is kept of a response while decoding it, so that
+// memory use stays bounded when the server sends more than was asked for.
+type decodeLimits struct {
+	keep              func(ReceptionReport) bool // nil keeps every report
+	maxReports        int                        // zero for no limit
+	noActiveReceivers bool
+
+	// reports, if not nil, is set to the number of reception reports in the
+	// response before any were dropped.
+	reports *int
+}
+
+// full reports whether n reports are as many as l keeps.
+func (l decodeLimits) full(n int) bool {
+	return l.maxReports > 0 && n >= l.maxReports
+}
+
+// apply applies l to a response that was decoded without it.
+func (l decodeLimits) apply(r *Response) {
+	if l.reports != nil {
+		*l.reports = len(r.ReceptionReports)
+	}
+	if l.keep != nil || l.maxReports > 0 {
+		reports := r.ReceptionReports[:0]
+		for _, rr := range r.ReceptionReports {
+			if l.full(len(reports)) {
+				break
+			}
+			if l.keep == nil || l.keep(rr) {
+				reports = append(reports, rr)
+			}
+		}
+		r.ReceptionReports = reports
+		if len(reports) == 0 {
+			r.ReceptionReports = nil
+		}
+	}
+	if l.noActiveReceivers {
+		r.ActiveReceivers = nil
+	}
+}
+
+// decodePartial decodes a response that ends part way through the document,
+// keeping every element that was complete before the end. It returns false if
+// b isn't a truncated response, including when it is malformed before the end.
+func decodePartial(b []byte) (*Response, bool) {
+	d := xml.NewDecoder(bytes.NewReader(b))
+	var r *Response
+	var text []byte
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return r.truncated(text, err)
+		}
+
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if r == nil {
+				if tok.Name.Local != "receptionReports" {
+					return nil, false
+				}
+				r = &Response{XMLName: tok.Name}
+				for _, a := range tok.Attr {
+					if a.Name.Local == "currentSeconds" {
+						r.CurrentSeconds = a.Value
+					}
+				}
+				continue
+			}
+			if err := r.decodeChild(d, tok); err != nil {
+				return r.truncated(text, err)
+			}
+		case xml.CharData:
+			if r != nil {
+				text = append(text, tok...)
+			}
+		case xml.EndElement:
+			// The root element was closed, so the response wasn't truncated.
+			return nil, false
+		}
+	}
+}
+
+// truncated finishes a partially decoded response, if err shows the document
+// ended early.
+func (r *Response) truncated(text []byte, err error) (*Response, bool) {
+	var se *xml.SyntaxError
+	if r == nil || !errors.As(err, &se) || se.Msg != "unexpected EOF" {
+		return nil, false
+	}
+	r.Text = string(text)
+	r.Partial = true
+	return r, true
+}
+
+// decodeChild decodes the child element start of the root into r.
+func (r *Response) decodeChild(d *xml.Decoder, start xml.StartElement) error {
+	switch start.Name.Local {
+	case "activeReceiver":
+		var v ActiveReceiver
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		r.ActiveReceivers = append(r.ActiveReceivers, v)
+	case "receptionReport":
+		var v ReceptionReport
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		r.ReceptionReports = append(r.ReceptionReports, v)
+	case "activeCallsign":
+		var v ActiveCallsign
+		if err := d.DecodeElement(&v, &start); err != nil {
+			return err
+		}
+		r.ActiveCallsigns = append(r.ActiveCallsigns, v)
+	case "senderSearch":
+		return d.DecodeElement(&r.SenderSearch, &start)
+	case "lastSequenceNumber":
+		return d.DecodeElement(&r.LastSequenceNumber, &start)
+	case "maxFlowStartSeconds":
+		return d.DecodeElement(&r.MaxFlowStartSeconds, &start)
+	default:
+		return d.Skip()
+	}
+	return nil
+}
+
+// internedAttrs are the attributes whose values are shared between many
+// elements.
+var internedAttrs = map[string]bool{
+	"mode":               true,
+	"DXCC":               true,
+	"DXCCcode":           true,
+	"receiverDXCC":       true,
+	"receiverDXCCCode":   true,
+	"region":             true,
+	"decoderSoftware":    true,
+	"antennaInformation": true,
+	"bands":              true,
+	"isSender":           true,
+	"senderCallsign":     true,
+	"senderLocator":      true,
+	"receiverCallsign":   true,
+	"receiverLocator":    true,
+	"flowStartSeconds":   true,
+	"callsign":           true,
+}
+
+var (
+	activeReceiverAttrs = map[string]func(*ActiveReceiver) *string{
+		"callsign":           func(r *ActiveReceiver) *string { return &r.Callsign },
+		"locator":            func(r *ActiveReceiver) *string { return &r.Locator },
+		"frequency":          func(r *ActiveReceiver) *string { return &r.Frequency },
+		"region":             func(r *ActiveReceiver) *string { return &r.Region },
+		"DXCC":               func(r *ActiveReceiver) *string { return &r.DXCC },
+		"decoderSoftware":    func(r *ActiveReceiver) *string { return &r.DecoderSoftware },
+		"antennaInformation": func(r *ActiveReceiver) *string { return &r.AntennaInformation },
+		"mode":               func(r *ActiveReceiver) *string { return &r.Mode },
+		"bands":              func(r *ActiveReceiver) *string { return &r.Bands },
+	}
+	receptionReportAttrs = map[string]func(*ReceptionReport) *string{
+		"receiverCallsign": func(r *ReceptionReport) *string { return &r.ReceiverCallsign },
+		"receiverLocator":  func(r *ReceptionReport) *string { return &r.ReceiverLocator },
+		"senderCallsign":   func(r *ReceptionReport) *string { return &r.SenderCallsign },
+		"senderLocator":    func(r *ReceptionReport) *string { return &r.SenderLocator },
+		"frequency":        func(r *ReceptionReport) *string { return &r.Frequency },
+		"flowStartSeconds": func(r *ReceptionReport) *string { return &r.FlowStartSeconds },
+		"mode":             func(r *ReceptionReport) *string { return &r.Mode },
+		"isSender":         func(r *ReceptionReport) *string { return &r.IsSender },
+		"receiverDXCC":     func(r *ReceptionReport) *string { return &r.ReceiverDXCC },
+		"receiverDXCCCode": func(r *ReceptionReport) *string { return &r.ReceiverDXCCCode },
+		"sNR":              func(r *ReceptionReport) *string { return &r.SNR },
+	}
+	activeCallsignAttrs = map[string]func(*ActiveCallsign) *string{
+		"callsign":  func(c *ActiveCallsign) *string { return &c.Callsign },
+		"reports":   func(c *ActiveCallsign) *string { return &c.Reports },
+		"DXCC":      func(c *ActiveCallsign) *string { return &c.DXCC },
+		"DXCCcode":  func(c *ActiveCallsign) *string { return &c.DXCCcode },
+		"frequency": func(c *ActiveCallsign) *string { return &c.Frequency },
+	}
+)
+
+type responseScanner struct {
+	b      []byte
+	i      int
+	intern map[string]string
+	limits decodeLimits
+}
+
+// scan decodes the response, returning false if it uses anything outside the
+// subset of XML the scanner understands.
+func (s *responseScanner) scan() (*Response, bool) {
+	if !s.prolog() {
+		return nil, false
+	}
+
+	name, ok := s.name()
+	if !ok || string(name) != "receptionReports" {
+		return nil, false
+	}
+	r := &Response{XMLName: xml.Name{Local: "receptionReports"}}
+	selfClosing, ok := s.attrs(func(attr []byte, val string) {
+		if string(attr) == "currentSeconds" {
+			r.CurrentSeconds = val
+		}
+	}, false)
+	if !ok {
+		return nil, false
+	}
+	if selfClosing {
+		return r, true
+	}
+
+	// Size the slices up front so they aren't grown one copy at a time.
+	n := countElements(s.b[s.i:], "receptionReport")
+	if s.limits.reports != nil {
+		*s.limits.reports = n
+	}
+	if n > 0 {
+		if s.limits.full(n) {
+			n = s.limits.maxReports
+		}
+		r.ReceptionReports = make([]ReceptionReport, 0, n)
+	}
+	if n := countElements(s.b[s.i:], "activeReceiver"); n > 0 && !s.limits.noActiveReceivers {
+		r.ActiveReceivers = make([]ActiveReceiver, 0, n)
+	}
+	if n := countElements(s.b[s.i:], "activeCallsign"); n > 0 {
+		r.ActiveCallsigns = make([]ActiveCallsign, 0, n)
+	}
+
+	var text []byte
+	for {
+		// Character data directly inside the root element.
+		start := s.i
+		for s.i < len(s.b) && s.b[s.i] != '<' {
+			s.i++
+		}
+		if s.i == len(s.b) {
+			return nil, false
+		}
+		if s.i > start {
+			chunk := s.b[start:s.i]
+			if bytes.ContainsAny(chunk, "&>") || !validChars(chunk) {
+				return nil, false
+			}
+			text = append(text, chunk...)
+		}
+		s.i++ // <
+
+		if s.i < len(s.b) && s.b[s.i] == '/' {
+			s.i++
+			name, ok := s.name()
+			if !ok || string(name) != "receptionReports" {
+				return nil, false
+			}
+			s.space()
+			if s.i >= len(s.b) || s.b[s.i] != '>' {
+				return nil, false
+			}
+			r.Text = string(text)
+			r.trimHints()
+			return r, true
+		}
+		if s.i < len(s.b) && s.b[s.i] == '!' {
+			if !s.comment() {
+				return nil, false
+			}
+			continue
+		}
+
+		if !s.child(r) {
+			return nil, false
+		}
+	}
+}
+
+// child decodes a self-closing child element of the root into r.
+func (s *responseScanner) child(r *Response) bool {
+	name, ok := s.name()
+	if !ok {
+		return false
+	}
+
+	var set func(attr []byte, val string)
+	var done func() // called once the element's attributes are set
+	switch string(name) {
+	case "activeReceiver":
+		if s.limits.noActiveReceivers {
+			set = func([]byte, string) {}
+			break
+		}
+		r.ActiveReceivers = append(r.ActiveReceivers, ActiveReceiver{})
+		ar := &r.ActiveReceivers[len(r.ActiveReceivers)-1]
+		set = func(attr []byte, val string) {
+			if f, ok := activeReceiverAttrs[string(attr)]; ok {
+				*f(ar) = val
+			}
+		}
+	case "receptionReport":
+		if s.limits.full(len(r.ReceptionReports)) {
+			set = func([]byte, string) {}
+			break
+		}
+		r.ReceptionReports = append(r.ReceptionReports, ReceptionReport{})
+		last := len(r.ReceptionReports) - 1
+		rr := &r.ReceptionReports[last]
+		set = func(attr []byte, val string) {
+			if f, ok := receptionReportAttrs[string(attr)]; ok {
+				*f(rr) = val
+			}
+		}
+		if s.limits.keep != nil {
+			done = func() {
+				if !s.limits.keep(*rr) {
+					*rr = ReceptionReport{}
+					r.ReceptionReports = r.ReceptionReports[:last]
+				}
+			}
+		}
+	case "activeCallsign":
+		r.ActiveCallsigns = append(r.ActiveCallsigns, ActiveCallsign{})
+		ac := &r.ActiveCallsigns[len(r.ActiveCallsigns)-1]
+		set = func(attr []byte, val string) {
+			if f, ok := activeCallsignAttrs[string(attr)]; ok {
+				*f(ac) = val
+			}
+		}
+	case "senderSearch":
+		set = func(attr []byte, val string) {
+			switch string(attr) {
+			case "callsign":
+				r.SenderSearch.Callsign = val
+			case "recentFlowStartSeconds":
+				r.SenderSearch.RecentFlowStartSeconds = val
+			}
+		}
+	case "lastSequenceNumber":
+		set = func(attr []byte, val string) {
+			if string(attr) == "value" {
+				r.LastSequenceNumber.Value = val
+			}
+		}
+	case "maxFlowStartSeconds":
+		set = func(attr []byte, val string) {
+			if string(attr) == "value" {
+				r.MaxFlowStartSeconds.Value = val
+			}
+		}
+	default:
+		set = func([]byte, string) {}
+	}
+
+	selfClosing, ok := s.attrs(set, true)
+	if ok && done != nil {
+		done()
+	}
+	return ok && selfClosing
+}
+
+// trimHints drops slices that were sized by countElements but never filled, so
+// they stay nil as they would be with encoding/xml.
+func (r *Response) trimHints() {
+	if len(r.ReceptionReports) == 0 {
+		r.ReceptionReports = nil
+	}
+	if len(r.ActiveReceivers) == 0 {
+		r.ActiveReceivers = nil
+	}
+	if len(r.ActiveCallsigns) == 0 {
+		r.ActiveCallsigns = nil
+	}
+}
+
+// countElements estimates how many name elements b holds by counting their
+// start tags. Tags inside comments are counted too, which is harmless as the
+// count is only a capacity hint.
+func countElements(b []byte, name string) int {
+	tag := []byte("<" + name)
+	n := 0
+	for {
+		i := bytes.Index(b, tag)
+		if i < 0 {
+			return n
+		}
+		b = b[i+len(tag):]
+		if len(b) > 0 {
+			switch b[0] {
+			case ' ', '\t', '\n', '/', '>':
+				n++
+			}
+		}
+	}
+}
+
+// prolog skips whitespace and an optional UTF-8 XML declaration, leaving s
+// just past the '<' of the root element.
+func (s *responseScanner) prolog() bool {
+	s.space()
+	if bytes.HasPrefix(s.b[s.i:], []byte("<?xml")) {
+		end := bytes.Index(s.b[s.i:], []byte("?>"))
+		if end < 0 {
+			return false
+		}
+		decl := s.b[s.i : s.i+end]
+		if enc := bytes.Index(decl, []byte("encoding=")); enc >= 0 {
+			v := strings.Trim(string(decl[enc+len("encoding="):]), `"' `)
+			if !strings.EqualFold(v, "utf-8") {
+				return false
+			}
+		}
+		s.i += end + 2
+		s.space()
+	}
+	if s.i >= len(s.b) || s.b[s.i] != '<' {
+		return false
+	}
+	s.i++
+	return true
+}
+
+// comment skips a comment, with s just past its '<'.
+func (s *responseScanner) comment() bool {
+	if !bytes.HasPrefix(s.b[s.i:], []byte("!--")) {
+		return false
+	}
+	end := bytes.Index(s.b[s.i+3:], []byte("-->"))
+	if end < 0 {
+		return false
+	}
+	s.i += 3 + end + 3
+	return true
+}
+
+func (s *responseScanner) space() {
+	for s.i < len(s.b) {
+		switch s.b[s.i] {
+		case ' ', '\t', '\n':
+			s.i++
+		default:
+			return
+		}
+	}
+}
+
+func (s *responseScanner) name() ([]byte, bool) {
+	start := s.i
+	for s.i < len(s.b) {
+		c := s.b[s.i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.' {
+			s.i++
+			continue
+		}
+		break
+	}
+	return s.b[start:s.i], s.i > start
+}
+
+// attrs reads the attributes of the current tag, passing each to set, and
+// reports whether the tag was self-closing.
+func (s *responseScanner) attrs(set func(attr []byte, val string), intern bool) (selfClosing, ok bool) {
+	for {
+		s.space()
+		if s.i >= len(s.b) {
+			return false, false
+		}
+		switch s.b[s.i] {
+		case '>':
+			s.i++
+			return false, true
+		case '/':
+			if s.i+1 < len(s.b) && s.b[s.i+1] == '>' {
+				s.i += 2
+				return true, true
+			}
+			return false, false
+		}
+
+		attr, ok := s.name()
+		if !ok {
+			return false, false
+		}
+		s.space()
+		if s.i >= len(s.b) || s.b[s.i] != '=' {
+			return false, false
+		}
+		s.i++
+		s.space()
+		if s.i >= len(s.b) || (s.b[s.i] != '"' && s.b[s.i] != '\'') {
+			return false, false
+		}
+		quote := s.b[s.i]
+		s.i++
+		end := bytes.IndexByte(s.b[s.i:], quote)
+		if end < 0 {
+			return false, false
+		}
+		raw := s.b[s.i : s.i+end]
+		s.i += end + 1
+
+		val, ok := s.value(raw, intern && internedAttrs[string(attr)])
+		if !ok {
+			return false, false
+		}
+		set(attr, val)
+	}
+}
+
+// value converts a raw attribute value to a string, expanding entities.
+func (s *responseScanner) value(raw []byte, intern bool) (string, bool) {
+	if bytes.IndexByte(raw, '<') >= 0 || !validChars(raw) {
+		return "", false
+	}
+	if bytes.IndexByte(raw, '&') >= 0 {
+		var ok bool
+		if raw, ok = unescape(raw); !ok {
+			return "", false
+		}
+	}
+	if !intern {
+		return string(raw), true
+	}
+	if v, ok := s.intern[string(raw)]; ok {
+		return v, true
+	}
+	v := string(raw)
+	s.intern[v] = v
+	return v, true
+}
+
+// unescape expands the predefined and numeric character references in raw.
+func unescape(raw []byte) ([]byte, bool) {
+	out := make([]byte, 0, len(raw))
+	for len(raw) > 0 {
+		amp := bytes.IndexByte(raw, '&')
+		if amp < 0 {
+			return append(out, raw...), true
+		}
+		out = append(out, raw[:amp]...)
+		raw = raw[amp+1:]
+
+		semi := bytes.IndexByte(raw, ';')
+		if semi < 0 {
+			return nil, false
+		}
+		ent := string(raw[:semi])
+		raw = raw[semi+1:]
+
+		switch ent {
+		case "amp":
+			out = append(out, '&')
+		case "lt":
+			out = append(out, '<')
+		case "gt":
+			out = append(out, '>')
+		case "quot":
+			out = append(out, '"')
+		case "apos":
+			out = append(out, '\'')
+		default:
+			if !strings.HasPrefix(ent, "#") {
+				return nil, false
+			}
+			base, digits := 10, ent[1:]
+			if strings.HasPrefix(digits, "x") {
+				base, digits = 16, digits[1:]
+			}
+			n, err := strconv.ParseUint(digits, base, 32)
+			if err != nil || !isXMLChar(rune(n)) {
+				return nil, false
+			}
+			out = utf8.AppendRune(out, rune(n))
+		}
+	}
+	return out, true
+}
+
+// validChars reports whether b is UTF-8 made only of characters that XML
+// allows literally and encoding/xml passes through unchanged, which excludes
+// carriage returns as they are normalized to newlines.
+func validChars(b []byte) bool {
+	for len(b) > 0 {
+		c := b[0]
+		if c < utf8.RuneSelf {
+			if c < 0x20 && c != '\t' && c != '\n' {
+				return false
+			}
+			b = b[1:]
+			continue
+		}
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && size == 1 || !isXMLChar(r) {
+			return false
+		}
+		b = b[size:]
+	}
+	return true
+}
+
+// isXMLChar reports whether r is in the XML character range.
+func isXMLChar(r rune) bool {
+	return r == 0x09 || r == 0x0A || r == 0x0D ||
+		r >= 0x20 && r <= 0xD7FF ||
+		r >= 0xE000 && r <= 0xFFFD ||
+		r >= 0x10000 && r <= 0x10FFFF
+}
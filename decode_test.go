@@ -0,0 +1,281 @@
+package pskreporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeResponse(t *testing.T) {
+	b, err := os.ReadFile("testdata/output.xml")
+	require.NoError(t, err)
+
+	var want Response
+	require.NoError(t, xml.Unmarshal(b, &want))
+
+	s := responseScanner{b: b, intern: make(map[string]string)}
+	got, ok := s.scan()
+	require.True(t, ok, "testdata should be handled by the scanner")
+	require.Equal(t, &want, got)
+	checkResponse(t, got)
+
+	// The slices are sized from a pre-scan, so they are never grown.
+	require.Equal(t, len(got.ReceptionReports), cap(got.ReceptionReports))
+	require.Equal(t, len(got.ActiveReceivers), cap(got.ActiveReceivers))
+	require.Equal(t, len(got.ActiveCallsigns), cap(got.ActiveCallsigns))
+}
+
+func TestDecodeResponseMatchesXML(t *testing.T) {
+	tests := []struct {
+		desc    string
+		doc     string
+		scanned bool // handled without falling back to encoding/xml
+	}{
+		{"empty root", `<receptionReports/>`, true},
+		{"no prolog", `<receptionReports currentSeconds="1"><receptionReport sNR="-3" /></receptionReports>`, true},
+		{"utf-8 prolog", `<?xml version="1.0" encoding="UTF-8"?><receptionReports currentSeconds='1'></receptionReports>`, true},
+		{"entities", `<receptionReports><activeReceiver antennaInformation="A &amp; B &lt;&gt; &quot;x&quot; &apos;y&apos; &#233;&#xE9;"/></receptionReports>`, true},
+		{"unicode", `<receptionReports><activeReceiver region="Île-de-France"/></receptionReports>`, true},
+		{"comment", `<receptionReports><!-- hi --><activeCallsign callsign="AG6K"/></receptionReports>`, true},
+		{"commented out element", `<receptionReports><!-- <receptionReport sNR="1"/> --><activeCallsign callsign="AG6K"/></receptionReports>`, true},
+		{"unknown element and attribute", `<receptionReports><other x="1"/><activeCallsign callsign="AG6K" extra="y"/></receptionReports>`, true},
+		{"trailing content", `<receptionReports/><junk>`, true},
+		{"nested element", `<receptionReports><activeCallsign callsign="AG6K">text</activeCallsign></receptionReports>`, false},
+		{"carriage return", "<receptionReports>\r\n<activeCallsign callsign=\"AG6K\"/></receptionReports>", false},
+		{"cdata", `<receptionReports><![CDATA[x]]></receptionReports>`, false},
+		{"namespace", `<receptionReports xmlns:a="urn:x" a:b="c"/>`, false},
+		{"latin-1", `<?xml version="1.0" encoding="ISO-8859-1"?><receptionReports/>`, false},
+		{"wrong root", `<other/>`, false},
+		{"unknown entity", `<receptionReports><activeReceiver region="&nbsp;"/></receptionReports>`, false},
+		{"control character", "<receptionReports><activeReceiver region=\"a\x01b\"/></receptionReports>", false},
+		{"invalid utf-8", "<receptionReports><activeReceiver region=\"\xff\"/></receptionReports>", false},
+		{"unterminated", `<receptionReports><activeReceiver region="x"`, false},
+		{"unclosed root", `<receptionReports>`, false},
+		{"empty", ``, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			s := responseScanner{b: []byte(tt.doc), intern: make(map[string]string)}
+			_, ok := s.scan()
+			require.Equal(t, tt.scanned, ok)
+
+			var want Response
+			wantErr := xml.Unmarshal([]byte(tt.doc), &want)
+			got, err := decodeResponse([]byte(tt.doc))
+			if wantErr != nil {
+				var de *DecodeError
+				require.True(t, errors.As(err, &de))
+				require.EqualError(t, de.Err, wantErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, &want, got)
+		})
+	}
+}
+
+func TestDecodeLimited(t *testing.T) {
+	b, err := os.ReadFile("testdata/output.xml")
+	require.NoError(t, err)
+	full, err := decodeResponse(b)
+	require.NoError(t, err)
+
+	keepK := func(rr ReceptionReport) bool { return strings.HasPrefix(rr.ReceiverCallsign, "K") }
+	for desc, lim := range map[string]decodeLimits{
+		"max reports":    {maxReports: 5},
+		"no active":      {noActiveReceivers: true},
+		"keep":           {keep: keepK},
+		"keep and max":   {keep: keepK, maxReports: 3},
+		"max over count": {maxReports: 1000},
+	} {
+		t.Run(desc, func(t *testing.T) {
+			got, err := decodeLimited(b, lim)
+			require.NoError(t, err)
+
+			// The scanner must agree with applying the limits afterwards, as
+			// is done for responses it can't scan.
+			want, err := decodeResponse(b)
+			require.NoError(t, err)
+			lim.apply(want)
+			require.Equal(t, want, got)
+
+			require.Equal(t, full.ActiveCallsigns, got.ActiveCallsigns)
+			require.Equal(t, full.LastSequenceNumber, got.LastSequenceNumber)
+			if lim.maxReports > 0 {
+				require.LessOrEqual(t, len(got.ReceptionReports), lim.maxReports)
+			}
+			if lim.noActiveReceivers {
+				require.Nil(t, got.ActiveReceivers)
+			} else {
+				require.Equal(t, full.ActiveReceivers, got.ActiveReceivers)
+			}
+		})
+	}
+
+	got, err := decodeLimited(b, decodeLimits{maxReports: 5})
+	require.NoError(t, err)
+	require.Equal(t, full.ReceptionReports[:5], got.ReceptionReports)
+	require.Equal(t, 5, cap(got.ReceptionReports))
+}
+
+func TestDecodeResponseInterning(t *testing.T) {
+	got, err := decodeResponse([]byte(`<receptionReports>
+		<receptionReport mode="FT8" frequency="14074000"/>
+		<receptionReport mode="FT8" frequency="14074001"/>
+	</receptionReports>`))
+	require.NoError(t, err)
+	require.Same(t, unsafeStringData(got.ReceptionReports[0].Mode), unsafeStringData(got.ReceptionReports[1].Mode))
+}
+
+func TestDecodeResponseAllocations(t *testing.T) {
+	if testing.Short() {
+		t.Skip("decodes the test response many times")
+	}
+	b, err := os.ReadFile("testdata/output.xml")
+	require.NoError(t, err)
+
+	fast := testing.AllocsPerRun(5, func() {
+		decodeResponse(b)
+	})
+	slow := testing.AllocsPerRun(5, func() {
+		var r Response
+		xml.Unmarshal(b, &r)
+	})
+	require.Less(t, fast*3, slow, "scanner allocations %.0f vs encoding/xml %.0f", fast, slow)
+}
+
+func unsafeStringData(s string) *byte {
+	return unsafe.StringData(s)
+}
+
+func TestDecodePartial(t *testing.T) {
+	b, err := os.ReadFile("testdata/output.xml")
+	require.NoError(t, err)
+	full, err := decodeResponse(b)
+	require.NoError(t, err)
+
+	for _, n := range []int{len(b) / 4, len(b) / 2, len(b) - 30} {
+		r, ok := decodePartial(b[:n])
+		require.True(t, ok)
+		require.True(t, r.Partial)
+		require.Equal(t, full.CurrentSeconds, r.CurrentSeconds)
+
+		// Whatever was decoded is a prefix of the full response.
+		require.Less(t, len(r.ActiveReceivers)+len(r.ReceptionReports)+len(r.ActiveCallsigns),
+			len(full.ActiveReceivers)+len(full.ReceptionReports)+len(full.ActiveCallsigns))
+		require.Equal(t, append([]ActiveReceiver(nil), full.ActiveReceivers[:len(r.ActiveReceivers)]...), r.ActiveReceivers)
+		require.Equal(t, append([]ReceptionReport(nil), full.ReceptionReports[:len(r.ReceptionReports)]...), r.ReceptionReports)
+		require.Equal(t, append([]ActiveCallsign(nil), full.ActiveCallsigns[:len(r.ActiveCallsigns)]...), r.ActiveCallsigns)
+	}
+
+	for _, doc := range []string{
+		string(b),                              // complete
+		`<receptionReports><<`,                 // malformed
+		`<other><receptionReport sNR="1"/>`,    // wrong root
+		`<?xml version="1.0" encoding="UTF-8"`, // no root
+	} {
+		_, ok := decodePartial([]byte(doc))
+		require.False(t, ok, doc)
+	}
+}
+
+func BenchmarkDecodeResponse(b *testing.B) {
+	benchmarkDecode(b, func(data []byte) {
+		if _, err := decodeResponse(data); err != nil {
+			b.Fatal(err)
+		}
+	})
+}
+
+func BenchmarkDecodeResponseXML(b *testing.B) {
+	benchmarkDecode(b, func(data []byte) {
+		var r Response
+		if err := xml.Unmarshal(data, &r); err != nil {
+			b.Fatal(err)
+		}
+	})
+}
+
+func BenchmarkResponseSpots(b *testing.B) {
+	data, err := os.ReadFile("testdata/output.xml")
+	require.NoError(b, err)
+	r, err := decodeResponse(data)
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Spots(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkStreamSpots(b *testing.B) {
+	benchmarkDecode(b, func(data []byte) {
+		_, err := streamSpots(bytes.NewReader(data), nil, decodeLimits{}, DefaultBatchSize, nil, func([]Spot) error { return nil })
+		if err != nil {
+			b.Fatal(err)
+		}
+	})
+}
+
+func benchmarkDecode(b *testing.B, decode func([]byte)) {
+	data, err := os.ReadFile("testdata/output.xml")
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.SetBytes(int64(len(data)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		decode(data)
+	}
+}
+
+// decodeBudgets are the allocations per run that TestDecodeBudgets allows
+// when processing testdata/output.xml, set about 10% above the baselines
+// measured with Go 1.27: decodeResponse 18348, Response.Spots 1 and
+// streamSpots 135593. Each entry prepares the function to measure.
+var decodeBudgets = map[string]struct {
+	allocs float64
+	setup  func(t *testing.T, b []byte) func()
+}{
+	"decodeResponse": {20000, func(t *testing.T, b []byte) func() {
+		return func() { decodeResponse(b) }
+	}},
+	"Response.Spots": {1, func(t *testing.T, b []byte) func() {
+		r, err := decodeResponse(b)
+		require.NoError(t, err)
+		return func() { r.Spots() }
+	}},
+	"streamSpots": {150000, func(t *testing.T, b []byte) func() {
+		return func() {
+			streamSpots(bytes.NewReader(b), nil, decodeLimits{}, DefaultBatchSize, nil, func([]Spot) error { return nil })
+		}
+	}},
+}
+
+// TestDecodeBudgets guards the polling hot path against allocation
+// regressions. Allocation counts vary between Go releases, so it only runs
+// when PSKREPORTER_DECODE_BUDGETS is set.
+func TestDecodeBudgets(t *testing.T) {
+	if os.Getenv("PSKREPORTER_DECODE_BUDGETS") == "" {
+		t.Skip("set PSKREPORTER_DECODE_BUDGETS to check allocation budgets")
+	}
+	b, err := os.ReadFile("testdata/output.xml")
+	require.NoError(t, err)
+
+	for name, budget := range decodeBudgets {
+		t.Run(name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(5, budget.setup(t, b))
+			require.LessOrEqual(t, allocs, budget.allocs, "allocations per run")
+		})
+	}
+}
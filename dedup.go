@@ -0,0 +1,207 @@
+package pskreporter
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeduperOption is used to configure a Deduper.
+type DeduperOption func(*dedupOptions) error
+
+type dedupOptions struct {
+	bloomCapacity int
+	bloomRate     float64
+}
+
+// WithBloomFilter makes the Deduper remember spots in Bloom filters instead of
+// exact sets, so its memory use is fixed no matter how many spots it sees.
+// Each filter is sized to hold capacity spots, the most expected within one
+// window, with a false positive rate of falsePositiveRate. As a lookup checks
+// the filters of two windows, the chance of a new spot being wrongly reported
+// as a duplicate is at most about twice falsePositiveRate while no more than
+// capacity spots are seen per window. Beyond that it grows quickly.
+func WithBloomFilter(capacity int, falsePositiveRate float64) DeduperOption {
+	return func(o *dedupOptions) error {
+		if capacity < 1 {
+			return errors.New("bloom filter capacity must be positive")
+		}
+		if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+			return errors.New("bloom filter false positive rate must be between 0 and 1")
+		}
+		o.bloomCapacity = capacity
+		o.bloomRate = falsePositiveRate
+		return nil
+	}
+}
+
+// Deduper detects spots that have already been seen by a long-running
+// watcher, using memory bounded by the spots of the last two windows rather
+// than everything ever seen. Spots are duplicates if they have the same time,
+// sender, receiver, frequency and mode. Windows are measured using the time of
+// the spots, and spots older than the previous window are always treated as
+// duplicates, so the window should be at least as long as the time span a
+// watcher queries. It is safe for concurrent use.
+type Deduper struct {
+	mu       sync.Mutex
+	window   time.Duration
+	newSet   func() dedupSet
+	cur      dedupSet
+	prev     dedupSet
+	curStart time.Time
+}
+
+// NewDeduper creates a Deduper that remembers spots for between one and two
+// windows.
+func NewDeduper(window time.Duration, opts ...DeduperOption) (*Deduper, error) {
+	if window <= 0 {
+		return nil, errors.New("dedup window must be positive")
+	}
+
+	var o dedupOptions
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	d := &Deduper{window: window}
+	d.newSet = func() dedupSet { return make(exactSet) }
+	if o.bloomCapacity > 0 {
+		d.newSet = func() dedupSet { return newBloomFilter(o.bloomCapacity, o.bloomRate) }
+	}
+	d.cur, d.prev = d.newSet(), d.newSet()
+	return d, nil
+}
+
+// Seen reports whether s has been seen before, and remembers it if it hasn't.
+func (d *Deduper) Seen(s Spot) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.seen(s)
+}
+
+// Filter returns the spots that haven't been seen before, remembering them.
+func (d *Deduper) Filter(spots []Spot) []Spot {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var fresh []Spot
+	for _, s := range spots {
+		if !d.seen(s) {
+			fresh = append(fresh, s)
+		}
+	}
+	return fresh
+}
+
+func (d *Deduper) seen(s Spot) bool {
+	switch {
+	case d.curStart.IsZero():
+		d.curStart = s.Time.Truncate(d.window)
+	case !s.Time.Before(d.curStart.Add(2 * d.window)):
+		// Both windows have passed.
+		d.cur, d.prev = d.newSet(), d.newSet()
+		d.curStart = s.Time.Truncate(d.window)
+	case !s.Time.Before(d.curStart.Add(d.window)):
+		d.cur, d.prev = d.newSet(), d.cur
+		d.curStart = d.curStart.Add(d.window)
+	case s.Time.Before(d.curStart.Add(-d.window)):
+		// Too old to tell, and most likely already seen.
+		return true
+	}
+
+	k := dedupKey(s)
+	if d.cur.has(k) || d.prev.has(k) {
+		return true
+	}
+	d.cur.add(k)
+	return false
+}
+
+// dedupKey identifies a spot using the same fields as Import.
+func dedupKey(s Spot) string {
+	b := make([]byte, 0, 64)
+	b = strconv.AppendInt(b, s.Time.Unix(), 10)
+	b = append(b, '|')
+	b = append(b, strings.ToUpper(s.SenderCallsign)...)
+	b = append(b, '|')
+	b = append(b, strings.ToUpper(s.ReceiverCallsign)...)
+	b = append(b, '|')
+	b = strconv.AppendInt(b, s.Frequency, 10)
+	b = append(b, '|')
+	b = append(b, strings.ToUpper(s.Mode)...)
+	return string(b)
+}
+
+type dedupSet interface {
+	has(key string) bool
+	add(key string)
+}
+
+type exactSet map[string]struct{}
+
+func (s exactSet) has(key string) bool {
+	_, ok := s[key]
+	return ok
+}
+
+func (s exactSet) add(key string) {
+	s[key] = struct{}{}
+}
+
+// bloomFilter is a fixed size Bloom filter using double hashing.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64 // number of bits
+	k    uint64 // number of hashes
+}
+
+// newBloomFilter sizes a filter to hold n keys with false positive rate p.
+func newBloomFilter(n int, p float64) *bloomFilter {
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	if m < 64 {
+		m = 64
+	}
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+func (f *bloomFilter) hashes(key string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h1 := h.Sum64()
+	h.Write([]byte{0})
+	h2 := h.Sum64() | 1
+	return h1, h2
+}
+
+func (f *bloomFilter) has(key string) bool {
+	h1, h2 := f.hashes(key)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *bloomFilter) add(key string) {
+	h1, h2 := f.hashes(key)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
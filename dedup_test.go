@@ -0,0 +1,80 @@
+package pskreporter
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeduper(t *testing.T) {
+	base := time.Unix(1599163200, 0).UTC()
+	spot := func(offset time.Duration, receiver string) Spot {
+		return Spot{SenderCallsign: "AG6K", ReceiverCallsign: receiver, Frequency: 14075000, Mode: "FT8", Time: base.Add(offset)}
+	}
+
+	for _, tt := range []struct {
+		desc string
+		opts []DeduperOption
+	}{
+		{"exact", nil},
+		{"bloom", []DeduperOption{WithBloomFilter(1000, 0.001)}},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			d, err := NewDeduper(time.Hour, tt.opts...)
+			require.NoError(t, err)
+
+			require.False(t, d.Seen(spot(0, "W5CJ")))
+			require.True(t, d.Seen(spot(0, "w5cj")))
+			require.False(t, d.Seen(spot(0, "N7HPX")))
+
+			// Still remembered from the previous window.
+			require.True(t, d.Seen(spot(0, "W5CJ")))
+			require.False(t, d.Seen(spot(90*time.Minute, "W5CJ")))
+			require.True(t, d.Seen(spot(0, "N7HPX")))
+
+			require.Equal(t, []Spot{spot(91*time.Minute, "K1ABC")}, d.Filter([]Spot{
+				spot(90*time.Minute, "W5CJ"),
+				spot(91*time.Minute, "K1ABC"),
+				spot(91*time.Minute, "K1ABC"),
+			}))
+
+			// Spots older than the previous window are treated as duplicates.
+			require.False(t, d.Seen(spot(150*time.Minute, "W5CJ")))
+			require.True(t, d.Seen(spot(10*time.Minute, "KE0NEW")))
+
+			// Jumping ahead forgets everything.
+			require.False(t, d.Seen(spot(10*time.Hour, "W5CJ")))
+			require.True(t, d.Seen(spot(10*time.Hour, "W5CJ")))
+		})
+	}
+
+	t.Run("options", func(t *testing.T) {
+		_, err := NewDeduper(0)
+		require.Error(t, err)
+		_, err = NewDeduper(time.Hour, WithBloomFilter(0, 0.01))
+		require.Error(t, err)
+		_, err = NewDeduper(time.Hour, WithBloomFilter(100, 1))
+		require.Error(t, err)
+	})
+}
+
+func TestBloomFilter(t *testing.T) {
+	const n = 10000
+	f := newBloomFilter(n, 0.01)
+	for i := 0; i < n; i++ {
+		f.add(fmt.Sprintf("in-%d", i))
+	}
+	for i := 0; i < n; i++ {
+		require.True(t, f.has(fmt.Sprintf("in-%d", i)))
+	}
+
+	falsePositives := 0
+	for i := 0; i < n; i++ {
+		if f.has(fmt.Sprintf("out-%d", i)) {
+			falsePositives++
+		}
+	}
+	require.Less(t, falsePositives, 2*n/100)
+}
@@ -0,0 +1,142 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Digest is a Notifier that collects notifications and forwards them to
+// another Notifier as a single summary, so recipients get one message per
+// period instead of one per alert.
+type Digest struct {
+	next  Notifier
+	title string
+
+	mu      sync.Mutex
+	pending []Notification
+}
+
+// NewDigest creates a Digest that sends its summaries to next with the given
+// title, such as "Daily PSKReporter digest".
+func NewDigest(next Notifier, title string) *Digest {
+	return &Digest{next: next, title: title}
+}
+
+// Notify queues n for the next summary.
+func (d *Digest) Notify(ctx context.Context, n Notification) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pending = append(d.pending, n)
+	return nil
+}
+
+// Send forwards the queued notifications as one summary and clears the queue.
+// Nothing is sent if the queue is empty. If sending fails the notifications
+// are kept for the next attempt.
+func (d *Digest) Send(ctx context.Context) error {
+	d.mu.Lock()
+	pending := d.pending
+	d.pending = nil
+	d.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := d.next.Notify(ctx, d.summarize(pending)); err != nil {
+		d.mu.Lock()
+		d.pending = append(pending, d.pending...)
+		d.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Run sends a summary every interval, such as time.Hour or 24*time.Hour,
+// until ctx is cancelled. Errors from sending are passed to onError, if not
+// nil, and don't stop the loop. The interval must be positive.
+func (d *Digest) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	if interval <= 0 {
+		return errors.New("digest interval must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if err := d.Send(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+}
+
+// summarize combines notifications into one, listing each alert followed by
+// statistics over all of their spots.
+func (d *Digest) summarize(pending []Notification) Notification {
+	var b strings.Builder
+	var spots []Spot
+	for _, n := range pending {
+		b.WriteString("- ")
+		if n.Title != "" {
+			b.WriteString(n.Title)
+			if n.Message != "" {
+				b.WriteString(": ")
+			}
+		}
+		b.WriteString(n.Message)
+		b.WriteString("\n")
+		spots = append(spots, n.Spots...)
+	}
+
+	alerts := "alerts"
+	if len(pending) == 1 {
+		alerts = "alert"
+	}
+	fmt.Fprintf(&b, "\n%d %s", len(pending), alerts)
+	if len(spots) > 0 {
+		b.WriteString(", " + digestStats(spots))
+	}
+
+	return Notification{Title: d.title, Message: b.String(), Spots: spots}
+}
+
+// digestStats describes spots, such as
+// "12 spots from 5 receivers, best DX 2570 km, bands 40m 20m".
+func digestStats(spots []Spot) string {
+	receivers := make(map[string]bool)
+	bandSeen := make(map[Band]bool)
+	var best float64
+	for i := range spots {
+		s := &spots[i]
+		receivers[strings.ToUpper(s.ReceiverCallsign)] = true
+		if b, ok := spotBand(s); ok {
+			bandSeen[b] = true
+		}
+		if dist, ok := spotDistance(s); ok && dist > best {
+			best = dist
+		}
+	}
+
+	parts := []string{fmt.Sprintf("%d spots from %d receivers", len(spots), len(receivers))}
+	if best > 0 {
+		parts = append(parts, fmt.Sprintf("best DX %.0f km", best))
+	}
+	var bands []string
+	for _, b := range Bands() {
+		if bandSeen[b] {
+			bands = append(bands, string(b))
+		}
+	}
+	if len(bands) > 0 {
+		parts = append(parts, "bands "+strings.Join(bands, " "))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,66 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigest(t *testing.T) {
+	ctx := context.Background()
+
+	var sent []Notification
+	fail := false
+	next := NotifierFunc(func(ctx context.Context, n Notification) error {
+		if fail {
+			return errors.New("smtp down")
+		}
+		sent = append(sent, n)
+		return nil
+	})
+
+	d := NewDigest(next, "Hourly digest")
+	require.NoError(t, d.Send(ctx))
+	require.Empty(t, sent)
+
+	require.NoError(t, d.Notify(ctx, Notification{Title: "Heard in VK", Message: "AG6K spotted by VK3ABC", Spots: testSpots[:1]}))
+	require.NoError(t, d.Notify(ctx, Notification{Message: "Beacon W6ABC recovered", Spots: testSpots[1:]}))
+
+	fail = true
+	require.EqualError(t, d.Send(ctx), "smtp down")
+	fail = false
+
+	require.NoError(t, d.Send(ctx))
+	require.Len(t, sent, 1)
+	require.Equal(t, "Hourly digest", sent[0].Title)
+	require.Equal(t, "- Heard in VK: AG6K spotted by VK3ABC\n- Beacon W6ABC recovered\n\n2 alerts, 2 spots from 2 receivers, best DX 2570 km, bands 40m 20m", sent[0].Message)
+	require.Equal(t, testSpots, sent[0].Spots)
+
+	require.NoError(t, d.Send(ctx))
+	require.Len(t, sent, 1)
+
+	require.NoError(t, d.Notify(ctx, Notification{Title: "Only one"}))
+	require.NoError(t, d.Send(ctx))
+	require.Equal(t, "- Only one\n\n1 alert", sent[1].Message)
+}
+
+func TestDigestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sent := make(chan Notification, 1)
+	d := NewDigest(NotifierFunc(func(ctx context.Context, n Notification) error {
+		sent <- n
+		cancel()
+		return nil
+	}), "digest")
+	require.NoError(t, d.Notify(ctx, Notification{Message: "hi"}))
+
+	require.Equal(t, context.Canceled, d.Run(ctx, time.Millisecond, nil))
+	require.Equal(t, "digest", (<-sent).Title)
+
+	require.Error(t, d.Run(context.Background(), 0, nil))
+}
@@ -0,0 +1,55 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+)
+
+// discordMaxContent is the longest message Discord accepts.
+const discordMaxContent = 2000
+
+// DiscordNotifier posts notifications to a Discord channel through a webhook.
+type DiscordNotifier struct {
+	webhookURL string
+	username   string
+	opts       notifierOptions
+}
+
+// NewDiscordNotifier creates a notifier that posts to the webhook URL. If
+// username is not empty it overrides the webhook's default name.
+// WithNotifierAPIURL has no effect, as the webhook URL is used as is.
+func NewDiscordNotifier(webhookURL, username string, opts ...NotifierOption) (*DiscordNotifier, error) {
+	if webhookURL == "" {
+		return nil, errors.New("discord webhook url is required")
+	}
+	o, err := newNotifierOptions("", DefaultNotificationTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &DiscordNotifier{webhookURL: webhookURL, username: username, opts: o}, nil
+}
+
+// Notify posts the rendered notification, truncated to Discord's limit.
+func (d *DiscordNotifier) Notify(ctx context.Context, n Notification) error {
+	text, err := d.opts.render(n)
+	if err != nil {
+		return err
+	}
+
+	msg := struct {
+		Content  string `json:"content"`
+		Username string `json:"username,omitempty"`
+	}{truncate(text, discordMaxContent), d.username}
+
+	_, err = d.opts.postJSON(ctx, d.webhookURL, msg)
+	return err
+}
+
+// truncate shortens s to at most n runes, marking the cut with an ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
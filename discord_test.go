@@ -0,0 +1,45 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscordNotifier(t *testing.T) {
+	var got map[string]string
+	status := http.StatusNoContent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		got = nil
+		json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	_, err := NewDiscordNotifier("", "")
+	require.Error(t, err)
+
+	d, err := NewDiscordNotifier(srv.URL, "pskreporter")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, d.Notify(ctx, Notification{Title: "Beacon heard", Message: "W6ABC heard in ZL"}))
+	require.Equal(t, map[string]string{"content": "Beacon heard\nW6ABC heard in ZL", "username": "pskreporter"}, got)
+
+	require.NoError(t, d.Notify(ctx, Notification{Message: strings.Repeat("é", 3000)}))
+	require.Equal(t, discordMaxContent, utf8.RuneCountInString(got["content"]))
+	require.True(t, strings.HasSuffix(got["content"], "…"))
+
+	status = http.StatusTooManyRequests
+	require.EqualError(t, d.Notify(ctx, Notification{Message: "hi"}), "unexpected http response 429")
+}
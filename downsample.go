@@ -0,0 +1,120 @@
+package pskreporter
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SpotSummary collapses the spots heard on one band over one path during one
+// hour.
+type SpotSummary struct {
+	Hour             time.Time `json:"hour"` // start of the hour, in UTC
+	Band             Band      `json:"band"`
+	SenderCallsign   string    `json:"senderCallsign"`
+	ReceiverCallsign string    `json:"receiverCallsign"`
+	Count            int       `json:"count"`
+	BestSNR          int       `json:"bestSNR,omitempty"`
+	HasSNR           bool      `json:"hasSNR,omitempty"`
+	MaxDistance      float64   `json:"maxDistance,omitempty"` // kilometers
+}
+
+// Downsampler is implemented by stores that can replace old spots with hourly
+// summaries.
+type Downsampler interface {
+	// Downsample replaces the spots heard before threshold with summaries,
+	// returning how many spots were removed.
+	Downsample(ctx context.Context, threshold time.Time) (int, error)
+
+	// Summaries returns the summaries for hours starting in [start, end),
+	// where a zero start or end is unbounded, ordered by hour.
+	Summaries(ctx context.Context, start, end time.Time) ([]SpotSummary, error)
+}
+
+type summaryKey struct {
+	hour     time.Time
+	band     Band
+	sender   string
+	receiver string
+}
+
+func (s SpotSummary) key() summaryKey {
+	return summaryKey{s.Hour, s.Band, strings.ToUpper(s.SenderCallsign), strings.ToUpper(s.ReceiverCallsign)}
+}
+
+// merge folds o, which must have the same key, into s.
+func (s *SpotSummary) merge(o SpotSummary) {
+	s.Count += o.Count
+	if o.HasSNR && (!s.HasSNR || o.BestSNR > s.BestSNR) {
+		s.BestSNR, s.HasSNR = o.BestSNR, true
+	}
+	if o.MaxDistance > s.MaxDistance {
+		s.MaxDistance = o.MaxDistance
+	}
+}
+
+// Downsample collapses spots into hourly summaries per band and path, ordered
+// by hour, band and path. Spots with no known band are dropped.
+func Downsample(spots []Spot) []SpotSummary {
+	var summaries []SpotSummary
+	for i := range spots {
+		s := &spots[i]
+		b, ok := spotBand(s)
+		if !ok {
+			continue
+		}
+		sum := SpotSummary{
+			Hour:             s.Time.UTC().Truncate(time.Hour),
+			Band:             b,
+			SenderCallsign:   s.SenderCallsign,
+			ReceiverCallsign: s.ReceiverCallsign,
+			Count:            1,
+			BestSNR:          s.SNR,
+			HasSNR:           s.HasSNR,
+		}
+		if d, ok := spotDistance(s); ok {
+			sum.MaxDistance = d
+		}
+		summaries = append(summaries, sum)
+	}
+	return mergeSummaries(nil, summaries)
+}
+
+// mergeSummaries folds add into existing, combining summaries with the same
+// hour, band and path, and returns the result ordered by hour, band and path.
+func mergeSummaries(existing, add []SpotSummary) []SpotSummary {
+	index := make(map[summaryKey]int, len(existing))
+	merged := make([]SpotSummary, 0, len(existing)+len(add))
+	for _, list := range [][]SpotSummary{existing, add} {
+		for _, s := range list {
+			k := s.key()
+			if i, ok := index[k]; ok {
+				merged[i].merge(s)
+				continue
+			}
+			index[k] = len(merged)
+			merged = append(merged, s)
+		}
+	}
+
+	bandOrder := make(map[Band]int)
+	for i, b := range Bands() {
+		bandOrder[b] = i
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		a, b := merged[i], merged[j]
+		if !a.Hour.Equal(b.Hour) {
+			return a.Hour.Before(b.Hour)
+		}
+		if a.Band != b.Band {
+			return bandOrder[a.Band] < bandOrder[b.Band]
+		}
+		ka, kb := a.key(), b.key()
+		if ka.sender != kb.sender {
+			return ka.sender < kb.sender
+		}
+		return ka.receiver < kb.receiver
+	})
+	return merged
+}
@@ -0,0 +1,64 @@
+package pskreporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownsample(t *testing.T) {
+	hour := time.Date(2020, 9, 3, 20, 0, 0, 0, time.UTC)
+	spots := []Spot{
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Frequency: 14097100, SNR: -20, HasSNR: true, Distance: 1500, Time: hour.Add(5 * time.Minute)},
+		{SenderCallsign: "ag6k", ReceiverCallsign: "w5cj", Frequency: 14097050, SNR: -10, HasSNR: true, Distance: 1400, Time: hour.Add(50 * time.Minute)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Frequency: 7038600, Time: hour.Add(10 * time.Minute)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Frequency: 14097100, Time: hour.Add(time.Hour)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "N7HPX", Frequency: 14097100, Time: hour},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Frequency: 1, Time: hour}, // no band
+	}
+
+	require.Equal(t, []SpotSummary{
+		{Hour: hour, Band: Band40m, SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Count: 1},
+		{Hour: hour, Band: Band20m, SenderCallsign: "AG6K", ReceiverCallsign: "N7HPX", Count: 1},
+		{Hour: hour, Band: Band20m, SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Count: 2, BestSNR: -10, HasSNR: true, MaxDistance: 1500},
+		{Hour: hour.Add(time.Hour), Band: Band20m, SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Count: 1},
+	}, Downsample(spots))
+}
+
+func TestMemoryStoreDownsample(t *testing.T) {
+	ctx := context.Background()
+	hour := time.Date(2020, 9, 3, 20, 0, 0, 0, time.UTC)
+
+	s := NewMemoryStore()
+	require.NoError(t, s.Put(ctx, []Spot{
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Frequency: 14097100, SNR: -20, HasSNR: true, Time: hour.Add(5 * time.Minute)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Frequency: 14097100, SNR: -10, HasSNR: true, Time: hour.Add(50 * time.Minute)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Frequency: 14097100, Time: hour.Add(2 * time.Hour)},
+	}))
+
+	// The threshold splits the first hour, so the second pass must merge into
+	// the existing summary.
+	removed, err := s.Downsample(ctx, hour.Add(30*time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	removed, err = s.Downsample(ctx, hour.Add(time.Hour))
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	summaries, err := s.Summaries(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, []SpotSummary{
+		{Hour: hour, Band: Band20m, SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Count: 2, BestSNR: -10, HasSNR: true},
+	}, summaries)
+
+	summaries, err = s.Summaries(ctx, hour.Add(time.Hour), time.Time{})
+	require.NoError(t, err)
+	require.Empty(t, summaries)
+
+	spots, err := s.QueryByTimeRange(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, spots, 1)
+}
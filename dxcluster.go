@@ -0,0 +1,279 @@
+package pskreporter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FormatDXCluster renders a spot as a DX cluster spot line, such as
+// "DX de W5CJ:       14075.3  AG6K         FT8 -19 dB DM14>EM55         2003Z".
+// The receiver is the spotter and the sender is the DX station.
+func FormatDXCluster(s Spot) string {
+	var comment []string
+	if s.Mode != "" {
+		comment = append(comment, s.Mode)
+	}
+	if s.HasSNR {
+		comment = append(comment, fmt.Sprintf("%d dB", s.SNR))
+	}
+	if s.SenderLocator != "" && s.ReceiverLocator != "" {
+		comment = append(comment, grid4(s.SenderLocator)+">"+grid4(s.ReceiverLocator))
+	}
+
+	return fmt.Sprintf("DX de %-10s %8.1f  %-12s %-28.28s %sZ",
+		s.ReceiverCallsign+":",
+		float64(s.Frequency)/1e3,
+		s.SenderCallsign,
+		strings.Join(comment, " "),
+		s.Time.UTC().Format("1504"),
+	)
+}
+
+// grid4 returns the 4 character square of a locator, or the locator itself if
+// it is shorter.
+func grid4(loc string) string {
+	if len(loc) > 4 {
+		return strings.ToUpper(loc[:4])
+	}
+	return strings.ToUpper(loc)
+}
+
+// DXClusterSink writes spots to w as DX cluster spot lines.
+type DXClusterSink struct {
+	w io.Writer
+}
+
+// NewDXClusterSink creates a sink that writes one DX cluster line per spot to
+// w. If w is an io.Closer, it is closed when the sink is closed.
+func NewDXClusterSink(w io.Writer) *DXClusterSink {
+	return &DXClusterSink{w: w}
+}
+
+// Write writes the spots to the underlying writer.
+func (s *DXClusterSink) Write(ctx context.Context, spots []Spot) error {
+	for _, spot := range spots {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(s.w, FormatDXCluster(spot)+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush is a no-op; every spot is written as it is received.
+func (s *DXClusterSink) Flush() error {
+	return nil
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (s *DXClusterSink) Close() error {
+	return closeWriter(s.w)
+}
+
+// Settings for DX cluster server connections.
+const (
+	// dxClusterLoginTimeout is how long a client has to send its callsign.
+	dxClusterLoginTimeout = time.Minute
+
+	// dxClusterQueue is the number of lines buffered per client. Clients
+	// that fall further behind are disconnected.
+	dxClusterQueue = 256
+)
+
+var errServerClosed = errors.New("server is closed")
+
+// DXClusterServer is a Sink that serves spots to telnet clients in DX
+// cluster format, for logging programs that only understand cluster feeds.
+// Clients are asked for a callsign, then receive every spot written to the
+// server until they send "bye" or disconnect.
+type DXClusterServer struct {
+	l  net.Listener
+	wg sync.WaitGroup
+
+	mu      sync.Mutex
+	conns   map[net.Conn]struct{} // every open connection, logged in or not
+	clients map[*dxClusterClient]struct{}
+	closed  bool
+}
+
+type dxClusterClient struct {
+	conn  net.Conn
+	lines chan string
+}
+
+// ListenDXCluster starts a DX cluster server listening on addr.
+func ListenDXCluster(addr string) (*DXClusterServer, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewDXClusterServer(l), nil
+}
+
+// NewDXClusterServer starts a DX cluster server accepting connections on l.
+func NewDXClusterServer(l net.Listener) *DXClusterServer {
+	s := &DXClusterServer{
+		l:       l,
+		conns:   make(map[net.Conn]struct{}),
+		clients: make(map[*dxClusterClient]struct{}),
+	}
+	s.wg.Add(1)
+	go s.accept()
+	return s
+}
+
+// Addr returns the address the server is listening on.
+func (s *DXClusterServer) Addr() net.Addr {
+	return s.l.Addr()
+}
+
+// Write sends the spots to every logged in client.
+func (s *DXClusterServer) Write(ctx context.Context, spots []Spot) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return errServerClosed
+	}
+
+	for _, spot := range spots {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		line := FormatDXCluster(spot) + "\r\n"
+		for c := range s.clients {
+			select {
+			case c.lines <- line:
+			default:
+				s.removeLocked(c)
+			}
+		}
+	}
+	return nil
+}
+
+// Flush is a no-op; spots are queued to clients as they are received.
+func (s *DXClusterServer) Flush() error {
+	return nil
+}
+
+// Close stops accepting connections and disconnects every client.
+func (s *DXClusterServer) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errServerClosed
+	}
+	s.closed = true
+	err := s.l.Close()
+	for c := range s.clients {
+		s.removeLocked(c)
+	}
+	// Closing connections also ends those still waiting for a callsign.
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+func (s *DXClusterServer) accept() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.l.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.serve(conn)
+	}
+}
+
+func (s *DXClusterServer) serve(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	r := bufio.NewReader(conn)
+	conn.SetDeadline(time.Now().Add(dxClusterLoginTimeout))
+	io.WriteString(conn, "login: ")
+	call, err := r.ReadString('\n')
+	call = strings.ToUpper(strings.TrimSpace(call))
+	if err != nil || call == "" {
+		conn.Close()
+		return
+	}
+	conn.SetDeadline(time.Time{})
+	fmt.Fprintf(conn, "Hello %s, this is the PSKReporter cluster.\r\n%s de PSKREPORTER >\r\n", call, call)
+
+	c := &dxClusterClient{conn: conn, lines: make(chan string, dxClusterQueue)}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		conn.Close()
+		return
+	}
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer conn.Close()
+		// Closing the connection on a write error ends the read loop
+		// below, which removes the client.
+		for line := range c.lines {
+			if _, err := io.WriteString(conn, line); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "bye", "quit", "exit":
+			io.WriteString(conn, "73\r\n")
+			s.remove(c)
+			return
+		}
+	}
+	s.remove(c)
+}
+
+func (s *DXClusterServer) remove(c *dxClusterClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeLocked(c)
+}
+
+func (s *DXClusterServer) removeLocked(c *dxClusterClient) {
+	if _, ok := s.clients[c]; ok {
+		delete(s.clients, c)
+		close(c.lines)
+	}
+}
@@ -0,0 +1,149 @@
+package pskreporter
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatDXCluster(t *testing.T) {
+	require.Equal(t,
+		"DX de W5CJ:       14075.3  AG6K         FT8 -19 dB DM14>EM55         2003Z",
+		FormatDXCluster(testSpots[0]),
+	)
+	require.Equal(t,
+		"DX de N7HPX:       7075.3  AG6K         FT8                          2002Z",
+		FormatDXCluster(testSpots[1]),
+	)
+}
+
+func TestDXClusterSink(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewDXClusterSink(&buf)
+	require.NoError(t, s.Write(context.Background(), testSpots))
+	require.NoError(t, s.Close())
+	require.Equal(t, FormatDXCluster(testSpots[0])+"\n"+FormatDXCluster(testSpots[1])+"\n", buf.String())
+}
+
+func TestDXClusterServer(t *testing.T) {
+	ctx := context.Background()
+	s, err := ListenDXCluster("127.0.0.1:0")
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+
+	prompt := make([]byte, len("login: "))
+	_, err = r.Read(prompt)
+	require.NoError(t, err)
+	require.Equal(t, "login: ", string(prompt))
+
+	_, err = conn.Write([]byte("ag6k\r\n"))
+	require.NoError(t, err)
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "Hello AG6K, this is the PSKReporter cluster.\r\n", line)
+	_, err = r.ReadString('\n')
+	require.NoError(t, err)
+
+	// The client is registered after the greeting is sent, so keep writing
+	// until the spot arrives.
+	got := make(chan string)
+	go func() {
+		line, _ := r.ReadString('\n')
+		got <- line
+	}()
+	var received string
+	require.Eventually(t, func() bool {
+		require.NoError(t, s.Write(ctx, testSpots[:1]))
+		select {
+		case received = <-got:
+			return true
+		default:
+			return false
+		}
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Equal(t, FormatDXCluster(testSpots[0])+"\r\n", received)
+
+	require.NoError(t, s.Flush())
+	require.NoError(t, s.Close())
+	require.Equal(t, errServerClosed, s.Write(ctx, testSpots))
+
+	// The server hangs up on close; skip any spots still buffered.
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+		require.True(t, strings.HasPrefix(line, "DX de "))
+	}
+}
+
+func TestDXClusterServerBye(t *testing.T) {
+	s, err := ListenDXCluster("127.0.0.1:0")
+	require.NoError(t, err)
+	defer s.Close()
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	_, err = conn.Write([]byte("AG6K\nbye\n"))
+	require.NoError(t, err)
+
+	r := bufio.NewReader(conn)
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			break
+		}
+		lines = append(lines, line)
+	}
+	require.Equal(t, "73\r\n", lines[len(lines)-1])
+
+	require.Eventually(t, func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return len(s.clients) == 0
+	}, time.Second, time.Millisecond)
+}
+
+func TestDXClusterServerCloseBeforeLogin(t *testing.T) {
+	s, err := ListenDXCluster("127.0.0.1:0")
+	require.NoError(t, err)
+
+	conn, err := net.Dial("tcp", s.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// Wait for the prompt so the server is serving the connection, then
+	// never log in.
+	prompt := make([]byte, len("login: "))
+	_, err = io.ReadFull(conn, prompt)
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() { done <- s.Close() }()
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close waited for the client to log in")
+	}
+
+	_, err = conn.Read(prompt)
+	require.ErrorIs(t, err, io.EOF)
+}
@@ -0,0 +1,139 @@
+package pskreporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// EmailNotifier sends notifications as email over SMTP. When a notification
+// has spots with locators at both ends, a map of them is attached as an SVG
+// image.
+type EmailNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	opts notifierOptions
+
+	// send is smtp.SendMail, replaced in tests.
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier creates a notifier that sends mail through the SMTP server
+// at addr (host:port) from one address to the others. auth may be nil for
+// servers that don't require it. Pair it with a Digest to send one email per
+// period. The subject is the notification's title and the body is rendered
+// with DefaultBodyTemplate unless WithNotificationTemplate is given.
+func NewEmailNotifier(addr string, auth smtp.Auth, from string, to []string, opts ...NotifierOption) (*EmailNotifier, error) {
+	if addr == "" || from == "" || len(to) == 0 {
+		return nil, errors.New("smtp address, sender and recipients are required")
+	}
+	o, err := newNotifierOptions("", DefaultBodyTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &EmailNotifier{addr: addr, auth: auth, from: from, to: to, opts: o, send: smtp.SendMail}, nil
+}
+
+// Notify sends the notification as an email.
+func (e *EmailNotifier) Notify(ctx context.Context, n Notification) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	msg, err := e.message(n, timeNow())
+	if err != nil {
+		return err
+	}
+	return e.send(e.addr, e.auth, e.from, e.to, msg)
+}
+
+// message builds the MIME message for n.
+func (e *EmailNotifier) message(n Notification, date time.Time) ([]byte, error) {
+	text, err := e.opts.render(n)
+	if err != nil {
+		return nil, err
+	}
+
+	subject := n.Title
+	if subject == "" {
+		subject = "PSKReporter notification"
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", e.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", mw.Boundary())
+
+	pw, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"base64"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := writeBase64(pw, []byte(text)); err != nil {
+		return nil, err
+	}
+
+	if hasMappableSpot(n.Spots) {
+		var svg bytes.Buffer
+		if err := RenderSVG(&svg, n.Spots); err != nil {
+			return nil, fmt.Errorf("rendering map: %w", err)
+		}
+		aw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {"image/svg+xml"},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {`attachment; filename="spots.svg"`},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64(aw, svg.Bytes()); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// hasMappableSpot reports whether any of spots has valid locators at both
+// ends, so that RenderSVG draws it.
+func hasMappableSpot(spots []Spot) bool {
+	for i := range spots {
+		if _, _, ok := spotEnds(&spots[i]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// writeBase64 writes b base64 encoded in 76 character lines, as MIME requires.
+func writeBase64(w io.Writer, b []byte) error {
+	enc := base64.StdEncoding.EncodeToString(b)
+	for len(enc) > 76 {
+		if _, err := fmt.Fprintf(w, "%s\r\n", enc[:76]); err != nil {
+			return err
+		}
+		enc = enc[76:]
+	}
+	_, err := fmt.Fprintf(w, "%s\r\n", enc)
+	return err
+}
@@ -0,0 +1,99 @@
+package pskreporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmailNotifier(t *testing.T) {
+	_, err := NewEmailNotifier("smtp.example.com:587", nil, "psk@example.com", nil)
+	require.Error(t, err)
+
+	e, err := NewEmailNotifier("smtp.example.com:587", nil, "psk@example.com", []string{"ag6k@example.com", "club@example.com"})
+	require.NoError(t, err)
+
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var raw []byte
+	e.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, raw = addr, from, to, msg
+		return nil
+	}
+
+	require.NoError(t, e.Notify(context.Background(), Notification{
+		Title:   "Daily digest ✓",
+		Message: "AG6K was heard by 2 stations",
+		Spots:   testSpots,
+	}))
+	require.Equal(t, "smtp.example.com:587", gotAddr)
+	require.Equal(t, "psk@example.com", gotFrom)
+	require.Equal(t, []string{"ag6k@example.com", "club@example.com"}, gotTo)
+
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Daily digest ✓", subject)
+	require.Equal(t, "ag6k@example.com, club@example.com", msg.Header.Get("To"))
+	_, err = msg.Header.Date()
+	require.NoError(t, err)
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/mixed", mediaType)
+
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	part := func() (*multipart.Part, string) {
+		p, err := mr.NextPart()
+		require.NoError(t, err)
+		b, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, p))
+		require.NoError(t, err)
+		return p, string(b)
+	}
+
+	p, body := part()
+	require.Equal(t, "text/plain; charset=utf-8", p.Header.Get("Content-Type"))
+	require.True(t, strings.HasPrefix(body, "AG6K was heard by 2 stations\nW5CJ"))
+
+	p, svg := part()
+	require.Equal(t, "spots.svg", p.FileName())
+	require.Contains(t, svg, "<svg")
+
+	_, err = mr.NextPart()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestEmailNotifierNoSpots(t *testing.T) {
+	e, err := NewEmailNotifier("smtp.example.com:587", nil, "psk@example.com", []string{"ag6k@example.com"})
+	require.NoError(t, err)
+
+	// No map is attached without spots to draw, including spots missing a
+	// locator.
+	for _, spots := range [][]Spot{nil, {{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ"}}} {
+		raw, err := e.message(Notification{Message: "hello", Spots: spots}, time.Unix(1599163380, 0).UTC())
+		require.NoError(t, err)
+		msg, err := mail.ReadMessage(bytes.NewReader(raw))
+		require.NoError(t, err)
+		require.Equal(t, "PSKReporter notification", msg.Header.Get("Subject"))
+		require.Equal(t, "Thu, 03 Sep 2020 20:03:00 +0000", msg.Header.Get("Date"))
+
+		_, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+		require.NoError(t, err)
+		mr := multipart.NewReader(msg.Body, params["boundary"])
+		_, err = mr.NextPart()
+		require.NoError(t, err)
+		_, err = mr.NextPart()
+		require.Equal(t, io.EOF, err)
+	}
+}
@@ -0,0 +1,104 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+// WithBaseURLs sets several API base URLs to query, such as a local mirror
+// followed by the official server. Queries go to the last base URL that
+// answered, starting with the first, and fail over to the others in order
+// when it can't be reached or answers with a server error or throttling. The
+// base URLs' query parameters are taken from the first. Clients derived with
+// With share which base URL is preferred.
+func WithBaseURLs(urls ...string) ClientOption {
+	return func(o *clientOptions) error {
+		if len(urls) == 0 {
+			return badOption(errors.New("WithBaseURLs needs at least one url"))
+		}
+		e := &endpointSet{}
+		for _, s := range urls {
+			u, err := url.Parse(s)
+			if err != nil {
+				return badOption(err)
+			}
+			e.urls = append(e.urls, u)
+		}
+		o.baseURL = urls[0]
+		o.endpoints = e
+		if len(urls) == 1 {
+			o.endpoints = nil
+		}
+		return nil
+	}
+}
+
+// endpointSet is a list of base URLs and which of them last worked. It is
+// safe for concurrent use.
+type endpointSet struct {
+	mu        sync.Mutex
+	urls      []*url.URL
+	preferred int
+}
+
+// order returns the indexes of the base URLs in the order to try them.
+func (e *endpointSet) order() []int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	order := make([]int, 0, len(e.urls))
+	for i := range e.urls {
+		order = append(order, (e.preferred+i)%len(e.urls))
+	}
+	return order
+}
+
+// succeeded records that the base URL at i answered.
+func (e *endpointSet) succeeded(i int) {
+	e.mu.Lock()
+	e.preferred = i
+	e.mu.Unlock()
+}
+
+// at returns u sent to the base URL at i instead.
+func (e *endpointSet) at(i int, u *url.URL) *url.URL {
+	v := *e.urls[i]
+	v.RawQuery = u.RawQuery
+	return &v
+}
+
+// getFailover requests u from each base URL in turn until one answers.
+func (c *Client) getFailover(ctx context.Context, u *url.URL, meta *QueryMeta) (io.ReadCloser, error) {
+	var errs []error
+	for _, i := range c.endpoints.order() {
+		eu := c.endpoints.at(i, u)
+		body, err := c.do(ctx, eu, meta)
+		if err == nil {
+			c.endpoints.succeeded(i)
+			return body, nil
+		}
+		if ctx.Err() != nil || !failoverable(err) {
+			return nil, err
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", eu.Host, err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// failoverable reports whether a request that failed with err should be tried
+// on another base URL: the server couldn't be reached, had an error, or is
+// throttling the client. Other errors would most likely recur.
+func failoverable(err error) bool {
+	if errors.Is(err, ErrThrottled) {
+		return true
+	}
+	var se *HTTPStatusError
+	if errors.As(err, &se) {
+		return se.Code >= http.StatusInternalServerError
+	}
+	return true
+}
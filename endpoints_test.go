@@ -0,0 +1,106 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBaseURLs(t *testing.T) {
+	newServer := func(status *int32, hits *int32) *httptest.Server {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(hits, 1)
+			if s := atomic.LoadInt32(status); s != http.StatusOK {
+				w.WriteHeader(int(s))
+				return
+			}
+			w.Write([]byte(`<receptionReports><lastSequenceNumber value="` + req.URL.Query().Get("callsign") + `"/></receptionReports>`))
+		}))
+		t.Cleanup(svr.Close)
+		return svr
+	}
+
+	var (
+		mirrorStatus, officialStatus int32 = http.StatusOK, http.StatusOK
+		mirrorHits, officialHits     int32
+	)
+	mirror := newServer(&mirrorStatus, &mirrorHits)
+	official := newServer(&officialStatus, &officialHits)
+
+	c, err := New(WithBaseURLs(mirror.URL+"/query", official.URL+"/query"))
+	require.NoError(t, err)
+	officialURL, err := url.Parse(official.URL)
+	require.NoError(t, err)
+
+	r, err := c.Query(WithCallsign("AG6K"))
+	require.NoError(t, err)
+	require.Equal(t, "AG6K", r.LastSequenceNumber.Value)
+	require.Equal(t, int32(1), mirrorHits)
+	require.Equal(t, int32(0), officialHits)
+
+	// The mirror fails, so the official server answers and is preferred from
+	// then on.
+	atomic.StoreInt32(&mirrorStatus, http.StatusBadGateway)
+	_, err = c.Query(WithCallsign("W5CJ"))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), mirrorHits)
+	require.Equal(t, int32(1), officialHits)
+
+	atomic.StoreInt32(&mirrorStatus, http.StatusOK)
+	d, err := c.With(WithDefaultAppContact("me@example.com"))
+	require.NoError(t, err)
+	_, err = d.Query(WithCallsign("N7HPX"))
+	require.NoError(t, err)
+	require.Equal(t, int32(2), mirrorHits)
+	require.Equal(t, int32(2), officialHits)
+
+	// Client errors aren't retried elsewhere.
+	atomic.StoreInt32(&officialStatus, http.StatusBadRequest)
+	_, err = c.Query(WithCallsign("K1ABC"))
+	var se *HTTPStatusError
+	require.True(t, errors.As(err, &se))
+	require.Equal(t, http.StatusBadRequest, se.Code)
+	require.Equal(t, int32(2), mirrorHits)
+
+	// The error names the server that answered, not the first base URL.
+	var qe *QueryError
+	require.True(t, errors.As(err, &qe))
+	require.Equal(t, officialURL.Host, qe.Host)
+	_, meta, err := c.QueryWithMeta(context.Background(), WithCallsign("K1ABC"))
+	require.Error(t, err)
+	require.Equal(t, officialURL.Host, meta.Host)
+	_, err = c.QueryToSink(context.Background(), &recordingSink{}, WithCallsign("K1ABC"))
+	require.True(t, errors.As(err, &qe))
+	require.Equal(t, officialURL.Host, qe.Host)
+
+	// When every server fails, each error is kept.
+	atomic.StoreInt32(&officialStatus, http.StatusServiceUnavailable)
+	atomic.StoreInt32(&mirrorStatus, http.StatusInternalServerError)
+	_, err = c.Query(WithCallsign("K1ABC"))
+	require.True(t, errors.Is(err, ErrThrottled))
+	require.True(t, errors.As(err, &se))
+	require.Contains(t, err.Error(), "unexpected http response 500")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	hits := atomic.LoadInt32(&mirrorHits) + atomic.LoadInt32(&officialHits)
+	_, err = c.QueryContext(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, hits, atomic.LoadInt32(&mirrorHits)+atomic.LoadInt32(&officialHits))
+
+	// A single base URL replaces the list.
+	d, err = c.With(WithBaseURL(mirror.URL))
+	require.NoError(t, err)
+	require.Nil(t, d.endpoints)
+
+	_, err = New(WithBaseURLs())
+	require.True(t, errors.Is(err, ErrBadOption))
+	_, err = New(WithBaseURLs("http://ok", "://bad"))
+	require.True(t, errors.Is(err, ErrBadOption))
+}
@@ -0,0 +1,165 @@
+package pskreporter
+
+import (
+	"math"
+	"strings"
+)
+
+// Enricher adds derived data to a Spot.
+type Enricher interface {
+	Enrich(*Spot) error
+}
+
+// EnricherFunc adapts an ordinary function to the Enricher interface.
+type EnricherFunc func(*Spot) error
+
+// Enrich calls f(s).
+func (f EnricherFunc) Enrich(s *Spot) error {
+	return f(s)
+}
+
+// Pipeline runs a chain of enrichers over spots, in order. A Pipeline is itself
+// an Enricher, so pipelines can be nested.
+type Pipeline struct {
+	enrichers []Enricher
+}
+
+// NewPipeline creates a Pipeline that runs the given enrichers in order.
+func NewPipeline(enrichers ...Enricher) *Pipeline {
+	return &Pipeline{enrichers: enrichers}
+}
+
+// DefaultPipeline creates a Pipeline running the built-in band, distance,
+// continent and greyline enrichers followed by any extra enrichers supplied.
+// Filling in missing DXCC details takes a Response, with DXCCEnricher, so
+// queries returning spots do that before running a client's pipeline.
+func DefaultPipeline(extra ...Enricher) *Pipeline {
+	enrichers := []Enricher{
+		BandEnricher(),
+		DistanceEnricher(),
+		ContinentEnricher(),
+		GreylineEnricher(),
+	}
+	return NewPipeline(append(enrichers, extra...)...)
+}
+
+// Enrich runs every enricher in the pipeline against s, stopping at the first
+// error.
+func (p *Pipeline) Enrich(s *Spot) error {
+	for _, e := range p.enrichers {
+		if err := e.Enrich(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply enriches every spot in the slice in place.
+func (p *Pipeline) Apply(spots []Spot) error {
+	for i := range spots {
+		if err := p.Enrich(&spots[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BandEnricher sets Spot.Band from the spot's frequency. Spots outside of any
+// known band are left untouched.
+func BandEnricher() Enricher {
+	return EnricherFunc(func(s *Spot) error {
+		if b, ok := BandForFrequency(s.Frequency); ok {
+			s.Band = b
+		}
+		return nil
+	})
+}
+
+// RegionBandEnricher is like BandEnricher, but uses the band plan of region.
+func RegionBandEnricher(region IARURegion) Enricher {
+	return EnricherFunc(func(s *Spot) error {
+		if b, ok := BandForFrequencyIn(region, s.Frequency); ok {
+			s.Band = b
+		}
+		return nil
+	})
+}
+
+// DistanceEnricher sets Spot.Distance and Spot.Bearing from the sender and
+// receiver locators. Spots missing a valid locator on either end are left
+// untouched.
+func DistanceEnricher() Enricher {
+	return EnricherFunc(func(s *Spot) error {
+		from, to, ok := spotEnds(s)
+		if !ok {
+			return nil
+		}
+		s.Distance = from.DistanceTo(to)
+		s.Bearing = from.BearingTo(to)
+		return nil
+	})
+}
+
+// greylineElevation is how far the sun may be above or below the horizon, in
+// degrees, for a location to be considered within the greyline.
+const greylineElevation = 6.0
+
+// GreylineEnricher sets Spot.Greyline when both the sender and the receiver
+// were close to the day/night terminator at the time of the spot. Spots missing
+// a time or a valid locator on either end are left untouched.
+func GreylineEnricher() Enricher {
+	return EnricherFunc(func(s *Spot) error {
+		from, to, ok := spotEnds(s)
+		if !ok || s.Time.IsZero() {
+			return nil
+		}
+		s.Greyline = math.Abs(sunElevation(from, s.Time)) <= greylineElevation &&
+			math.Abs(sunElevation(to, s.Time)) <= greylineElevation
+		return nil
+	})
+}
+
+// DXCCEnricher fills in missing sender and receiver DXCC details using the
+// active callsign and active receiver lists from r.
+func DXCCEnricher(r *Response) Enricher {
+	type entity struct{ name, code string }
+	lookup := make(map[string]entity, len(r.ActiveCallsigns)+len(r.ActiveReceivers))
+	for _, ar := range r.ActiveReceivers {
+		if ar.DXCC != "" {
+			lookup[strings.ToUpper(ar.Callsign)] = entity{name: ar.DXCC}
+		}
+	}
+	// Active callsigns carry the DXCC code as well as the name, so prefer them.
+	for _, ac := range r.ActiveCallsigns {
+		if ac.DXCC != "" {
+			lookup[strings.ToUpper(ac.Callsign)] = entity{name: ac.DXCC, code: ac.DXCCcode}
+		}
+	}
+
+	return EnricherFunc(func(s *Spot) error {
+		if s.SenderDXCC == "" {
+			if e, ok := lookup[strings.ToUpper(s.SenderCallsign)]; ok {
+				s.SenderDXCC, s.SenderDXCCCode = e.name, e.code
+			}
+		}
+		if s.ReceiverDXCC == "" {
+			if e, ok := lookup[strings.ToUpper(s.ReceiverCallsign)]; ok {
+				s.ReceiverDXCC, s.ReceiverDXCCCode = e.name, e.code
+			}
+		}
+		return nil
+	})
+}
+
+// spotEnds returns the positions of the sender and the receiver of the spot.
+func spotEnds(s *Spot) (from, to LatLon, ok bool) {
+	from, err := ParseLocator(s.SenderLocator)
+	if err != nil {
+		return from, to, false
+	}
+	to, err = ParseLocator(s.ReceiverLocator)
+	if err != nil {
+		return from, to, false
+	}
+	return from, to, true
+}
@@ -0,0 +1,82 @@
+package pskreporter
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipeline(t *testing.T) {
+	t.Run("default", func(t *testing.T) {
+		s := Spot{
+			SenderLocator:    "DM14cc24",
+			SenderDXCCCode:   "291",
+			ReceiverLocator:  "EM55db92",
+			ReceiverDXCCCode: "230",
+			Frequency:        14075311,
+			Time:             time.Unix(1599163380, 0),
+		}
+
+		require.NoError(t, DefaultPipeline().Enrich(&s))
+		require.Equal(t, Band20m, s.Band)
+		require.Equal(t, ContinentNorthAmerica, s.SenderContinent)
+		require.Equal(t, ContinentEurope, s.ReceiverContinent)
+		require.InDelta(t, 2570.1, s.Distance, 0.1)
+		require.InDelta(t, 79.6, s.Bearing, 0.1)
+		require.False(t, s.Greyline)
+	})
+
+	t.Run("order and errors", func(t *testing.T) {
+		var calls []string
+		e := func(name string, err error) Enricher {
+			return EnricherFunc(func(*Spot) error {
+				calls = append(calls, name)
+				return err
+			})
+		}
+
+		errBoom := errors.New("boom")
+		p := NewPipeline(e("a", nil), NewPipeline(e("b", nil)), e("c", errBoom), e("d", nil))
+		require.Equal(t, errBoom, p.Apply(make([]Spot, 2)))
+		require.Equal(t, []string{"a", "b", "c"}, calls)
+	})
+
+	t.Run("missing locators", func(t *testing.T) {
+		s := Spot{SenderLocator: "DM14", Time: time.Unix(1599163380, 0)}
+		require.NoError(t, DefaultPipeline().Enrich(&s))
+		require.Equal(t, 0.0, s.Distance)
+		require.Equal(t, Band(""), s.Band)
+	})
+}
+
+func TestGreylineEnricher(t *testing.T) {
+	// Shortly after sunset in New England around the equinox, when it is
+	// already the middle of the night in western Europe.
+	s := Spot{
+		SenderLocator:   "FN31",
+		ReceiverLocator: "IN53",
+		Time:            time.Date(2020, 3, 20, 23, 15, 0, 0, time.UTC),
+	}
+	require.NoError(t, GreylineEnricher().Enrich(&s))
+	require.False(t, s.Greyline)
+
+	s.ReceiverLocator = "FN42"
+	require.NoError(t, GreylineEnricher().Enrich(&s))
+	require.True(t, s.Greyline)
+}
+
+func TestDXCCEnricher(t *testing.T) {
+	resp := &Response{
+		ActiveCallsigns: []ActiveCallsign{{Callsign: "R2PU", DXCC: "European Russia", DXCCcode: "UA"}},
+		ActiveReceivers: []ActiveReceiver{{Callsign: "DL0046SWL", DXCC: "Fed. Rep. of Germany"}},
+	}
+
+	s := Spot{SenderCallsign: "r2pu", ReceiverCallsign: "DL0046SWL"}
+	require.NoError(t, DXCCEnricher(resp).Enrich(&s))
+	require.Equal(t, "European Russia", s.SenderDXCC)
+	require.Equal(t, "UA", s.SenderDXCCCode)
+	require.Equal(t, "Fed. Rep. of Germany", s.ReceiverDXCC)
+	require.Equal(t, "", s.ReceiverDXCCCode)
+}
@@ -0,0 +1,64 @@
+package pskreporter
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Environment variables read by NewFromEnv.
+const (
+	EnvBaseURL    = "PSKREPORTER_BASE_URL"
+	EnvCacheDir   = "PSKREPORTER_CACHE_DIR"
+	EnvCacheTTL   = "PSKREPORTER_CACHE_TTL"
+	EnvAppContact = "PSKREPORTER_APP_CONTACT"
+	EnvRateLimit  = "PSKREPORTER_RATE_LIMIT"
+)
+
+// NewFromEnv instantiates a new Client configured from the environment.
+// PSKREPORTER_CACHE_TTL and PSKREPORTER_RATE_LIMIT are durations such as
+// "5m", or a number of seconds; PSKREPORTER_RATE_LIMIT sets the client's
+// minimum query interval.
+// Unset variables leave the defaults in place, and opts are applied after the
+// environment so they take precedence.
+func NewFromEnv(opts ...ClientOption) (*Client, error) {
+	var envOpts []ClientOption
+	if v := os.Getenv(EnvBaseURL); v != "" {
+		envOpts = append(envOpts, WithBaseURL(v))
+	}
+	if v := os.Getenv(EnvCacheDir); v != "" {
+		envOpts = append(envOpts, WithCacheDir(v))
+	}
+	if v := os.Getenv(EnvCacheTTL); v != "" {
+		d, err := parseEnvDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", EnvCacheTTL, err)
+		}
+		envOpts = append(envOpts, WithCacheDuration(d))
+	}
+	if v := os.Getenv(EnvAppContact); v != "" {
+		envOpts = append(envOpts, WithDefaultAppContact(v))
+	}
+	if v := os.Getenv(EnvRateLimit); v != "" {
+		d, err := parseEnvDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", EnvRateLimit, err)
+		}
+		envOpts = append(envOpts, WithMinQueryInterval(d))
+	}
+
+	return New(append(envOpts, opts...)...)
+}
+
+// parseEnvDuration parses a duration, treating a bare number as seconds.
+func parseEnvDuration(s string) (time.Duration, error) {
+	if secs, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if secs > math.MaxInt64/int64(time.Second) || secs < math.MinInt64/int64(time.Second) {
+			return 0, &strconv.NumError{Func: "ParseInt", Num: s, Err: strconv.ErrRange}
+		}
+		return time.Duration(secs) * time.Second, nil
+	}
+	return time.ParseDuration(s)
+}
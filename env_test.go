@@ -0,0 +1,84 @@
+package pskreporter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFromEnv(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		c, err := NewFromEnv()
+		require.NoError(t, err)
+		require.Equal(t, queryURL, c.baseURL)
+		require.Equal(t, 280*time.Second, c.cacheDuration)
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		var contact string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			contact = r.URL.Query().Get("appcontact")
+			w.Write([]byte("<receptionReports/>"))
+		}))
+		defer srv.Close()
+
+		dir := t.TempDir()
+		t.Setenv(EnvBaseURL, srv.URL)
+		t.Setenv(EnvCacheDir, dir)
+		t.Setenv(EnvCacheTTL, "90")
+		t.Setenv(EnvAppContact, "me@example.com")
+		t.Setenv(EnvRateLimit, "1ms")
+
+		c, err := NewFromEnv()
+		require.NoError(t, err)
+		require.Equal(t, srv.URL, c.baseURL)
+		require.Equal(t, dir, c.cacheDir)
+		require.Equal(t, 90*time.Second, c.cacheDuration)
+		require.Equal(t, time.Millisecond, c.limiter.interval)
+
+		_, err = c.Query()
+		require.NoError(t, err)
+		require.Equal(t, "me@example.com", contact)
+
+		_, err = c.Query(WithAppContact("other@example.com"))
+		require.NoError(t, err)
+		require.Equal(t, "other@example.com", contact)
+	})
+
+	t.Run("options override", func(t *testing.T) {
+		t.Setenv(EnvCacheTTL, "5m")
+		c, err := NewFromEnv(WithCacheDuration(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, c.cacheDuration)
+	})
+
+	t.Run("bad ttl", func(t *testing.T) {
+		t.Setenv(EnvCacheTTL, "soon")
+		_, err := NewFromEnv()
+		require.Error(t, err)
+
+		t.Setenv(EnvCacheTTL, "-1s")
+		_, err = NewFromEnv()
+		require.Error(t, err)
+
+		t.Setenv(EnvCacheTTL, "9223372037") // seconds overflowing a Duration
+		_, err = NewFromEnv()
+		require.Error(t, err)
+	})
+
+	t.Run("rate limit seconds", func(t *testing.T) {
+		t.Setenv(EnvRateLimit, "2")
+		c, err := NewFromEnv()
+		require.NoError(t, err)
+		require.Equal(t, 2*time.Second, c.limiter.interval)
+	})
+
+	t.Run("bad rate limit", func(t *testing.T) {
+		t.Setenv(EnvRateLimit, "often")
+		_, err := NewFromEnv()
+		require.ErrorContains(t, err, EnvRateLimit)
+	})
+}
@@ -0,0 +1,69 @@
+package pskreporter
+
+// Equal reports whether two responses hold the same data. The XML character
+// data and the order of the reports, receivers and callsigns are ignored, as
+// are the query parameters. A partial response never equals a complete one.
+func Equal(a, b *Response) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Partial != b.Partial ||
+		a.CurrentSeconds != b.CurrentSeconds ||
+		a.LastSequenceNumber.Value != b.LastSequenceNumber.Value ||
+		a.MaxFlowStartSeconds.Value != b.MaxFlowStartSeconds.Value ||
+		a.SenderSearch.Callsign != b.SenderSearch.Callsign ||
+		a.SenderSearch.RecentFlowStartSeconds != b.SenderSearch.RecentFlowStartSeconds {
+		return false
+	}
+	return sameElements(a.ReceptionReports, b.ReceptionReports, func(r ReceptionReport) ReceptionReport {
+		r.Text = ""
+		return r
+	}) && sameElements(a.ActiveReceivers, b.ActiveReceivers, func(r ActiveReceiver) ActiveReceiver {
+		r.Text = ""
+		return r
+	}) && sameElements(a.ActiveCallsigns, b.ActiveCallsigns, func(c ActiveCallsign) ActiveCallsign {
+		c.Text = ""
+		return c
+	})
+}
+
+// sameElements reports whether a and b hold the same elements, in any order,
+// once each has been passed through clean.
+func sameElements[T comparable](a, b []T, clean func(T) T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[T]int, len(a))
+	for _, v := range a {
+		counts[clean(v)]++
+	}
+	for _, v := range b {
+		v = clean(v)
+		if counts[v] == 0 {
+			return false
+		}
+		counts[v]--
+	}
+	return true
+}
+
+// Equal reports whether two reception reports are the same, ignoring the XML
+// character data.
+func (r ReceptionReport) Equal(o ReceptionReport) bool {
+	r.Text, o.Text = "", ""
+	return r == o
+}
+
+// Equal reports whether two active receivers are the same, ignoring the XML
+// character data.
+func (r ActiveReceiver) Equal(o ActiveReceiver) bool {
+	r.Text, o.Text = "", ""
+	return r == o
+}
+
+// Equal reports whether two active callsigns are the same, ignoring the XML
+// character data.
+func (c ActiveCallsign) Equal(o ActiveCallsign) bool {
+	c.Text, o.Text = "", ""
+	return c == o
+}
@@ -0,0 +1,65 @@
+package pskreporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqual(t *testing.T) {
+	require.True(t, Equal(nil, nil))
+
+	a := loadResponse(t)
+	require.False(t, Equal(a, nil))
+	require.True(t, Equal(a, a.Clone()))
+
+	t.Run("ignores order and chardata", func(t *testing.T) {
+		b := a.Clone()
+		b.Text = "\n  "
+		b.ReceptionReports[0], b.ReceptionReports[1] = b.ReceptionReports[1], b.ReceptionReports[0]
+		b.ActiveReceivers[0].Text = "\n"
+		b.Query.Callsign = "AG6K"
+		require.True(t, Equal(a, b))
+	})
+
+	t.Run("differences", func(t *testing.T) {
+		tests := []struct {
+			desc   string
+			change func(*Response)
+		}{
+			{"current seconds", func(r *Response) { r.CurrentSeconds = "1" }},
+			{"sequence number", func(r *Response) { r.LastSequenceNumber.Value = "1" }},
+			{"partial", func(r *Response) { r.Partial = true }},
+			{"report", func(r *Response) { r.ReceptionReports[0].SNR = "99" }},
+			{"missing report", func(r *Response) { r.ReceptionReports = r.ReceptionReports[1:] }},
+			{"receiver", func(r *Response) { r.ActiveReceivers[0].Locator = "AA00" }},
+			{"callsign", func(r *Response) { r.ActiveCallsigns[0].Reports = "0" }},
+			{
+				"duplicated report",
+				func(r *Response) { r.ReceptionReports[1] = r.ReceptionReports[0] },
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.desc, func(t *testing.T) {
+				b := a.Clone()
+				tt.change(b)
+				require.False(t, Equal(a, b))
+				require.False(t, Equal(b, a))
+			})
+		}
+	})
+}
+
+func TestElementEqual(t *testing.T) {
+	r := ReceptionReport{SenderCallsign: "AG6K", Text: "\n"}
+	require.True(t, r.Equal(ReceptionReport{SenderCallsign: "AG6K"}))
+	require.False(t, r.Equal(ReceptionReport{SenderCallsign: "W5CJ"}))
+
+	ar := ActiveReceiver{Callsign: "W5CJ", Text: "\n"}
+	require.True(t, ar.Equal(ActiveReceiver{Callsign: "W5CJ"}))
+	require.False(t, ar.Equal(ActiveReceiver{Callsign: "W5CJ", Locator: "EM55"}))
+
+	ac := ActiveCallsign{Callsign: "AG6K", Text: "\n"}
+	require.True(t, ac.Equal(ActiveCallsign{Callsign: "AG6K"}))
+	require.False(t, ac.Equal(ActiveCallsign{Callsign: "AG6K", Reports: "3"}))
+}
@@ -0,0 +1,173 @@
+package pskreporter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sensitiveParams are query parameters left out of a QueryError because they
+// identify the user rather than the query.
+var sensitiveParams = []string{"appcontact"}
+
+// QueryError is returned by Query when a request fails. It records which
+// query failed, with personal details such as the app contact removed. Err
+// says why, and is often one of the error types below, which can be checked
+// for with errors.As and errors.Is.
+type QueryError struct {
+	Host   string
+	Params url.Values
+	Err    error
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("query %s?%s: %v", e.Host, e.Params.Encode(), e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// ErrBadOption is wrapped by the errors ClientOptions and QueryOptions return
+// when they are given invalid values.
+var ErrBadOption = errors.New("bad option")
+
+// optionError is an invalid option error, which is ErrBadOption as well as
+// the error describing the problem.
+type optionError struct {
+	err error
+}
+
+// badOption marks err as an invalid option error.
+func badOption(err error) error {
+	return &optionError{err: err}
+}
+
+func (e *optionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *optionError) Unwrap() []error {
+	return []error{ErrBadOption, e.err}
+}
+
+// errorBodyLimit is how much of an unsuccessful response's body is kept in
+// an HTTPStatusError.
+const errorBodyLimit = 1024
+
+// HTTPStatusError is returned, wrapped in a QueryError, when the server
+// answers with a status other than 200 OK.
+type HTTPStatusError struct {
+	Code int
+	Body []byte // the start of the response body, as sent
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected http response %d", e.Code)
+}
+
+// DecodeError is returned, wrapped in a QueryError, when a response can't be
+// decoded.
+type DecodeError struct {
+	Offset int64 // bytes into the response where decoding failed
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("decoding response at byte %d: %v", e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// RateLimitError is returned, wrapped in a QueryError, when the server
+// throttles a request with a 429 or 503 response. It unwraps to an
+// HTTPStatusError.
+type RateLimitError struct {
+	StatusCode int
+
+	// RetryAfter is how long the server asked to wait before trying again,
+	// from the Retry-After header, or zero if it didn't say.
+	RetryAfter time.Duration
+
+	status *HTTPStatusError
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("rate limited: http response %d, retry after %s", e.StatusCode, e.RetryAfter)
+	}
+	return fmt.Sprintf("rate limited: http response %d", e.StatusCode)
+}
+
+// Unwrap returns the HTTPStatusError for the response, including its body.
+func (e *RateLimitError) Unwrap() error {
+	if e.status == nil {
+		return &HTTPStatusError{Code: e.StatusCode}
+	}
+	return e.status
+}
+
+// Is reports whether target is ErrThrottled.
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrThrottled
+}
+
+// newRateLimitError returns a RateLimitError wrapping status if resp is a
+// throttling response, or nil otherwise.
+func newRateLimitError(resp *http.Response, status *HTTPStatusError) *RateLimitError {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return nil
+	}
+	return &RateLimitError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), timeNow()),
+		status:     status,
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or an HTTP date, returning zero if it is missing, invalid or in
+// the past.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if secs <= 0 || secs > int64(time.Duration(1<<63-1)/time.Second) {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := t.Sub(now); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// sanitizeParams returns a copy of vals without the sensitive parameters.
+func sanitizeParams(vals url.Values) url.Values {
+	clean := copyValues(vals)
+	for _, k := range sensitiveParams {
+		clean.Del(k)
+	}
+	return clean
+}
+
+func copyValues(vals url.Values) url.Values {
+	c := make(url.Values, len(vals))
+	for k, v := range vals {
+		c[k] = append(v[:0:0], v...)
+	}
+	return c
+}
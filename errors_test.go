@@ -0,0 +1,165 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	c, err := New(WithBaseURL(srv.URL), WithDefaultAppContact("me@example.com"))
+	require.NoError(t, err)
+
+	_, err = c.Query(WithCallsign("AG6K"), WithMode("FT8"))
+	var qe *QueryError
+	require.True(t, errors.As(err, &qe))
+
+	u, _ := url.Parse(srv.URL)
+	require.Equal(t, u.Host, qe.Host)
+	require.Equal(t, url.Values{"callsign": {"AG6K"}, "mode": {"FT8"}}, qe.Params)
+	require.EqualError(t, err, "query "+u.Host+"?callsign=AG6K&mode=FT8: unexpected http response 500")
+	require.NotContains(t, err.Error(), "example.com")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.QueryContext(ctx)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestRateLimitError(t *testing.T) {
+	now := time.Unix(1599163380, 0).UTC()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var (
+		status     int
+		retryAfter string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if retryAfter != "" {
+			w.Header().Set("Retry-After", retryAfter)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte("slow down"))
+	}))
+	defer srv.Close()
+
+	c, err := New(WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	tests := []struct {
+		status     int
+		retryAfter string
+		want       time.Duration
+	}{
+		{http.StatusTooManyRequests, "120", 2 * time.Minute},
+		{http.StatusServiceUnavailable, now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{http.StatusServiceUnavailable, "", 0},
+		{http.StatusServiceUnavailable, "soon", 0},
+		{http.StatusTooManyRequests, "-5", 0},
+		{http.StatusTooManyRequests, now.Add(-time.Minute).Format(http.TimeFormat), 0},
+	}
+	for _, tt := range tests {
+		status, retryAfter = tt.status, tt.retryAfter
+		_, err = c.Query()
+		var rle *RateLimitError
+		require.True(t, errors.As(err, &rle), tt.retryAfter)
+		require.Equal(t, tt.status, rle.StatusCode)
+		require.Equal(t, tt.want, rle.RetryAfter, tt.retryAfter)
+
+		// The HTTPStatusError keeps the response body.
+		var hse *HTTPStatusError
+		require.True(t, errors.As(err, &hse))
+		require.Equal(t, tt.status, hse.Code)
+		require.Equal(t, "slow down", string(hse.Body))
+	}
+	require.EqualError(t, &RateLimitError{StatusCode: 429, RetryAfter: time.Minute}, "rate limited: http response 429, retry after 1m0s")
+	require.EqualError(t, &RateLimitError{StatusCode: 503}, "rate limited: http response 503")
+
+	status = http.StatusInternalServerError
+	_, err = c.Query()
+	var rle *RateLimitError
+	require.False(t, errors.As(err, &rle))
+}
+
+func TestErrorTypes(t *testing.T) {
+	var (
+		status = http.StatusOK
+		body   = "<receptionReports/>"
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	c, err := New(WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	t.Run("http status", func(t *testing.T) {
+		status, body = http.StatusBadRequest, "bad callsign"
+		defer func() { status, body = http.StatusOK, "<receptionReports/>" }()
+
+		_, err := c.Query()
+		var se *HTTPStatusError
+		require.True(t, errors.As(err, &se))
+		require.Equal(t, http.StatusBadRequest, se.Code)
+		require.Equal(t, "bad callsign", string(se.Body))
+
+		status = http.StatusTooManyRequests
+		_, err = c.Query()
+		require.True(t, errors.As(err, &se))
+		require.Equal(t, http.StatusTooManyRequests, se.Code)
+	})
+
+	t.Run("decode", func(t *testing.T) {
+		body = "<receptionReports><receptionReport"
+		defer func() { body = "<receptionReports/>" }()
+
+		for _, query := range []func() error{
+			func() error { _, err := c.Query(); return err },
+			func() error { _, err := c.QueryToSink(context.Background(), &recordingSink{}); return err },
+		} {
+			err := query()
+			var de *DecodeError
+			require.True(t, errors.As(err, &de))
+			require.Greater(t, de.Offset, int64(0))
+			var se *xml.SyntaxError
+			require.True(t, errors.As(err, &se))
+		}
+	})
+
+	t.Run("bad option", func(t *testing.T) {
+		for _, opt := range []ClientOption{
+			WithCacheDuration(-time.Second),
+			WithUserAgent(""),
+			WithIARURegion(IARURegion(9)),
+			WithStation("AG6K", "ZZ99", ""),
+			WithMinQueryInterval(-time.Second),
+			WithProxy("ftp://proxy"),
+		} {
+			_, err := c.With(opt)
+			require.True(t, errors.Is(err, ErrBadOption), err)
+		}
+
+		_, err := c.Query(WithCallsign("AG6K"), WithSenderCallsign("AG6K"))
+		require.True(t, errors.Is(err, ErrBadOption))
+		require.Equal(t, errCallsignExclusive, err)
+		require.EqualError(t, err, "only one of callsign, senderCallsign, or receiverCallsign can be specified at a time")
+
+		_, err = c.Query(WithFrequencyRange(2, 1))
+		require.True(t, errors.Is(err, ErrBadOption))
+	})
+}
@@ -0,0 +1,118 @@
+package pskreporter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeNow is replaced in tests to make relative ages deterministic.
+var timeNow = time.Now
+
+// String renders the spot on one line, receiver first, such as
+// "W5CJ EM55 ← AG6K DM14 14.075MHz FT8 -19dB 3m ago". Empty fields are left
+// out.
+func (s Spot) String() string {
+	parts := []string{s.ReceiverCallsign}
+	if s.ReceiverLocator != "" {
+		parts = append(parts, s.ReceiverLocator)
+	}
+	parts = append(parts, "←", s.SenderCallsign)
+	if s.SenderLocator != "" {
+		parts = append(parts, s.SenderLocator)
+	}
+	if s.Frequency != 0 {
+		parts = append(parts, fmt.Sprintf("%.3fMHz", float64(s.Frequency)/1e6))
+	}
+	if s.Mode != "" {
+		parts = append(parts, s.Mode)
+	}
+	if s.HasSNR {
+		parts = append(parts, fmt.Sprintf("%ddB", s.SNR))
+	}
+	if !s.Time.IsZero() {
+		parts = append(parts, s.RelativeAge())
+	}
+	return strings.Join(parts, " ")
+}
+
+// String renders the report on one line in the same form as Spot.String. If
+// the report's numeric fields can't be parsed they are shown as is.
+func (r ReceptionReport) String() string {
+	if s, err := NewSpot(r); err == nil {
+		return s.String()
+	}
+	parts := []string{r.ReceiverCallsign, r.ReceiverLocator, "←", r.SenderCallsign, r.SenderLocator, r.Frequency, r.Mode, r.SNR}
+	return joinNonEmpty(parts)
+}
+
+// String renders the receiver on one line, such as
+// "W5CJ EM55 FT8 20m,40m WSJT-X v2.2.2". Empty fields are left out.
+func (r ActiveReceiver) String() string {
+	return joinNonEmpty([]string{r.Callsign, r.Locator, r.Mode, r.Bands, r.DecoderSoftware})
+}
+
+func joinNonEmpty(parts []string) string {
+	kept := parts[:0]
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, " ")
+}
+
+// TimeLayout is the layout FormatTime uses, with the zone abbreviation so
+// local and UTC times can't be mistaken for each other.
+const TimeLayout = "2006-01-02 15:04:05 MST"
+
+// TimeIn returns the time the spot was heard in loc. A nil loc means UTC, the
+// zone PSK Reporter's timestamps are in.
+func (s Spot) TimeIn(loc *time.Location) time.Time {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return s.Time.In(loc)
+}
+
+// FormatTime renders the time the spot was heard in loc with TimeLayout
+// followed by its relative age, such as "2020-09-03 15:03:00 CDT (3m ago)".
+// A nil loc means UTC. It returns an empty string if the time isn't known.
+func (s Spot) FormatTime(loc *time.Location) string {
+	if s.Time.IsZero() {
+		return ""
+	}
+	return s.TimeIn(loc).Format(TimeLayout) + " (" + s.RelativeAge() + ")"
+}
+
+// RelativeAge renders how long ago the spot was heard, such as "3m ago". It
+// returns an empty string if the time isn't known.
+func (s Spot) RelativeAge() string {
+	return RelativeAge(s.Time, timeNow())
+}
+
+// RelativeAge renders how long before now t was as a short relative age such
+// as "3m ago", "5h ago" or "3d ago". Times after now are shown as "0s ago",
+// and a zero t as an empty string.
+func RelativeAge(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return formatAge(now.Sub(t))
+}
+
+// formatAge renders d as a short relative age such as "3m ago".
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		if d < 0 {
+			d = 0
+		}
+		return fmt.Sprintf("%ds ago", int(d/time.Second))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d/time.Minute))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d/time.Hour))
+	}
+	return fmt.Sprintf("%dd ago", int(d/(24*time.Hour)))
+}
@@ -0,0 +1,74 @@
+package pskreporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStringers(t *testing.T) {
+	now := time.Unix(1599163380, 0).UTC()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	s := testSpots[0]
+	s.Time = now.Add(-3 * time.Minute)
+	require.Equal(t, "W5CJ EM55db92 ← AG6K DM14cc24 14.075MHz FT8 -19dB 3m ago", s.String())
+	require.Equal(t, "N7HPX ← AG6K 7.075MHz FT8 2s ago", testSpots[1].String())
+
+	rr := ReceptionReport{
+		ReceiverCallsign: "W5CJ",
+		ReceiverLocator:  "EM55",
+		SenderCallsign:   "AG6K",
+		Frequency:        "14075311",
+		FlowStartSeconds: "1599163260",
+		Mode:             "FT8",
+		SNR:              "-12",
+	}
+	require.Equal(t, "W5CJ EM55 ← AG6K 14.075MHz FT8 -12dB 2m ago", rr.String())
+	rr.SNR = "loud"
+	require.Equal(t, "W5CJ EM55 ← AG6K 14075311 FT8 loud", rr.String())
+
+	ar := ActiveReceiver{Callsign: "W5CJ", Locator: "EM55", Mode: "FT8", Bands: "20m,40m", DecoderSoftware: "WSJT-X v2.2.2"}
+	require.Equal(t, "W5CJ EM55 FT8 20m,40m WSJT-X v2.2.2", ar.String())
+	require.Equal(t, "W5CJ", ActiveReceiver{Callsign: "W5CJ"}.String())
+}
+
+func TestFormatAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{-time.Second, "0s ago"},
+		{45 * time.Second, "45s ago"},
+		{90 * time.Second, "1m ago"},
+		{3 * time.Hour, "3h ago"},
+		{47 * time.Hour, "47h ago"},
+		{72 * time.Hour, "3d ago"},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, formatAge(tt.d))
+	}
+}
+
+func TestSpotTime(t *testing.T) {
+	now := time.Unix(1599163380, 0).UTC()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	chicago := time.FixedZone("CDT", -5*60*60)
+	s := Spot{Time: now.Add(-3 * time.Minute)}
+	require.Equal(t, time.UTC, s.TimeIn(nil).Location())
+	require.Equal(t, 15, s.TimeIn(chicago).Hour())
+	require.True(t, s.TimeIn(chicago).Equal(s.Time))
+
+	require.Equal(t, "2020-09-03 20:00:00 UTC (3m ago)", s.FormatTime(nil))
+	require.Equal(t, "2020-09-03 15:00:00 CDT (3m ago)", s.FormatTime(chicago))
+	require.Equal(t, "3m ago", s.RelativeAge())
+
+	require.Empty(t, Spot{}.FormatTime(nil))
+	require.Empty(t, Spot{}.RelativeAge())
+	require.Equal(t, "2h ago", RelativeAge(now.Add(-2*time.Hour).In(chicago), now))
+	require.Equal(t, "0s ago", RelativeAge(now.Add(time.Minute), now))
+}
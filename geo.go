@@ -0,0 +1,102 @@
+package pskreporter
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+const earthRadiusKm = 6371.0
+
+var errInvalidLocator = errors.New("invalid maidenhead locator")
+
+// LatLon is a position in decimal degrees.
+type LatLon struct {
+	Lat float64
+	Lon float64
+}
+
+// ParseLocator converts a 2, 4, 6, or 8 character Maidenhead locator into the
+// position of the center of the grid square it describes.
+func ParseLocator(loc string) (LatLon, error) {
+	loc = strings.ToUpper(strings.TrimSpace(loc))
+	if len(loc) < 2 || len(loc) > 8 || len(loc)%2 != 0 {
+		return LatLon{}, errInvalidLocator
+	}
+
+	lon, lat := -180.0, -90.0
+	lonStep, latStep := 20.0, 10.0
+	for i := 0; i < len(loc); i += 2 {
+		var base, divs byte
+		switch i {
+		case 0:
+			base, divs = 'A', 18
+		case 4:
+			base, divs = 'A', 24
+		default:
+			base, divs = '0', 10
+		}
+
+		if i > 0 {
+			lonStep /= float64(divs)
+			latStep /= float64(divs)
+		}
+
+		x, y := loc[i]-base, loc[i+1]-base
+		if loc[i] < base || loc[i+1] < base || x >= divs || y >= divs {
+			return LatLon{}, errInvalidLocator
+		}
+
+		lon += float64(x) * lonStep
+		lat += float64(y) * latStep
+	}
+
+	return LatLon{Lat: lat + latStep/2, Lon: lon + lonStep/2}, nil
+}
+
+// DistanceTo returns the great-circle distance to o in kilometers.
+func (p LatLon) DistanceTo(o LatLon) float64 {
+	lat1, lat2 := radians(p.Lat), radians(o.Lat)
+	dLat := lat2 - lat1
+	dLon := radians(o.Lon - p.Lon)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BearingTo returns the initial great-circle bearing to o in degrees from true
+// north, in the range [0, 360).
+func (p LatLon) BearingTo(o LatLon) float64 {
+	lat1, lat2 := radians(p.Lat), radians(o.Lat)
+	dLon := radians(o.Lon - p.Lon)
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Mod(degrees(math.Atan2(y, x))+360, 360)
+}
+
+// sunElevation returns the approximate elevation of the sun above the horizon
+// in degrees as seen from p at time t.
+func sunElevation(p LatLon, t time.Time) float64 {
+	// Days since the J2000 epoch.
+	n := float64(t.Unix())/86400 + 2440587.5 - 2451545.0
+
+	meanLon := math.Mod(280.460+0.9856474*n, 360)
+	meanAnomaly := radians(math.Mod(357.528+0.9856003*n, 360))
+	eclipticLon := radians(meanLon + 1.915*math.Sin(meanAnomaly) + 0.020*math.Sin(2*meanAnomaly))
+	obliquity := radians(23.439 - 0.0000004*n)
+
+	decl := math.Asin(math.Sin(obliquity) * math.Sin(eclipticLon))
+	ra := math.Atan2(math.Cos(obliquity)*math.Sin(eclipticLon), math.Cos(eclipticLon))
+
+	gmst := math.Mod(280.46061837+360.98564736629*n, 360)
+	hourAngle := radians(gmst+p.Lon) - ra
+
+	lat := radians(p.Lat)
+	return degrees(math.Asin(math.Sin(lat)*math.Sin(decl) + math.Cos(lat)*math.Cos(decl)*math.Cos(hourAngle)))
+}
+
+func radians(d float64) float64 { return d * math.Pi / 180 }
+func degrees(r float64) float64 { return r * 180 / math.Pi }
@@ -0,0 +1,62 @@
+package pskreporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLocator(t *testing.T) {
+	t.Run("no error", func(t *testing.T) {
+		tests := []struct {
+			loc string
+			lat float64
+			lon float64
+		}{
+			{"FN", 45, -70},
+			{"FN31", 41.5, -73},
+			{"FN31pr", 41.729167, -72.708333},
+			{"fn31PR", 41.729167, -72.708333},
+			{"FN31pr45", 41.73125, -72.7125},
+			{"AA00aa00", -89.997917, -179.995833},
+			{"RR99xx99", 89.997917, 179.995833},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.loc, func(t *testing.T) {
+				p, err := ParseLocator(tt.loc)
+				require.NoError(t, err)
+				require.InDelta(t, tt.lat, p.Lat, 0.000001)
+				require.InDelta(t, tt.lon, p.Lon, 0.000001)
+			})
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, loc := range []string{"", "F", "FN3", "SN31", "FN3a", "FN31yy", "FN31pr4x", "FN31pr45aa"} {
+			t.Run(loc, func(t *testing.T) {
+				_, err := ParseLocator(loc)
+				require.Equal(t, errInvalidLocator, err)
+			})
+		}
+	})
+}
+
+func TestDistanceAndBearing(t *testing.T) {
+	origin := LatLon{}
+
+	require.InDelta(t, 10007.5, origin.DistanceTo(LatLon{Lon: 90}), 0.1)
+	require.InDelta(t, 90, origin.BearingTo(LatLon{Lon: 90}), 0.000001)
+	require.InDelta(t, 0, origin.BearingTo(LatLon{Lat: 10}), 0.000001)
+	require.InDelta(t, 270, origin.BearingTo(LatLon{Lon: -10}), 0.000001)
+	require.Equal(t, 0.0, origin.DistanceTo(origin))
+}
+
+func TestSunElevation(t *testing.T) {
+	equinoxNoon := time.Date(2020, 3, 20, 12, 0, 0, 0, time.UTC)
+
+	require.Greater(t, sunElevation(LatLon{}, equinoxNoon), 85.0)
+	require.InDelta(t, 0, sunElevation(LatLon{Lon: 90}, equinoxNoon), 3)
+	require.Less(t, sunElevation(LatLon{Lon: 180}, equinoxNoon), -85.0)
+}
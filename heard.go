@@ -0,0 +1,220 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultHeardWindow is how far back WhoHearsMe looks when no window is given.
+const DefaultHeardWindow = 15 * time.Minute
+
+// WhoHearsMe returns the stations that have heard callsign within the last
+// window, farthest first. Each receiver appears once, represented by its most
+// recent spot. Spots are enriched with the client's Pipeline, or with the
+// equivalent of DefaultPipeline using the client's IARU region if the client
+// doesn't have one. An empty callsign uses the client's station, and a zero
+// window uses DefaultHeardWindow.
+func (c *Client) WhoHearsMe(ctx context.Context, callsign string, window time.Duration) ([]Spot, error) {
+	callsign, err := c.stationCallsign(callsign)
+	if err != nil {
+		return nil, err
+	}
+	if window == 0 {
+		window = DefaultHeardWindow
+	}
+
+	spots, err := c.querySpots(ctx, c.heardPipeline(),
+		WithSenderCallsign(callsign),
+		WithFlowStartDuration(window),
+		WithReceptionReportsOnly(true),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := make(map[string]int)
+	var receivers []Spot
+	for _, s := range spots {
+		if !strings.EqualFold(s.SenderCallsign, callsign) {
+			continue
+		}
+
+		call := strings.ToUpper(s.ReceiverCallsign)
+		if i, ok := latest[call]; ok {
+			if s.Time.After(receivers[i].Time) {
+				receivers[i] = s
+			}
+			continue
+		}
+		latest[call] = len(receivers)
+		receivers = append(receivers, s)
+	}
+
+	sort.SliceStable(receivers, func(i, j int) bool {
+		return receivers[i].Distance > receivers[j].Distance
+	})
+	return receivers, nil
+}
+
+// heardPipeline returns the client's Pipeline, or the equivalent of
+// DefaultPipeline using the client's IARU region if it doesn't have one.
+func (c *Client) heardPipeline() *Pipeline {
+	if c.pipeline != nil {
+		return c.pipeline
+	}
+	return NewPipeline(RegionBandEnricher(c.region), DistanceEnricher(), ContinentEnricher(), GreylineEnricher())
+}
+
+// Heard returns the spots of callsign being heard within the last window,
+// most recent first. HeardBy returns the spots of what callsign heard. Both
+// ask only for reception reports and drop any not sent or received by
+// callsign, such as those of other stations sharing a prefix. opts narrow the
+// query further, e.g. WithBand. Spots are enriched as in WhoHearsMe, an empty
+// callsign uses the client's station, and a zero window uses
+// DefaultHeardWindow.
+func (c *Client) Heard(ctx context.Context, callsign string, window time.Duration, opts ...QueryOption) ([]Spot, error) {
+	return c.heardSpots(ctx, callsign, window, true, opts)
+}
+
+// HeardBy returns the spots of stations heard by callsign within the last
+// window, most recent first. See Heard.
+func (c *Client) HeardBy(ctx context.Context, callsign string, window time.Duration, opts ...QueryOption) ([]Spot, error) {
+	return c.heardSpots(ctx, callsign, window, false, opts)
+}
+
+// heardSpots implements Heard, if sender is set, and HeardBy.
+func (c *Client) heardSpots(ctx context.Context, callsign string, window time.Duration, sender bool, opts []QueryOption) ([]Spot, error) {
+	callsign, err := c.stationCallsign(callsign)
+	if err != nil {
+		return nil, err
+	}
+	if window == 0 {
+		window = DefaultHeardWindow
+	}
+
+	who := WithReceiverCallsign(callsign)
+	if sender {
+		who = WithSenderCallsign(callsign)
+	}
+	spots, err := c.querySpots(ctx, c.heardPipeline(), append([]QueryOption{
+		who,
+		WithFlowStartDuration(window),
+		WithReceptionReportsOnly(true),
+	}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	matched := spots[:0]
+	for _, s := range spots {
+		call := s.ReceiverCallsign
+		if sender {
+			call = s.SenderCallsign
+		}
+		if strings.EqualFold(call, callsign) {
+			matched = append(matched, s)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].Time.After(matched[j].Time)
+	})
+	return matched, nil
+}
+
+type heardOptions struct {
+	minReceivers int
+	minDistance  float64
+	window       time.Duration
+}
+
+// HeardOption is used to customize the thresholds used by IsBeingHeard.
+type HeardOption func(*heardOptions) error
+
+// WithMinReceivers sets the number of receivers that must have heard the
+// callsign. Defaults to 1.
+func WithMinReceivers(n int) HeardOption {
+	return func(o *heardOptions) error {
+		if n < 1 {
+			return errors.New("minimum receivers must be at least 1")
+		}
+		o.minReceivers = n
+		return nil
+	}
+}
+
+// WithMinDistance only counts receivers at least km kilometers away. Defaults
+// to 0, counting every receiver.
+func WithMinDistance(km float64) HeardOption {
+	return func(o *heardOptions) error {
+		if km < 0 {
+			return errors.New("minimum distance must not be negative")
+		}
+		o.minDistance = km
+		return nil
+	}
+}
+
+// WithHeardWindow sets how far back to look for reception reports. Defaults to
+// DefaultHeardWindow.
+func WithHeardWindow(d time.Duration) HeardOption {
+	return func(o *heardOptions) error {
+		if d <= 0 {
+			return errors.New("heard window must be positive")
+		}
+		o.window = d
+		return nil
+	}
+}
+
+// Evidence explains the result of IsBeingHeard.
+type Evidence struct {
+	// Receivers are the receivers that met the distance threshold, farthest
+	// first.
+	Receivers []Spot
+
+	// MaxDistance is the distance to the farthest receiver in kilometers.
+	MaxDistance float64
+
+	// LastHeard is the time of the most recent qualifying spot.
+	LastHeard time.Time
+}
+
+// IsBeingHeard reports whether callsign has recently been heard by enough
+// receivers far enough away, which makes it suitable as a health check for an
+// unattended transmitter. Evidence describes the receivers that counted
+// towards the result. An empty callsign uses the client's station.
+func (c *Client) IsBeingHeard(ctx context.Context, callsign string, opts ...HeardOption) (bool, Evidence, error) {
+	o := heardOptions{
+		minReceivers: 1,
+		window:       DefaultHeardWindow,
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return false, Evidence{}, err
+		}
+	}
+
+	spots, err := c.WhoHearsMe(ctx, callsign, o.window)
+	if err != nil {
+		return false, Evidence{}, err
+	}
+
+	var ev Evidence
+	for _, s := range spots {
+		if s.Distance < o.minDistance {
+			continue
+		}
+		ev.Receivers = append(ev.Receivers, s)
+		if s.Distance > ev.MaxDistance {
+			ev.MaxDistance = s.Distance
+		}
+		if s.Time.After(ev.LastHeard) {
+			ev.LastHeard = s.Time
+		}
+	}
+
+	return len(ev.Receivers) >= o.minReceivers, ev, nil
+}
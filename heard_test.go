@@ -0,0 +1,166 @@
+package pskreporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testHeardXML = `<?xml version="1.0"?>
+<receptionReports currentSeconds="1599164934">
+  <receptionReport receiverCallsign="W5CJ" receiverLocator="EM55db92" senderCallsign="AG6K" senderLocator="DM14cc24" frequency="14075311" flowStartSeconds="1599163380" mode="FT8" isSender="1" sNR="-19" />
+  <receptionReport receiverCallsign="N7HPX" receiverLocator="DN13VJ" senderCallsign="AG6K" senderLocator="DM14cc24" frequency="14075301" flowStartSeconds="1599163378" mode="FT8" isSender="1" sNR="-11" />
+  <receptionReport receiverCallsign="W5CJ" receiverLocator="EM55db92" senderCallsign="AG6K" senderLocator="DM14cc24" frequency="7075311" flowStartSeconds="1599163980" mode="FT8" isSender="1" sNR="-5" />
+  <receptionReport receiverCallsign="JA1XYZ" receiverLocator="PM95" senderCallsign="AG6K" senderLocator="DM14cc24" frequency="14075311" flowStartSeconds="1599163000" mode="FT8" isSender="1" sNR="-20" />
+  <receptionReport receiverCallsign="AG6K" receiverLocator="DM14cc24" senderCallsign="K1ABC" senderLocator="FN31" frequency="14075311" flowStartSeconds="1599163000" mode="FT8" sNR="-20" />
+</receptionReports>`
+
+func newHeardServer(t *testing.T, check func(*http.Request)) *httptest.Server {
+	t.Helper()
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if check != nil {
+			check(req)
+		}
+		w.Write([]byte(testHeardXML))
+	}))
+	t.Cleanup(svr.Close)
+	return svr
+}
+
+func TestWhoHearsMe(t *testing.T) {
+	var query map[string][]string
+	svr := newHeardServer(t, func(req *http.Request) {
+		query = req.URL.Query()
+	})
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	spots, err := c.WhoHearsMe(context.Background(), "ag6k", 0)
+	require.NoError(t, err)
+
+	require.Equal(t, []string{"ag6k"}, query["senderCallsign"])
+	require.Equal(t, []string{"-900"}, query["flowStartSeconds"])
+	require.Equal(t, []string{"1"}, query["rronly"])
+
+	require.Len(t, spots, 3)
+	require.Equal(t, "JA1XYZ", spots[0].ReceiverCallsign)
+	require.Equal(t, "W5CJ", spots[1].ReceiverCallsign)
+	require.Equal(t, Band40m, spots[1].Band) // most recent W5CJ spot
+	require.Equal(t, "N7HPX", spots[2].ReceiverCallsign)
+	require.Greater(t, spots[0].Distance, spots[1].Distance)
+	require.Greater(t, spots[1].Distance, spots[2].Distance)
+
+	t.Run("custom window", func(t *testing.T) {
+		_, err := c.WhoHearsMe(context.Background(), "AG6K", time.Hour)
+		require.NoError(t, err)
+		require.Equal(t, []string{"-3600"}, query["flowStartSeconds"])
+	})
+
+	t.Run("window too large", func(t *testing.T) {
+		_, err := c.WhoHearsMe(context.Background(), "AG6K", 48*time.Hour)
+		require.Equal(t, errFlowStartDurationGreaterDay, err)
+	})
+
+	t.Run("negative window", func(t *testing.T) {
+		_, err := c.WhoHearsMe(context.Background(), "AG6K", -time.Minute)
+		require.Equal(t, errFlowStartDurationNotPositive, err)
+	})
+
+	t.Run("canceled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := c.WhoHearsMe(ctx, "AG6K", 0)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestHeard(t *testing.T) {
+	var query map[string][]string
+	svr := newHeardServer(t, func(req *http.Request) {
+		query = req.URL.Query()
+	})
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	spots, err := c.Heard(context.Background(), "ag6k", 0, WithMode("FT8"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"ag6k"}, query["senderCallsign"])
+	require.Equal(t, []string{"-900"}, query["flowStartSeconds"])
+	require.Equal(t, []string{"1"}, query["rronly"])
+	require.Equal(t, []string{"FT8"}, query["mode"])
+
+	require.Len(t, spots, 4)
+	require.Equal(t, Band40m, spots[0].Band) // most recent first
+	require.Equal(t, "JA1XYZ", spots[3].ReceiverCallsign)
+	for i := 1; i < len(spots); i++ {
+		require.False(t, spots[i].Time.After(spots[i-1].Time))
+	}
+
+	spots, err = c.HeardBy(context.Background(), "AG6K", time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []string{"AG6K"}, query["receiverCallsign"])
+	require.Nil(t, query["senderCallsign"])
+	require.Equal(t, []string{"-3600"}, query["flowStartSeconds"])
+	require.Len(t, spots, 1)
+	require.Equal(t, "K1ABC", spots[0].SenderCallsign)
+
+	_, err = c.HeardBy(context.Background(), "", 0)
+	require.Equal(t, errNoStation, err)
+
+	c, err = c.With(WithStation("AG6K", "DM14cc24", ""))
+	require.NoError(t, err)
+	spots, err = c.HeardBy(context.Background(), "", 0)
+	require.NoError(t, err)
+	require.Len(t, spots, 1)
+}
+
+func TestIsBeingHeard(t *testing.T) {
+	svr := newHeardServer(t, nil)
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	t.Run("defaults", func(t *testing.T) {
+		heard, ev, err := c.IsBeingHeard(context.Background(), "AG6K")
+		require.NoError(t, err)
+		require.True(t, heard)
+		require.Len(t, ev.Receivers, 3)
+		require.Equal(t, ev.Receivers[0].Distance, ev.MaxDistance)
+		require.Equal(t, time.Unix(1599163980, 0).UTC(), ev.LastHeard)
+	})
+
+	t.Run("thresholds met", func(t *testing.T) {
+		heard, ev, err := c.IsBeingHeard(context.Background(), "AG6K",
+			WithMinReceivers(2),
+			WithMinDistance(2000),
+			WithHeardWindow(time.Hour),
+		)
+		require.NoError(t, err)
+		require.True(t, heard)
+		require.Len(t, ev.Receivers, 2)
+	})
+
+	t.Run("thresholds not met", func(t *testing.T) {
+		heard, ev, err := c.IsBeingHeard(context.Background(), "AG6K",
+			WithMinReceivers(2),
+			WithMinDistance(5000),
+		)
+		require.NoError(t, err)
+		require.False(t, heard)
+		require.Len(t, ev.Receivers, 1)
+		require.Equal(t, "JA1XYZ", ev.Receivers[0].ReceiverCallsign)
+	})
+
+	t.Run("bad options", func(t *testing.T) {
+		for _, opt := range []HeardOption{WithMinReceivers(0), WithMinDistance(-1), WithHeardWindow(0)} {
+			_, _, err := c.IsBeingHeard(context.Background(), "AG6K", opt)
+			require.Error(t, err)
+		}
+	})
+}
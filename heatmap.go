@@ -0,0 +1,128 @@
+package pskreporter
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"time"
+)
+
+type heatmapOptions struct {
+	scale      int
+	start, end time.Time
+}
+
+// HeatmapOption is used to customize the output of RenderHeatmap.
+type HeatmapOption func(*heatmapOptions) error
+
+// WithHeatmapScale sets the number of pixels per degree of latitude. Each grid
+// square is drawn 2*scale pixels wide and scale pixels tall.
+func WithHeatmapScale(scale int) HeatmapOption {
+	return func(o *heatmapOptions) error {
+		if scale <= 0 {
+			return errors.New("heatmap scale must be positive")
+		}
+		o.scale = scale
+		return nil
+	}
+}
+
+var errHeatmapWindow = errors.New("heatmap window start must be before end")
+
+// WithHeatmapWindow limits the heatmap to spots with a time in [start, end).
+func WithHeatmapWindow(start, end time.Time) HeatmapOption {
+	return func(o *heatmapOptions) error {
+		if !start.Before(end) {
+			return errHeatmapWindow
+		}
+		o.start, o.end = start, end
+		return nil
+	}
+}
+
+var heatmapBackground = color.RGBA{0x0b, 0x1a, 0x2b, 0xff}
+
+// RenderHeatmap writes a PNG image showing how many spots were received in each
+// 4 character grid square. The image is an equirectangular world map, and the
+// color of each square is scaled logarithmically from blue for the fewest
+// spots to red for the most. Spots without a valid receiver locator are
+// skipped.
+func RenderHeatmap(w io.Writer, spots []Spot, opts ...HeatmapOption) error {
+	o := heatmapOptions{scale: 3}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return err
+		}
+	}
+
+	// There are 180x180 grid squares, each 2 degrees of longitude by 1 degree
+	// of latitude.
+	var counts [180][180]int
+	most := 0
+	for _, s := range spots {
+		if !o.start.IsZero() && (s.Time.Before(o.start) || !s.Time.Before(o.end)) {
+			continue
+		}
+
+		p, err := ParseLocator(s.ReceiverLocator)
+		if err != nil {
+			continue
+		}
+
+		col := int((p.Lon + 180) / 2)
+		row := int(90 - p.Lat)
+		counts[col][row]++
+		if counts[col][row] > most {
+			most = counts[col][row]
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 360*o.scale, 180*o.scale))
+	for y := 0; y < img.Bounds().Dy(); y++ {
+		for x := 0; x < img.Bounds().Dx(); x++ {
+			img.SetRGBA(x, y, heatmapBackground)
+		}
+	}
+
+	for col := range counts {
+		for row, n := range counts[col] {
+			if n == 0 {
+				continue
+			}
+			c := heatColor(math.Log1p(float64(n)) / math.Log1p(float64(most)))
+			for y := row * o.scale; y < (row+1)*o.scale; y++ {
+				for x := col * 2 * o.scale; x < (col+1)*2*o.scale; x++ {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+// heatColor maps f in [0, 1] onto a blue -> green -> yellow -> red ramp.
+func heatColor(f float64) color.RGBA {
+	stops := []color.RGBA{
+		{0x30, 0x60, 0xff, 0xff},
+		{0x30, 0xd0, 0x60, 0xff},
+		{0xff, 0xe0, 0x30, 0xff},
+		{0xff, 0x30, 0x20, 0xff},
+	}
+
+	pos := f * float64(len(stops)-1)
+	i := int(pos)
+	if i >= len(stops)-1 {
+		return stops[len(stops)-1]
+	}
+
+	frac := pos - float64(i)
+	lerp := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*frac)
+	}
+	a, b := stops[i], stops[i+1]
+	return color.RGBA{lerp(a.R, b.R), lerp(a.G, b.G), lerp(a.B, b.B), 0xff}
+}
@@ -0,0 +1,69 @@
+package pskreporter
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderHeatmap(t *testing.T) {
+	spots := []Spot{
+		{ReceiverLocator: "EM55db92", Time: time.Unix(100, 0)},
+		{ReceiverLocator: "EM55", Time: time.Unix(200, 0)},
+		{ReceiverLocator: "JO63HM", Time: time.Unix(300, 0)},
+		{ReceiverLocator: "", Time: time.Unix(300, 0)},
+	}
+
+	// EM55 covers longitude -90 to -88 and latitude 35 to 36, JO63 covers
+	// longitude 12 to 14 and latitude 53 to 54.
+	em55X, em55Y := 45*2*2, 54*2
+	jo63X, jo63Y := 96*2*2, 36*2
+
+	t.Run("all spots", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, RenderHeatmap(&buf, spots, WithHeatmapScale(2)))
+
+		img, err := png.Decode(&buf)
+		require.NoError(t, err)
+		require.Equal(t, 720, img.Bounds().Dx())
+		require.Equal(t, 360, img.Bounds().Dy())
+
+		require.Equal(t, heatColor(1), pixel(img, em55X, em55Y))
+		require.Equal(t, heatColor(1), pixel(img, em55X+3, em55Y+1))
+		require.Equal(t, heatColor(math.Log1p(1)/math.Log1p(2)), pixel(img, jo63X, jo63Y))
+		require.Equal(t, heatmapBackground, pixel(img, 0, 0))
+	})
+
+	t.Run("window", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, RenderHeatmap(&buf, spots, WithHeatmapScale(2), WithHeatmapWindow(time.Unix(150, 0), time.Unix(300, 0))))
+
+		img, err := png.Decode(&buf)
+		require.NoError(t, err)
+		require.Equal(t, heatColor(1), pixel(img, em55X, em55Y))
+		require.Equal(t, heatmapBackground, pixel(img, jo63X, jo63Y))
+	})
+
+	t.Run("bad options", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.Error(t, RenderHeatmap(&buf, spots, WithHeatmapScale(0)))
+		require.Equal(t, errHeatmapWindow, RenderHeatmap(&buf, spots, WithHeatmapWindow(time.Unix(1, 0), time.Unix(1, 0))))
+	})
+}
+
+func TestHeatColor(t *testing.T) {
+	require.Equal(t, uint8(0x30), heatColor(0).R)
+	require.Equal(t, uint8(0xff), heatColor(0).B)
+	require.Equal(t, uint8(0xff), heatColor(1).R)
+	require.Equal(t, uint8(0x20), heatColor(1).B)
+}
+
+func pixel(img image.Image, x, y int) color.RGBA {
+	return color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+}
@@ -0,0 +1,294 @@
+package pskreporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errADIFTag = errors.New("malformed ADIF tag")
+
+// ReadJSONL reads spots written by a JSONLSink.
+func ReadJSONL(r io.Reader) ([]Spot, error) {
+	var spots []Spot
+	dec := json.NewDecoder(r)
+	for {
+		var s Spot
+		if err := dec.Decode(&s); err == io.EOF {
+			return spots, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("decoding spot %d: %w", len(spots)+1, err)
+		}
+		spots = append(spots, s)
+	}
+}
+
+// ReadCSV reads spots written by a CSVSink. Columns are matched by the names
+// in the header row, so files with reordered or missing columns are accepted.
+func ReadCSV(r io.Reader) ([]Spot, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[name] = i
+	}
+	field := func(rec []string, name string) string {
+		if i, ok := cols[name]; ok && i < len(rec) {
+			return rec[i]
+		}
+		return ""
+	}
+
+	var spots []Spot
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return spots, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		s := Spot{
+			SenderCallsign:   field(rec, "senderCallsign"),
+			SenderLocator:    field(rec, "senderLocator"),
+			ReceiverCallsign: field(rec, "receiverCallsign"),
+			ReceiverLocator:  field(rec, "receiverLocator"),
+			Band:             Band(field(rec, "band")),
+			Mode:             field(rec, "mode"),
+		}
+		if v := field(rec, "time"); v != "" {
+			if s.Time, err = time.Parse(time.RFC3339, v); err != nil {
+				return nil, fmt.Errorf("line %d: parsing time %q: %w", line, v, err)
+			}
+		}
+		if v := field(rec, "frequency"); v != "" {
+			if s.Frequency, err = parseFrequency(v); err != nil {
+				return nil, fmt.Errorf("line %d: parsing frequency %q: %w", line, v, err)
+			}
+		}
+		if v := field(rec, "snr"); v != "" {
+			if s.SNR, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("line %d: parsing snr %q: %w", line, v, err)
+			}
+			s.HasSNR = true
+		}
+		if v := field(rec, "distance"); v != "" {
+			if s.Distance, err = strconv.ParseFloat(v, 64); err != nil {
+				return nil, fmt.Errorf("line %d: parsing distance %q: %w", line, v, err)
+			}
+		}
+		spots = append(spots, s)
+	}
+}
+
+// ReadADIF reads spots from the QSO records of an ADIF file. The logging
+// station (STATION_CALLSIGN, or OPERATOR) is taken as the sender and the
+// worked station (CALL) as the receiver, with RST_RCVD as the signal report
+// when it is a plain dB value.
+func ReadADIF(r io.Reader) ([]Spot, error) {
+	var spots []Spot
+	err := readADIF(r, func(fields map[string]string) error {
+		s, err := adifSpot(fields)
+		if err != nil {
+			return err
+		}
+		spots = append(spots, s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return spots, nil
+}
+
+// readADIF passes the fields of each record in an ADIF file to record, keyed
+// by upper case field name.
+func readADIF(r io.Reader, record func(fields map[string]string) error) error {
+	br := bufio.NewReader(r)
+	fields := make(map[string]string)
+	for n := 1; ; {
+		// Skip any text up to the next tag.
+		if _, err := br.ReadString('<'); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		tag, err := br.ReadString('>')
+		if err != nil {
+			return errADIFTag
+		}
+		parts := strings.Split(strings.TrimSuffix(tag, ">"), ":")
+		name := strings.ToUpper(parts[0])
+
+		switch {
+		case name == "EOH":
+			fields = make(map[string]string)
+			continue
+		case name == "EOR":
+			if err := record(fields); err != nil {
+				return fmt.Errorf("record %d: %w", n, err)
+			}
+			n++
+			fields = make(map[string]string)
+			continue
+		case len(parts) < 2:
+			return fmt.Errorf("%w: %q", errADIFTag, tag)
+		}
+
+		size, err := strconv.Atoi(parts[1])
+		if err != nil || size < 0 {
+			return fmt.Errorf("%w: %q", errADIFTag, tag)
+		}
+		// The buffer grows as the value is read, so a bogus length can't
+		// allocate more than the input holds.
+		var val strings.Builder
+		if _, err := io.CopyN(&val, br, int64(size)); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return fmt.Errorf("reading ADIF field %s: %w", name, err)
+		}
+		fields[name] = val.String()
+	}
+}
+
+func adifSpot(fields map[string]string) (Spot, error) {
+	s := Spot{
+		SenderCallsign:   fields["STATION_CALLSIGN"],
+		SenderLocator:    fields["MY_GRIDSQUARE"],
+		ReceiverCallsign: fields["CALL"],
+		ReceiverLocator:  fields["GRIDSQUARE"],
+		Mode:             fields["MODE"],
+		Band:             Band(strings.ToLower(fields["BAND"])),
+	}
+	if s.SenderCallsign == "" {
+		s.SenderCallsign = fields["OPERATOR"]
+	}
+
+	var err error
+	if s.Frequency, err = adifFrequency(fields); err != nil {
+		return Spot{}, err
+	}
+	if s.Time, err = adifTime(fields); err != nil {
+		return Spot{}, err
+	}
+
+	if snr, err := strconv.Atoi(strings.TrimSpace(fields["RST_RCVD"])); err == nil {
+		s.SNR, s.HasSNR = snr, true
+	}
+	return s, nil
+}
+
+// adifFrequency returns the FREQ field of a record in Hz, or zero if it is
+// missing.
+func adifFrequency(fields map[string]string) (int64, error) {
+	v := fields["FREQ"]
+	if v == "" {
+		return 0, nil
+	}
+	mhz, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing frequency %q: %w", v, err)
+	}
+	hz, ok := mhzToHz(mhz)
+	if !ok {
+		return 0, fmt.Errorf("parsing frequency %q: %w", v, strconv.ErrRange)
+	}
+	return hz, nil
+}
+
+// adifTime returns the QSO_DATE and TIME_ON fields of a record as a time, or
+// the zero time if the date is missing.
+func adifTime(fields map[string]string) (time.Time, error) {
+	date := fields["QSO_DATE"]
+	if date == "" {
+		return time.Time{}, nil
+	}
+	clock := fields["TIME_ON"]
+	layout := "20060102150405"
+	if len(clock) == 4 {
+		layout = "200601021504"
+	}
+	t, err := time.Parse(layout, date+clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing time %q: %w", date+clock, err)
+	}
+	return t, nil
+}
+
+// Import puts spots into s, skipping any that duplicate a spot already in the
+// store or earlier in spots. Spots are duplicates if they have the same time,
+// sender, receiver, frequency and mode. It returns how many were imported.
+func Import(ctx context.Context, s Store, spots []Spot) (int, error) {
+	if len(spots) == 0 {
+		return 0, nil
+	}
+
+	start, end := spots[0].Time, spots[0].Time
+	for _, spot := range spots[1:] {
+		if spot.Time.Before(start) {
+			start = spot.Time
+		}
+		if spot.Time.After(end) {
+			end = spot.Time
+		}
+	}
+	existing, err := s.QueryByTimeRange(ctx, start, end.Add(time.Nanosecond))
+	if err != nil {
+		return 0, err
+	}
+
+	seen := make(map[importKey]bool, len(existing)+len(spots))
+	for _, spot := range existing {
+		seen[newImportKey(spot)] = true
+	}
+	var fresh []Spot
+	for _, spot := range spots {
+		k := newImportKey(spot)
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		fresh = append(fresh, spot)
+	}
+
+	if len(fresh) == 0 {
+		return 0, nil
+	}
+	if err := s.Put(ctx, fresh); err != nil {
+		return 0, err
+	}
+	return len(fresh), nil
+}
+
+type importKey struct {
+	time      int64
+	sender    string
+	receiver  string
+	frequency int64
+	mode      string
+}
+
+func newImportKey(s Spot) importKey {
+	return importKey{
+		time:      s.Time.Unix(),
+		sender:    strings.ToUpper(s.SenderCallsign),
+		receiver:  strings.ToUpper(s.ReceiverCallsign),
+		frequency: s.Frequency,
+		mode:      strings.ToUpper(s.Mode),
+	}
+}
@@ -0,0 +1,129 @@
+package pskreporter
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadJSONL(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewJSONLSink(&buf)
+	require.NoError(t, s.Write(context.Background(), testSpots))
+
+	spots, err := ReadJSONL(&buf)
+	require.NoError(t, err)
+	require.Equal(t, testSpots, spots)
+
+	_, err = ReadJSONL(strings.NewReader("{\"frequency\":"))
+	require.Error(t, err)
+}
+
+func TestReadCSV(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewCSVSink(&buf)
+	require.NoError(t, s.Write(context.Background(), testSpots))
+	require.NoError(t, s.Flush())
+
+	spots, err := ReadCSV(&buf)
+	require.NoError(t, err)
+	require.Equal(t, testSpots, spots)
+
+	spots, err = ReadCSV(strings.NewReader("mode,senderCallsign\nWSPR,AG6K\n"))
+	require.NoError(t, err)
+	require.Equal(t, []Spot{{SenderCallsign: "AG6K", Mode: "WSPR"}}, spots)
+
+	_, err = ReadCSV(strings.NewReader("frequency\nabc\n"))
+	require.EqualError(t, err, `line 2: parsing frequency "abc": strconv.ParseInt: parsing "abc": invalid syntax`)
+}
+
+func TestReadADIF(t *testing.T) {
+	adif := `Exported log
+<ADIF_VER:5>3.1.0 <PROGRAMID:6>WSJT-X <EOH>
+<call:4>W5CJ <gridsquare:4>EM55 <mode:3>FT8 <rst_sent:3>-10 <rst_rcvd:3>-19
+<qso_date:8>20200903 <time_on:6>200300 <band:3>20M <freq:9>14.075311
+<station_callsign:4>AG6K <my_gridsquare:6>DM14cc <eor>
+<CALL:5>N7HPX <MODE:2>CW <RST_RCVD:3>599 <QSO_DATE:8>20200903 <TIME_ON:4>2002
+<OPERATOR:4>AG6K <EOR>
+`
+	spots, err := ReadADIF(strings.NewReader(adif))
+	require.NoError(t, err)
+	require.Equal(t, []Spot{
+		{
+			SenderCallsign:   "AG6K",
+			SenderLocator:    "DM14cc",
+			ReceiverCallsign: "W5CJ",
+			ReceiverLocator:  "EM55",
+			Frequency:        14075311,
+			Band:             Band20m,
+			Mode:             "FT8",
+			SNR:              -19,
+			HasSNR:           true,
+			Time:             time.Date(2020, 9, 3, 20, 3, 0, 0, time.UTC),
+		},
+		{
+			SenderCallsign:   "AG6K",
+			ReceiverCallsign: "N7HPX",
+			Mode:             "CW",
+			SNR:              599,
+			HasSNR:           true,
+			Time:             time.Date(2020, 9, 3, 20, 2, 0, 0, time.UTC),
+		},
+	}, spots)
+
+	tests := []struct {
+		desc string
+		adif string
+	}{
+		{"no length", "<CALL>W5CJ<EOR>"},
+		{"bad length", "<CALL:x>W5CJ<EOR>"},
+		{"unterminated tag", "<CALL:4"},
+		{"short value", "<CALL:10>W5CJ"},
+		{"bad frequency", "<FREQ:3>abc<EOR>"},
+		{"infinite frequency", "<FREQ:3>Inf<EOR>"},
+		{"huge frequency", "<FREQ:4>1e30<EOR>"},
+		{"negative frequency", "<FREQ:2>-1<EOR>"},
+		{"huge length", "<CALL:9223372036854775807>W5CJ<EOR>"},
+		{"length overflow", "<CALL:99999999999999999999>W5CJ<EOR>"},
+		{"bad time", "<QSO_DATE:8>20200903<TIME_ON:2>20<EOR>"},
+	}
+	t.Run("microwave frequency", func(t *testing.T) {
+		spots, err := ReadADIF(strings.NewReader("<FREQ:11>241000.0001<EOR>"))
+		require.NoError(t, err)
+		require.Equal(t, int64(241000000100), spots[0].Frequency)
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			_, err := ReadADIF(strings.NewReader(tt.adif))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	n, err := Import(ctx, s, testSpots[:1])
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	dup := testSpots[0]
+	dup.SenderCallsign = "ag6k"
+	n, err = Import(ctx, s, []Spot{dup, testSpots[1], testSpots[1]})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	n, err = Import(ctx, s, testSpots)
+	require.NoError(t, err)
+	require.Zero(t, n)
+
+	spots, err := s.QueryByTimeRange(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, spots, 2)
+}
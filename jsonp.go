@@ -0,0 +1,189 @@
+package pskreporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+)
+
+// ResponseFormat is the format a client asks PSK Reporter to answer queries
+// in. Either way queries produce the same Response.
+type ResponseFormat int
+
+const (
+	// FormatXML is the default, and the format the API documents.
+	FormatXML ResponseFormat = iota
+
+	// FormatJSON asks for JSONP, by sending the callback parameter.
+	FormatJSON
+)
+
+// jsonpCallback is the callback parameter sent with queries asking for JSON.
+const jsonpCallback = "pskreporter"
+
+// WithResponseFormat sets the format the client asks for responses in.
+// Defaults to FormatXML. With FormatJSON the raw bodies returned by
+// QueryWithRaw are JSONP, partial results aren't recovered, and QueryToSink
+// still asks for XML so that it can decode reports as they arrive.
+func WithResponseFormat(f ResponseFormat) ClientOption {
+	return func(o *clientOptions) error {
+		if f != FormatXML && f != FormatJSON {
+			return badOption(fmt.Errorf("unknown response format %d", f))
+		}
+		o.format = f
+		return nil
+	}
+}
+
+// decode decodes a response in the client's format, keeping what lim allows.
+func (c *Client) decode(b []byte, lim decodeLimits) (*Response, error) {
+	if c.format == FormatJSON {
+		r, err := decodeJSONResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		lim.apply(r)
+		return r, nil
+	}
+	return decodeLimited(b, lim)
+}
+
+// jsonResponse is the JSON form of a response, whose elements are objects
+// with the same names as the XML's attributes.
+type jsonResponse struct {
+	CurrentSeconds      jsonValue    `json:"currentSeconds"`
+	ActiveReceivers     []jsonObject `json:"activeReceiver"`
+	ReceptionReports    []jsonObject `json:"receptionReport"`
+	ActiveCallsigns     []jsonObject `json:"activeCallsign"`
+	SenderSearch        jsonElement  `json:"senderSearch"`
+	LastSequenceNumber  jsonElement  `json:"lastSequenceNumber"`
+	MaxFlowStartSeconds jsonElement  `json:"maxFlowStartSeconds"`
+}
+
+// jsonObject is an element of a JSON response.
+type jsonObject map[string]jsonValue
+
+// jsonElement is an element that appears at most once, which may be written
+// as an object or an array holding one.
+type jsonElement struct {
+	jsonObject
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (e *jsonElement) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '[' {
+		var objs []jsonObject
+		if err := json.Unmarshal(b, &objs); err != nil {
+			return err
+		}
+		if len(objs) > 0 {
+			e.jsonObject = objs[0]
+		}
+		return nil
+	}
+	return json.Unmarshal(b, &e.jsonObject)
+}
+
+// jsonValue is a JSON string, number or boolean held as the text the XML
+// response would have. Objects, arrays and nulls are ignored.
+type jsonValue string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (v *jsonValue) UnmarshalJSON(b []byte) error {
+	switch {
+	case len(b) == 0:
+		return nil
+	case b[0] == '"':
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*v = jsonValue(s)
+	case string(b) == "true":
+		*v = "1"
+	case string(b) == "false":
+		*v = "0"
+	case b[0] == '-' || (b[0] >= '0' && b[0] <= '9'):
+		*v = jsonValue(b)
+	}
+	return nil
+}
+
+// decodeJSONResponse decodes a JSON or JSONP query response into the same
+// Response the XML one decodes to, with errors wrapped in a DecodeError.
+func decodeJSONResponse(b []byte) (*Response, error) {
+	body, offset, err := unwrapJSONP(b)
+	if err != nil {
+		return nil, &DecodeError{Offset: offset, Err: err}
+	}
+
+	var jr jsonResponse
+	if err := json.Unmarshal(body, &jr); err != nil {
+		var se *json.SyntaxError
+		var te *json.UnmarshalTypeError
+		switch {
+		case errors.As(err, &se):
+			offset += se.Offset
+		case errors.As(err, &te):
+			offset += te.Offset
+		}
+		return nil, &DecodeError{Offset: offset, Err: err}
+	}
+
+	r := &Response{
+		XMLName:        xml.Name{Local: "receptionReports"},
+		CurrentSeconds: string(jr.CurrentSeconds),
+	}
+	for _, obj := range jr.ActiveReceivers {
+		var v ActiveReceiver
+		for k, val := range obj {
+			if f, ok := activeReceiverAttrs[k]; ok {
+				*f(&v) = string(val)
+			}
+		}
+		r.ActiveReceivers = append(r.ActiveReceivers, v)
+	}
+	for _, obj := range jr.ReceptionReports {
+		var v ReceptionReport
+		for k, val := range obj {
+			if f, ok := receptionReportAttrs[k]; ok {
+				*f(&v) = string(val)
+			}
+		}
+		r.ReceptionReports = append(r.ReceptionReports, v)
+	}
+	for _, obj := range jr.ActiveCallsigns {
+		var v ActiveCallsign
+		for k, val := range obj {
+			if f, ok := activeCallsignAttrs[k]; ok {
+				*f(&v) = string(val)
+			}
+		}
+		r.ActiveCallsigns = append(r.ActiveCallsigns, v)
+	}
+	r.SenderSearch.Callsign = string(jr.SenderSearch.jsonObject["callsign"])
+	r.SenderSearch.RecentFlowStartSeconds = string(jr.SenderSearch.jsonObject["recentFlowStartSeconds"])
+	r.LastSequenceNumber.Value = string(jr.LastSequenceNumber.jsonObject["value"])
+	r.MaxFlowStartSeconds.Value = string(jr.MaxFlowStartSeconds.jsonObject["value"])
+	return r, nil
+}
+
+// unwrapJSONP returns the JSON inside a JSONP response such as
+// `callback({...});`, and its offset in b. Plain JSON is returned as it is.
+func unwrapJSONP(b []byte) ([]byte, int64, error) {
+	trimmed := bytes.TrimSpace(b)
+	start := int64(len(b) - len(bytes.TrimLeft(b, " \t\r\n")))
+	if len(trimmed) > 0 && trimmed[0] == '{' {
+		return trimmed, start, nil
+	}
+
+	open := bytes.IndexByte(trimmed, '(')
+	trimmed = bytes.TrimSuffix(trimmed, []byte(";"))
+	trimmed = bytes.TrimRight(trimmed, " \t\r\n")
+	if open < 0 || len(trimmed) == 0 || trimmed[len(trimmed)-1] != ')' {
+		return nil, start, errors.New("response is neither JSON nor JSONP")
+	}
+	return trimmed[open+1 : len(trimmed)-1], start + int64(open) + 1, nil
+}
@@ -0,0 +1,93 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testJSONP = `pskreporter({"currentSeconds":1599164934,
+"receptionReport":[{"receiverCallsign":"W5CJ","receiverLocator":"EM10","senderCallsign":"AG6K","senderLocator":"DM04","frequency":14074000,"flowStartSeconds":1599161463,"mode":"FT8","isSender":1,"receiverDXCC":"United States","receiverDXCCCode":"K","sNR":-10}],
+"activeReceiver":[{"callsign":"W5CJ","locator":"EM10","frequency":14074870,"DXCC":"United States","mode":"FT8","bands":"20m,40m","extra":{"ignored":true}}],
+"activeCallsign":[{"callsign":"AG6K","reports":12,"DXCC":"United States","DXCCcode":"K","frequency":14074000}],
+"senderSearch":[{"callsign":"AG6K","recentFlowStartSeconds":1599164900}],
+"lastSequenceNumber":{"value":14631964162},
+"maxFlowStartSeconds":{"value":1599164934}});
+`
+
+const testJSONPAsXML = `<receptionReports currentSeconds="1599164934">
+<receptionReport receiverCallsign="W5CJ" receiverLocator="EM10" senderCallsign="AG6K" senderLocator="DM04" frequency="14074000" flowStartSeconds="1599161463" mode="FT8" isSender="1" receiverDXCC="United States" receiverDXCCCode="K" sNR="-10"/>
+<activeReceiver callsign="W5CJ" locator="EM10" frequency="14074870" DXCC="United States" mode="FT8" bands="20m,40m"/>
+<activeCallsign callsign="AG6K" reports="12" DXCC="United States" DXCCcode="K" frequency="14074000"/>
+<senderSearch callsign="AG6K" recentFlowStartSeconds="1599164900"/>
+<lastSequenceNumber value="14631964162"/>
+<maxFlowStartSeconds value="1599164934"/>
+</receptionReports>`
+
+func TestDecodeJSONResponse(t *testing.T) {
+	want, err := decodeResponse([]byte(testJSONPAsXML))
+	require.NoError(t, err)
+	want.Text = ""
+
+	for desc, body := range map[string]string{
+		"jsonp": testJSONP,
+		"json":  testJSONP[len("pskreporter(") : len(testJSONP)-len(");\n")],
+	} {
+		t.Run(desc, func(t *testing.T) {
+			got, err := decodeJSONResponse([]byte(body))
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := decodeJSONResponse([]byte(`<receptionReports/>`))
+		var de *DecodeError
+		require.True(t, errors.As(err, &de))
+
+		_, err = decodeJSONResponse([]byte(`cb({"receptionReport":[{"mode":"FT8"},]})`))
+		require.True(t, errors.As(err, &de))
+		require.Equal(t, int64(39), de.Offset)
+
+		_, err = decodeJSONResponse([]byte(`{"receptionReport":{"mode":"FT8"}}`))
+		require.True(t, errors.As(err, &de))
+	})
+}
+
+func TestResponseFormatJSON(t *testing.T) {
+	var callback string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		callback = req.URL.Query().Get("callback")
+		if callback == "" {
+			w.Write([]byte(testJSONPAsXML))
+			return
+		}
+		w.Write([]byte(testJSONP))
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL), WithResponseFormat(FormatJSON), WithCacheDir(t.TempDir()))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		r, err := c.Query(WithCallsign("AG6K"))
+		require.NoError(t, err)
+		require.Equal(t, jsonpCallback, callback)
+		require.Len(t, r.ReceptionReports, 1)
+		require.Equal(t, "14631964162", r.LastSequenceNumber.Value)
+	}
+
+	n, err := c.QueryToSink(context.Background(), &recordingSink{}, WithCallsign("AG6K"))
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.Empty(t, callback)
+
+	_, err = c.BuildURL(WithParam("callback", "x"))
+	require.ErrorIs(t, err, ErrBadOption)
+	_, err = New(WithResponseFormat(ResponseFormat(2)))
+	require.ErrorIs(t, err, ErrBadOption)
+}
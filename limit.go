@@ -0,0 +1,160 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultMinQueryInterval is the interval PSK Reporter asks clients to leave
+// between queries. Clients don't limit queries unless WithMinQueryInterval is
+// used.
+const DefaultMinQueryInterval = 5 * time.Minute
+
+// ErrQueryTooSoon is returned, wrapped in a QueryError, when a query is made
+// before the client's minimum query interval has passed and the client was
+// configured with RateLimitReject.
+var ErrQueryTooSoon = errors.New("query made before the minimum query interval passed")
+
+// RateLimitPolicy says what a client with a minimum query interval does with
+// queries made too soon.
+type RateLimitPolicy int
+
+const (
+	// RateLimitWait blocks the query until the interval has passed or its
+	// context is done.
+	RateLimitWait RateLimitPolicy = iota
+
+	// RateLimitReject fails the query with ErrQueryTooSoon.
+	RateLimitReject
+)
+
+// WithMinQueryInterval makes the client leave at least d between the queries
+// it sends to the server. Queries answered from the cache don't count. Clients
+// derived with With share the interval with the client they came from unless
+// they set their own. Zero turns the limit off.
+//
+// If the client has a cache directory, the time of the last query is kept
+// there too, so that the interval holds across separate runs of a program,
+// and between programs sharing the directory.
+func WithMinQueryInterval(d time.Duration) ClientOption {
+	return func(o *clientOptions) error {
+		if d < 0 {
+			return badOption(fmt.Errorf("min query interval %s must not be negative", d))
+		}
+		o.limiter = nil
+		if d > 0 {
+			o.limiter = &queryLimiter{interval: d}
+		}
+		return nil
+	}
+}
+
+// WithRateLimitPolicy sets what the client does with queries made before the
+// minimum query interval has passed. Defaults to RateLimitWait.
+func WithRateLimitPolicy(p RateLimitPolicy) ClientOption {
+	return func(o *clientOptions) error {
+		if p != RateLimitWait && p != RateLimitReject {
+			return badOption(fmt.Errorf("unknown rate limit policy %d", p))
+		}
+		o.limitPolicy = p
+		return nil
+	}
+}
+
+// queryLimiter spaces out queries by a minimum interval. It is safe for
+// concurrent use.
+type queryLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time // when the next query may be sent
+}
+
+// lastQueryFile is the file in the cache directory holding the time of the
+// last query.
+const lastQueryFile = "last-query"
+
+// wait reserves the next slot for a query, blocking until it comes unless
+// policy is RateLimitReject. If cacheDir isn't empty, the last query time
+// stored there is taken into account, and updated once the slot comes.
+func (l *queryLimiter) wait(ctx context.Context, policy RateLimitPolicy, cacheDir string) error {
+	l.mu.Lock()
+	var state string
+	if cacheDir != "" {
+		state = filepath.Join(cacheDir, lastQueryFile)
+		if last, ok := readLastQuery(state); ok {
+			if next := last.Add(l.interval); next.After(l.next) {
+				l.next = next
+			}
+		}
+	}
+	now := timeNow()
+	start := now
+	if l.next.After(now) {
+		if policy == RateLimitReject {
+			wait := l.next.Sub(now)
+			l.mu.Unlock()
+			return fmt.Errorf("%w: next query allowed in %s", ErrQueryTooSoon, wait.Round(time.Second))
+		}
+		start = l.next
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait := start.Sub(now); wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			// Give the slot back if nobody has queued behind it.
+			l.mu.Lock()
+			if l.next.Equal(start.Add(l.interval)) {
+				l.next = start
+			}
+			l.mu.Unlock()
+			return ctx.Err()
+		}
+	}
+
+	// The state file is only written now, so that a query given up while
+	// waiting doesn't hold back later runs.
+	if state != "" {
+		writeLastQuery(state, start)
+	}
+	return nil
+}
+
+// readLastQuery reads the time stored in file by writeLastQuery.
+func readLastQuery(file string) (time.Time, bool) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(b)))
+	return t, err == nil
+}
+
+// writeLastQuery stores t in file. Errors are ignored, as with the cache: at
+// worst the next run doesn't know about this query.
+func writeLastQuery(file string, t time.Time) {
+	tmp, err := os.CreateTemp(filepath.Dir(file), lastQueryFile+".*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.WriteString(t.UTC().Format(time.RFC3339Nano) + "\n")
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), file)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
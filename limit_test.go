@@ -0,0 +1,131 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinQueryInterval(t *testing.T) {
+	var requests int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte("<receptionReports/>"))
+	}))
+	defer srv.Close()
+
+	t.Run("wait", func(t *testing.T) {
+		c, err := New(WithBaseURL(srv.URL), WithMinQueryInterval(50*time.Millisecond))
+		require.NoError(t, err)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err := c.Query()
+			require.NoError(t, err)
+		}
+		require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+
+		// Derived clients share the limit.
+		d, err := c.With(WithDefaultAppContact("me@example.com"))
+		require.NoError(t, err)
+		require.Same(t, c.limiter, d.limiter)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = d.QueryContext(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		c, err := New(WithBaseURL(srv.URL), WithMinQueryInterval(time.Hour), WithRateLimitPolicy(RateLimitReject))
+		require.NoError(t, err)
+
+		_, err = c.Query()
+		require.NoError(t, err)
+		before := atomic.LoadInt32(&requests)
+		_, err = c.Query()
+		require.True(t, errors.Is(err, ErrQueryTooSoon))
+		var qe *QueryError
+		require.True(t, errors.As(err, &qe))
+		require.Equal(t, before, atomic.LoadInt32(&requests))
+	})
+
+	t.Run("cache hits", func(t *testing.T) {
+		c, err := New(
+			WithBaseURL(srv.URL),
+			WithCacheDir(t.TempDir()),
+			WithMinQueryInterval(time.Hour),
+			WithRateLimitPolicy(RateLimitReject),
+		)
+		require.NoError(t, err)
+
+		for i := 0; i < 3; i++ {
+			_, err := c.Query(WithCallsign("AG6K"))
+			require.NoError(t, err)
+		}
+		_, err = c.Query(WithCallsign("W5CJ"))
+		require.True(t, errors.Is(err, ErrQueryTooSoon))
+	})
+
+	t.Run("persisted", func(t *testing.T) {
+		dir := t.TempDir()
+		opts := []ClientOption{
+			WithBaseURL(srv.URL),
+			WithCacheDir(dir),
+			WithMinQueryInterval(time.Hour),
+			WithRateLimitPolicy(RateLimitReject),
+		}
+		c, err := New(opts...)
+		require.NoError(t, err)
+		_, err = c.Query(WithCallsign("AG6K"))
+		require.NoError(t, err)
+
+		// A new client, as in a later run of the program, knows about the
+		// first one's query.
+		c, err = New(opts...)
+		require.NoError(t, err)
+		_, err = c.Query(WithCallsign("W5CJ"))
+		require.True(t, errors.Is(err, ErrQueryTooSoon))
+
+		// A query given up while waiting leaves the state file alone.
+		before, err := os.ReadFile(filepath.Join(dir, lastQueryFile))
+		require.NoError(t, err)
+		c, err = New(append(opts, WithRateLimitPolicy(RateLimitWait))...)
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err = c.QueryContext(ctx, WithCallsign("W5CJ"))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		after, err := os.ReadFile(filepath.Join(dir, lastQueryFile))
+		require.NoError(t, err)
+		require.Equal(t, string(before), string(after))
+
+		// An unreadable state file is ignored.
+		require.NoError(t, os.WriteFile(filepath.Join(dir, lastQueryFile), []byte("garbage"), 0o600))
+		c, err = New(opts...)
+		require.NoError(t, err)
+		_, err = c.Query(WithCallsign("W5CJ"))
+		require.NoError(t, err)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		_, err := New(WithMinQueryInterval(-time.Second))
+		require.Error(t, err)
+		_, err = New(WithRateLimitPolicy(RateLimitPolicy(2)))
+		require.Error(t, err)
+
+		c, err := New(WithMinQueryInterval(time.Minute))
+		require.NoError(t, err)
+		d, err := c.With(WithMinQueryInterval(0))
+		require.NoError(t, err)
+		require.Nil(t, d.limiter)
+	})
+}
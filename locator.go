@@ -0,0 +1,101 @@
+package pskreporter
+
+import (
+	"errors"
+	"strings"
+)
+
+var (
+	errLocatorPrecision = errors.New("locator precision must be 2, 4, 6 or 8 characters")
+	errLocatorTooCoarse = errors.New("locator is coarser than the requested precision")
+)
+
+// LocatorPolicy decides what NormalizeLocators does with spots whose locators
+// are coarser than the requested precision.
+type LocatorPolicy int
+
+const (
+	// LocatorTruncate shortens finer locators and clears coarser or invalid
+	// ones, keeping every spot.
+	LocatorTruncate LocatorPolicy = iota
+
+	// LocatorReject shortens finer locators and drops spots with a coarser or
+	// invalid locator on either end.
+	LocatorReject
+)
+
+// NormalizeLocator shortens loc to precision characters, which must be 2, 4, 6
+// or 8, and writes it in the conventional case, e.g. "EM55db". It returns an
+// error if loc is invalid or coarser than precision.
+func NormalizeLocator(loc string, precision int) (string, error) {
+	if precision < 2 || precision > 8 || precision%2 != 0 {
+		return "", errLocatorPrecision
+	}
+	if _, err := ParseLocator(loc); err != nil {
+		return "", err
+	}
+
+	loc = strings.TrimSpace(loc)
+	if len(loc) < precision {
+		return "", errLocatorTooCoarse
+	}
+	loc = loc[:precision]
+	if len(loc) <= 4 {
+		return strings.ToUpper(loc), nil
+	}
+	return strings.ToUpper(loc[:4]) + strings.ToLower(loc[4:]), nil
+}
+
+// LocatorEnricher brings the sender and receiver locators of spots to the
+// same precision, using the LocatorTruncate policy. Placed at the start of a
+// Pipeline, it makes distances and later analytics consistent across spots
+// reported with mixed precision. It returns an error from Enrich if precision
+// isn't 2, 4, 6 or 8.
+func LocatorEnricher(precision int) Enricher {
+	return EnricherFunc(func(s *Spot) error {
+		if precision < 2 || precision > 8 || precision%2 != 0 {
+			return errLocatorPrecision
+		}
+		normalizeSpotLocators(s, precision)
+		return nil
+	})
+}
+
+// NormalizeLocators brings the sender and receiver locators of spots to the
+// same precision, handling coarser locators according to policy, and returns
+// the spots that are kept. Spots are modified in place. Spots whose locators
+// change and that already had a distance or bearing have them, and Greyline,
+// recalculated from the normalized locators.
+func NormalizeLocators(spots []Spot, precision int, policy LocatorPolicy) ([]Spot, error) {
+	if precision < 2 || precision > 8 || precision%2 != 0 {
+		return nil, errLocatorPrecision
+	}
+
+	kept := spots[:0]
+	for i := range spots {
+		s := &spots[i]
+		if !normalizeSpotLocators(s, precision) && policy == LocatorReject {
+			continue
+		}
+		kept = append(kept, *s)
+	}
+	return kept, nil
+}
+
+// normalizeSpotLocators normalizes both locators of s, clearing those that
+// can't be, and reports whether both could be.
+func normalizeSpotLocators(s *Spot, precision int) bool {
+	sender, senderErr := NormalizeLocator(s.SenderLocator, precision)
+	receiver, receiverErr := NormalizeLocator(s.ReceiverLocator, precision)
+	if sender == s.SenderLocator && receiver == s.ReceiverLocator {
+		return senderErr == nil && receiverErr == nil
+	}
+
+	s.SenderLocator, s.ReceiverLocator = sender, receiver
+	if s.Distance != 0 || s.Bearing != 0 {
+		s.Distance, s.Bearing, s.Greyline = 0, 0, false
+		DistanceEnricher().Enrich(s)
+		GreylineEnricher().Enrich(s)
+	}
+	return senderErr == nil && receiverErr == nil
+}
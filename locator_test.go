@@ -0,0 +1,99 @@
+package pskreporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeLocator(t *testing.T) {
+	tests := []struct {
+		loc       string
+		precision int
+		want      string
+		err       error
+	}{
+		{"EM55db92", 8, "EM55db92", nil},
+		{"em55DB92", 6, "EM55db", nil},
+		{" em55db ", 4, "EM55", nil},
+		{"EM55", 2, "EM", nil},
+		{"EM55", 6, "", errLocatorTooCoarse},
+		{"ZZ99", 4, "", errInvalidLocator},
+		{"", 4, "", errInvalidLocator},
+		{"EM55", 5, "", errLocatorPrecision},
+		{"EM55", 10, "", errLocatorPrecision},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeLocator(tt.loc, tt.precision)
+		require.Equal(t, tt.err, err, tt.loc)
+		require.Equal(t, tt.want, got, tt.loc)
+	}
+}
+
+func TestNormalizeLocators(t *testing.T) {
+	spots := func() []Spot {
+		s := []Spot{
+			{SenderCallsign: "AG6K", SenderLocator: "DM14cc24", ReceiverCallsign: "W5CJ", ReceiverLocator: "EM55db92"},
+			{SenderCallsign: "AG6K", SenderLocator: "DM14", ReceiverCallsign: "N7HPX", ReceiverLocator: "DN31uo"},
+			{SenderCallsign: "AG6K", SenderLocator: "DM14cc", ReceiverCallsign: "K1ABC", ReceiverLocator: "FN42"},
+		}
+		require.NoError(t, DefaultPipeline().Apply(s))
+		return s
+	}
+
+	t.Run("truncate", func(t *testing.T) {
+		got, err := NormalizeLocators(spots(), 6, LocatorTruncate)
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		require.Equal(t, "DM14cc", got[0].SenderLocator)
+		require.Equal(t, "EM55db", got[0].ReceiverLocator)
+		require.Equal(t, "", got[1].SenderLocator)
+		require.Equal(t, "DN31uo", got[1].ReceiverLocator)
+		require.Equal(t, "", got[2].ReceiverLocator)
+
+		// Distances follow the normalized locators.
+		want := []Spot{{SenderLocator: "DM14cc", ReceiverLocator: "EM55db"}}
+		require.NoError(t, DistanceEnricher().Enrich(&want[0]))
+		require.Equal(t, want[0].Distance, got[0].Distance)
+		require.Equal(t, want[0].Bearing, got[0].Bearing)
+		require.Zero(t, got[1].Distance)
+		require.Zero(t, got[2].Distance)
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		got, err := NormalizeLocators(spots(), 6, LocatorReject)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, "W5CJ", got[0].ReceiverCallsign)
+
+		got, err = NormalizeLocators(spots(), 4, LocatorReject)
+		require.NoError(t, err)
+		require.Len(t, got, 3)
+		require.Equal(t, "DN31", got[1].ReceiverLocator)
+	})
+
+	t.Run("unenriched spots stay unenriched", func(t *testing.T) {
+		got, err := NormalizeLocators([]Spot{{SenderLocator: "DM14cc24", ReceiverLocator: "EM55db92"}}, 4, LocatorTruncate)
+		require.NoError(t, err)
+		require.Zero(t, got[0].Distance)
+	})
+
+	t.Run("bad precision", func(t *testing.T) {
+		_, err := NormalizeLocators(spots(), 3, LocatorTruncate)
+		require.Equal(t, errLocatorPrecision, err)
+	})
+}
+
+func TestLocatorEnricher(t *testing.T) {
+	s := []Spot{{SenderLocator: "DM14cc24", ReceiverLocator: "EM55db92"}}
+	p := NewPipeline(LocatorEnricher(4), DistanceEnricher())
+	require.NoError(t, p.Apply(s))
+	require.Equal(t, "DM14", s[0].SenderLocator)
+	require.Equal(t, "EM55", s[0].ReceiverLocator)
+
+	want := Spot{SenderLocator: "DM14", ReceiverLocator: "EM55"}
+	require.NoError(t, DistanceEnricher().Enrich(&want))
+	require.Equal(t, want.Distance, s[0].Distance)
+
+	require.Equal(t, errLocatorPrecision, LocatorEnricher(0).Enrich(&Spot{}))
+}
@@ -0,0 +1,300 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errStoreClosed = errors.New("store is closed")
+
+// MemoryStore is a Store that keeps spots in memory. It is safe for concurrent
+// use, and suited to tests and short-lived collectors.
+type MemoryStore struct {
+	mu        sync.RWMutex
+	spots     []Spot        // ordered by time
+	summaries []SpotSummary // ordered by hour
+	closed    bool
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Put adds spots to the store.
+func (m *MemoryStore) Put(ctx context.Context, spots []Spot) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return errStoreClosed
+	}
+
+	n := len(m.spots)
+	m.spots = append(m.spots, spots...)
+
+	// Spots usually arrive in order, so only sort when the new ones don't
+	// simply extend the existing run.
+	sorted := sort.SliceIsSorted(m.spots[n:], func(i, j int) bool {
+		return m.spots[n+i].Time.Before(m.spots[n+j].Time)
+	})
+	if !sorted || (n > 0 && len(spots) > 0 && m.spots[n].Time.Before(m.spots[n-1].Time)) {
+		sort.SliceStable(m.spots, m.less)
+	}
+	return nil
+}
+
+func (m *MemoryStore) less(i, j int) bool {
+	return m.spots[i].Time.Before(m.spots[j].Time)
+}
+
+// QueryByCallsign returns the spots sent or received by callsign.
+func (m *MemoryStore) QueryByCallsign(ctx context.Context, callsign string, start, end time.Time) ([]Spot, error) {
+	return m.query(ctx, start, end, func(s *Spot) bool {
+		return strings.EqualFold(s.SenderCallsign, callsign) || strings.EqualFold(s.ReceiverCallsign, callsign)
+	})
+}
+
+// QueryByTimeRange returns every spot in the time range.
+func (m *MemoryStore) QueryByTimeRange(ctx context.Context, start, end time.Time) ([]Spot, error) {
+	return m.query(ctx, start, end, nil)
+}
+
+// QueryByBand returns the spots on band.
+func (m *MemoryStore) QueryByBand(ctx context.Context, band Band, start, end time.Time) ([]Spot, error) {
+	return m.query(ctx, start, end, func(s *Spot) bool {
+		b, ok := spotBand(s)
+		return ok && b == band
+	})
+}
+
+// Aggregate summarizes the spots in the time range per band, ordered by
+// frequency.
+func (m *MemoryStore) Aggregate(ctx context.Context, start, end time.Time) ([]BandAggregate, error) {
+	spots, err := m.query(ctx, start, end, nil)
+	if err != nil {
+		return nil, err
+	}
+	return aggregateByBand(spots), nil
+}
+
+// Close releases the spots held by the store.
+func (m *MemoryStore) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spots = nil
+	m.summaries = nil
+	m.closed = true
+	return nil
+}
+
+func (m *MemoryStore) query(ctx context.Context, start, end time.Time, match func(*Spot) bool) ([]Spot, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return nil, errStoreClosed
+	}
+
+	first := 0
+	if !start.IsZero() {
+		first = sort.Search(len(m.spots), func(i int) bool {
+			return !m.spots[i].Time.Before(start)
+		})
+	}
+
+	var result []Spot
+	for i := first; i < len(m.spots); i++ {
+		s := &m.spots[i]
+		if !end.IsZero() && !s.Time.Before(end) {
+			break
+		}
+		if match == nil || match(s) {
+			result = append(result, *s)
+		}
+	}
+	return result, nil
+}
+
+// aggregateByBand summarizes spots per band, ordered by frequency.
+func aggregateByBand(spots []Spot) []BandAggregate {
+	type acc struct {
+		agg       BandAggregate
+		senders   map[string]bool
+		receivers map[string]bool
+	}
+	byBand := make(map[Band]*acc)
+	for i := range spots {
+		s := &spots[i]
+		b, ok := spotBand(s)
+		if !ok {
+			continue
+		}
+
+		a, ok := byBand[b]
+		if !ok {
+			a = &acc{
+				agg:       BandAggregate{Band: b},
+				senders:   make(map[string]bool),
+				receivers: make(map[string]bool),
+			}
+			byBand[b] = a
+		}
+		a.agg.Spots++
+		a.senders[strings.ToUpper(s.SenderCallsign)] = true
+		a.receivers[strings.ToUpper(s.ReceiverCallsign)] = true
+		if s.HasSNR && (!a.agg.HasSNR || s.SNR > a.agg.BestSNR) {
+			a.agg.BestSNR, a.agg.HasSNR = s.SNR, true
+		}
+		if d, ok := spotDistance(s); ok && d > a.agg.MaxDistance {
+			a.agg.MaxDistance = d
+		}
+	}
+
+	var result []BandAggregate
+	for _, b := range Bands() {
+		if a, ok := byBand[b]; ok {
+			a.agg.Senders = len(a.senders)
+			a.agg.Receivers = len(a.receivers)
+			result = append(result, a.agg)
+		}
+	}
+	return result
+}
+
+// Prune removes the spots that fall outside policy as of now, returning how
+// many were removed.
+func (m *MemoryStore) Prune(ctx context.Context, policy RetentionPolicy, now time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0, errStoreClosed
+	}
+
+	keep := retained(m.spots, policy, now)
+	kept := m.spots[:0]
+	for i, s := range m.spots {
+		if keep[i] {
+			kept = append(kept, s)
+		}
+	}
+	removed := len(m.spots) - len(kept)
+	for i := len(kept); i < len(m.spots); i++ {
+		m.spots[i] = Spot{} // release references held by removed spots
+	}
+	m.spots = kept
+	return removed, nil
+}
+
+// Search runs q using the store's time ordering to narrow the time range.
+func (m *MemoryStore) Search(ctx context.Context, q SpotQuery) (SpotPage, error) {
+	if q.Limit < 0 || q.Offset < 0 {
+		return SpotPage{}, errNegativePage
+	}
+	matched, err := m.query(ctx, q.Start, q.End, q.matches)
+	if err != nil {
+		return SpotPage{}, err
+	}
+	return q.page(matched), nil
+}
+
+// Downsample replaces the spots heard before threshold with hourly summaries,
+// returning how many spots were removed.
+func (m *MemoryStore) Downsample(ctx context.Context, threshold time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return 0, errStoreClosed
+	}
+
+	n := sort.Search(len(m.spots), func(i int) bool {
+		return !m.spots[i].Time.Before(threshold)
+	})
+	m.summaries = mergeSummaries(m.summaries, Downsample(m.spots[:n]))
+	m.spots = append([]Spot(nil), m.spots[n:]...)
+	return n, nil
+}
+
+// Summaries returns the summaries for hours starting in [start, end), ordered
+// by hour.
+func (m *MemoryStore) Summaries(ctx context.Context, start, end time.Time) ([]SpotSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return nil, errStoreClosed
+	}
+
+	var result []SpotSummary
+	for _, s := range m.summaries {
+		if !start.IsZero() && s.Hour.Before(start) {
+			continue
+		}
+		if !end.IsZero() && !s.Hour.Before(end) {
+			break
+		}
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// Snapshot writes the spots and summaries in the store to w.
+func (m *MemoryStore) Snapshot(ctx context.Context, w io.Writer) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.closed {
+		return errStoreClosed
+	}
+	return writeSnapshot(w, m.spots, m.summaries)
+}
+
+// Restore replaces the spots and summaries in the store with a snapshot read
+// from r.
+func (m *MemoryStore) Restore(ctx context.Context, r io.Reader) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	spots, summaries, err := readSnapshot(r)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(spots, func(i, j int) bool {
+		return spots[i].Time.Before(spots[j].Time)
+	})
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return errStoreClosed
+	}
+	m.spots = spots
+	m.summaries = mergeSummaries(nil, summaries)
+	return nil
+}
@@ -0,0 +1,81 @@
+package pskreporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	base := time.Unix(1599163380, 0).UTC()
+
+	s := NewMemoryStore()
+	require.NoError(t, s.Put(ctx, []Spot{
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Frequency: 14097100, SNR: -10, HasSNR: true, Distance: 1500, Time: base.Add(2 * time.Minute)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "N7HPX", Frequency: 14097050, SNR: -20, HasSNR: true, Distance: 900, Time: base},
+	}))
+	require.NoError(t, s.Put(ctx, []Spot{
+		{SenderCallsign: "K1ABC", ReceiverCallsign: "ag6k", Frequency: 7038600, Time: base.Add(time.Minute)},
+		{SenderCallsign: "K1ABC", ReceiverCallsign: "W5CJ", Frequency: 1, Time: base.Add(3 * time.Minute)}, // no band
+	}))
+
+	t.Run("time range", func(t *testing.T) {
+		spots, err := s.QueryByTimeRange(ctx, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, spots, 4)
+		for i := 1; i < len(spots); i++ {
+			require.False(t, spots[i].Time.Before(spots[i-1].Time))
+		}
+
+		spots, err = s.QueryByTimeRange(ctx, base.Add(time.Minute), base.Add(3*time.Minute))
+		require.NoError(t, err)
+		require.Len(t, spots, 2)
+		require.Equal(t, "K1ABC", spots[0].SenderCallsign)
+		require.Equal(t, "W5CJ", spots[1].ReceiverCallsign)
+	})
+
+	t.Run("callsign", func(t *testing.T) {
+		spots, err := s.QueryByCallsign(ctx, "ag6k", time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, spots, 3)
+
+		spots, err = s.QueryByCallsign(ctx, "AG6K", base.Add(time.Minute), time.Time{})
+		require.NoError(t, err)
+		require.Len(t, spots, 2)
+	})
+
+	t.Run("band", func(t *testing.T) {
+		spots, err := s.QueryByBand(ctx, Band40m, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, spots, 1)
+		require.Equal(t, int64(7038600), spots[0].Frequency)
+	})
+
+	t.Run("aggregate", func(t *testing.T) {
+		aggs, err := s.Aggregate(ctx, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Equal(t, []BandAggregate{
+			{Band: Band40m, Spots: 1, Senders: 1, Receivers: 1},
+			{Band: Band20m, Spots: 2, Senders: 1, Receivers: 2, BestSNR: -10, HasSNR: true, MaxDistance: 1500},
+		}, aggs)
+	})
+
+	t.Run("sink", func(t *testing.T) {
+		store := NewMemoryStore()
+		sink := NewStoreSink(store)
+		require.NoError(t, sink.Write(ctx, testSpots))
+		require.NoError(t, sink.Flush())
+
+		spots, err := store.QueryByTimeRange(ctx, time.Time{}, time.Time{})
+		require.NoError(t, err)
+		require.Len(t, spots, len(testSpots))
+
+		require.NoError(t, sink.Close())
+		_, err = store.QueryByTimeRange(ctx, time.Time{}, time.Time{})
+		require.Equal(t, errStoreClosed, err)
+		require.Equal(t, errStoreClosed, store.Put(ctx, testSpots))
+	})
+}
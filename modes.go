@@ -0,0 +1,92 @@
+package pskreporter
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// modeRegistry holds the modes known to NormalizeMode, keyed by their
+// upper-case name or alias.
+var modeRegistry = struct {
+	sync.RWMutex
+	names map[string]string
+}{
+	names: map[string]string{
+		"CW":       "CW",
+		"CONTESTI": "CONTESTI",
+		"DOMINO":   "DOMINO",
+		"FREEDV":   "FREEDV",
+		"FSK441":   "FSK441",
+		"FST4":     "FST4",
+		"FST4W":    "FST4W",
+		"FT4":      "FT4",
+		"FT8":      "FT8",
+		"HELL":     "HELL",
+		"ISCAT":    "ISCAT",
+		"JS8":      "JS8",
+		"JT4":      "JT4",
+		"JT65":     "JT65",
+		"JT6M":     "JT6M",
+		"JT9":      "JT9",
+		"MFSK":     "MFSK",
+		"MSK144":   "MSK144",
+		"OLIVIA":   "OLIVIA",
+		"OPERA":    "OPERA",
+		"PI4":      "PI4",
+		"PSK125":   "PSK125",
+		"PSK31":    "PSK31",
+		"PSK63":    "PSK63",
+		"Q65":      "Q65",
+		"ROS":      "ROS",
+		"RTTY":     "RTTY",
+		"SSB":      "SSB",
+		"THOR":     "THOR",
+		"WSPR":     "WSPR",
+
+		// Aliases.
+		"BPSK31":  "PSK31",
+		"BPSK63":  "PSK63",
+		"BPSK125": "PSK125",
+		"JS8CALL": "JS8",
+		"WSPR2":   "WSPR",
+	},
+}
+
+// NormalizeMode returns the name PSK Reporter uses for mode s, such as "FT8"
+// for "ft8" or "PSK31" for "BPSK31", or false if the mode isn't known.
+func NormalizeMode(s string) (string, bool) {
+	modeRegistry.RLock()
+	defer modeRegistry.RUnlock()
+	m, ok := modeRegistry.names[strings.ToUpper(strings.TrimSpace(s))]
+	return m, ok
+}
+
+// KnownModes returns the names of the known modes, sorted.
+func KnownModes() []string {
+	modeRegistry.RLock()
+	defer modeRegistry.RUnlock()
+	var modes []string
+	for k, v := range modeRegistry.names {
+		if k == v {
+			modes = append(modes, v)
+		}
+	}
+	sort.Strings(modes)
+	return modes
+}
+
+// RegisterMode adds a mode, such as one PSK Reporter has started reporting
+// since this package was released, so that NormalizeMode and clients created
+// with WithStrictModes accept it. The name is stored in upper case.
+func RegisterMode(name string) error {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if name == "" || strings.ContainsAny(name, " \t/,") {
+		return errors.New("mode name must be a single word")
+	}
+	modeRegistry.Lock()
+	defer modeRegistry.Unlock()
+	modeRegistry.names[name] = name
+	return nil
+}
@@ -0,0 +1,48 @@
+package pskreporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeMode(t *testing.T) {
+	tests := map[string]string{
+		"FT8":     "FT8",
+		" ft4 ":   "FT4",
+		"bpsk31":  "PSK31",
+		"JS8Call": "JS8",
+	}
+	for in, want := range tests {
+		m, ok := NormalizeMode(in)
+		require.True(t, ok, in)
+		require.Equal(t, want, m)
+	}
+
+	_, ok := NormalizeMode("FT9")
+	require.False(t, ok)
+}
+
+func TestKnownModes(t *testing.T) {
+	modes := KnownModes()
+	require.Contains(t, modes, "FT8")
+	require.Contains(t, modes, "WSPR")
+	require.NotContains(t, modes, "BPSK31")
+	require.IsIncreasing(t, modes)
+}
+
+func TestRegisterMode(t *testing.T) {
+	t.Cleanup(func() {
+		modeRegistry.Lock()
+		delete(modeRegistry.names, "FT9")
+		modeRegistry.Unlock()
+	})
+
+	require.Error(t, RegisterMode(""))
+	require.Error(t, RegisterMode("FT 9"))
+	require.NoError(t, RegisterMode("ft9"))
+	m, ok := NormalizeMode("Ft9")
+	require.True(t, ok)
+	require.Equal(t, "FT9", m)
+	require.Contains(t, KnownModes(), "FT9")
+}
@@ -0,0 +1,161 @@
+package pskreporter
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Station is one end of a spot.
+type Station struct {
+	Callsign string
+	Locator  string
+	DXCC     string
+	DXCCCode string
+}
+
+// Sender returns the station that transmitted the spotted signal.
+func (s Spot) Sender() Station {
+	return Station{
+		Callsign: s.SenderCallsign,
+		Locator:  s.SenderLocator,
+		DXCC:     s.SenderDXCC,
+		DXCCCode: s.SenderDXCCCode,
+	}
+}
+
+// Receiver returns the station that received the spotted signal.
+func (s Spot) Receiver() Station {
+	return Station{
+		Callsign: s.ReceiverCallsign,
+		Locator:  s.ReceiverLocator,
+		DXCC:     s.ReceiverDXCC,
+		DXCCCode: s.ReceiverDXCCCode,
+	}
+}
+
+// Multiplier returns the contest multiplier a station counts for, such as its
+// DXCC entity or zone, or false if it can't be determined.
+type Multiplier func(Station) (string, bool)
+
+// DXCCMultiplier counts each DXCC entity as a multiplier.
+func DXCCMultiplier(st Station) (string, bool) {
+	return st.DXCC, st.DXCC != ""
+}
+
+// GridMultiplier counts each 4 character grid square as a multiplier, as used
+// in most VHF contests.
+func GridMultiplier(st Station) (string, bool) {
+	if len(st.Locator) < 4 {
+		return "", false
+	}
+	if _, err := ParseLocator(st.Locator[:4]); err != nil {
+		return "", false
+	}
+	return strings.ToUpper(st.Locator[:4]), true
+}
+
+// MultiplierSpot is a station representing a needed multiplier that is in
+// contact range of the user.
+type MultiplierSpot struct {
+	Multiplier string
+	Station    Station
+
+	// Hears is true if the station heard the user, and false if the user heard
+	// the station.
+	Hears bool
+
+	// Spot is the most recent spot linking the user and the station.
+	Spot Spot
+}
+
+// MultiplierSpotter watches spots involving the user's callsign and flags the
+// stations on the other end that represent multipliers still needed in a
+// contest. It is safe for concurrent use.
+type MultiplierSpotter struct {
+	mu       sync.Mutex
+	callsign string
+	mult     Multiplier
+	needed   map[string]bool // nil means every multiplier not yet worked
+	worked   map[string]bool
+}
+
+// NewMultiplierSpotter creates a spotter for the user's callsign. If needed is
+// empty, every multiplier that hasn't been marked as worked is needed.
+func NewMultiplierSpotter(callsign string, mult Multiplier, needed []string) *MultiplierSpotter {
+	m := &MultiplierSpotter{
+		callsign: callsign,
+		mult:     mult,
+		worked:   make(map[string]bool),
+	}
+	if len(needed) > 0 {
+		m.needed = make(map[string]bool, len(needed))
+		for _, n := range needed {
+			m.needed[strings.ToUpper(n)] = true
+		}
+	}
+	return m
+}
+
+// Worked marks the multipliers as worked, so they are no longer flagged.
+func (m *MultiplierSpotter) Worked(mults ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, mult := range mults {
+		m.worked[strings.ToUpper(mult)] = true
+	}
+}
+
+// Check returns the stations in the spots that represent needed multipliers
+// and either heard or were heard by the user. Each station is returned once,
+// ordered by multiplier and then callsign.
+func (m *MultiplierSpotter) Check(spots []Spot) []MultiplierSpot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	found := make(map[string]*MultiplierSpot)
+	for _, s := range spots {
+		var other Station
+		var hears bool
+		switch {
+		case strings.EqualFold(s.SenderCallsign, m.callsign):
+			other, hears = s.Receiver(), true
+		case strings.EqualFold(s.ReceiverCallsign, m.callsign):
+			other = s.Sender()
+		default:
+			continue
+		}
+
+		mult, ok := m.mult(other)
+		if !ok {
+			continue
+		}
+		key := strings.ToUpper(mult)
+		if m.worked[key] || (m.needed != nil && !m.needed[key]) {
+			continue
+		}
+
+		call := strings.ToUpper(other.Callsign)
+		if prev, ok := found[call]; ok && !s.Time.After(prev.Spot.Time) {
+			continue
+		}
+		found[call] = &MultiplierSpot{
+			Multiplier: mult,
+			Station:    other,
+			Hears:      hears,
+			Spot:       s,
+		}
+	}
+
+	result := make([]MultiplierSpot, 0, len(found))
+	for _, ms := range found {
+		result = append(result, *ms)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Multiplier != result[j].Multiplier {
+			return result[i].Multiplier < result[j].Multiplier
+		}
+		return result[i].Station.Callsign < result[j].Station.Callsign
+	})
+	return result
+}
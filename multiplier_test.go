@@ -0,0 +1,76 @@
+package pskreporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiplierSpotter(t *testing.T) {
+	base := time.Unix(1599163380, 0).UTC()
+	spots := []Spot{
+		{SenderCallsign: "AG6K", ReceiverCallsign: "JA1XYZ", ReceiverDXCC: "Japan", Time: base},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "JA1XYZ", ReceiverDXCC: "Japan", Time: base.Add(time.Minute)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "JA1XYZ", ReceiverDXCC: "Japan", Time: base.Add(-time.Minute)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", ReceiverDXCC: "United States", Time: base},
+		{SenderCallsign: "ZL2ABC", SenderDXCC: "New Zealand", ReceiverCallsign: "ag6k", Time: base},
+		{SenderCallsign: "VK3ABC", ReceiverCallsign: "AG6K", Time: base}, // unknown DXCC
+		{SenderCallsign: "G4ABC", SenderDXCC: "England", ReceiverCallsign: "W5CJ", Time: base},
+	}
+
+	t.Run("all needed", func(t *testing.T) {
+		m := NewMultiplierSpotter("AG6K", DXCCMultiplier, nil)
+		m.Worked("united states")
+
+		found := m.Check(spots)
+		require.Len(t, found, 2)
+
+		require.Equal(t, "Japan", found[0].Multiplier)
+		require.Equal(t, "JA1XYZ", found[0].Station.Callsign)
+		require.True(t, found[0].Hears)
+		require.Equal(t, base.Add(time.Minute), found[0].Spot.Time)
+
+		require.Equal(t, "New Zealand", found[1].Multiplier)
+		require.Equal(t, "ZL2ABC", found[1].Station.Callsign)
+		require.False(t, found[1].Hears)
+	})
+
+	t.Run("needed list", func(t *testing.T) {
+		m := NewMultiplierSpotter("AG6K", DXCCMultiplier, []string{"new zealand", "England"})
+
+		found := m.Check(spots)
+		require.Len(t, found, 1)
+		require.Equal(t, "New Zealand", found[0].Multiplier)
+
+		m.Worked("New Zealand")
+		require.Empty(t, m.Check(spots))
+	})
+}
+
+func TestGridMultiplier(t *testing.T) {
+	mult, ok := GridMultiplier(Station{Locator: "em55db92"})
+	require.True(t, ok)
+	require.Equal(t, "EM55", mult)
+
+	_, ok = GridMultiplier(Station{Locator: "EM"})
+	require.False(t, ok)
+
+	_, ok = GridMultiplier(Station{Locator: "ZZ99"})
+	require.False(t, ok)
+}
+
+func TestSpotStations(t *testing.T) {
+	s := Spot{
+		SenderCallsign:   "AG6K",
+		SenderLocator:    "DM14",
+		SenderDXCC:       "United States",
+		SenderDXCCCode:   "K",
+		ReceiverCallsign: "JA1XYZ",
+		ReceiverLocator:  "PM95",
+		ReceiverDXCC:     "Japan",
+		ReceiverDXCCCode: "JA",
+	}
+	require.Equal(t, Station{"AG6K", "DM14", "United States", "K"}, s.Sender())
+	require.Equal(t, Station{"JA1XYZ", "PM95", "Japan", "JA"}, s.Receiver())
+}
@@ -0,0 +1,139 @@
+package pskreporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+)
+
+// Notification is a message sent through a Notifier.
+type Notification struct {
+	Title   string
+	Message string
+	Spots   []Spot // the spots that triggered the notification, if any
+}
+
+// Notifier delivers notifications to people, such as through a chat service
+// or a push notification.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// NotifierFunc is an adapter to allow the use of ordinary functions as
+// Notifiers.
+type NotifierFunc func(ctx context.Context, n Notification) error
+
+// Notify calls f(ctx, n).
+func (f NotifierFunc) Notify(ctx context.Context, n Notification) error {
+	return f(ctx, n)
+}
+
+// Templates used to render notifications unless WithNotificationTemplate is
+// given. Services with a separate title field use DefaultBodyTemplate.
+const (
+	DefaultNotificationTemplate = `{{if .Title}}{{.Title}}
+{{end}}{{.Message}}{{range .Spots}}
+{{.}}{{end}}`
+
+	DefaultBodyTemplate = `{{.Message}}{{range .Spots}}
+{{.}}{{end}}`
+)
+
+// NotifierOption is used to customize a Notifier.
+type NotifierOption func(*notifierOptions) error
+
+type notifierOptions struct {
+	doer   Doer
+	tmpl   *template.Template
+	apiURL string
+}
+
+// WithNotifierHTTPClient sets the http client used to deliver notifications.
+func WithNotifierHTTPClient(c Doer) NotifierOption {
+	return func(o *notifierOptions) error {
+		o.doer = c
+		return nil
+	}
+}
+
+// WithNotificationTemplate sets the text/template used to render the body of
+// a notification. The template is executed with the Notification as its data.
+func WithNotificationTemplate(tmpl string) NotifierOption {
+	return func(o *notifierOptions) error {
+		t, err := template.New("notification").Parse(tmpl)
+		if err != nil {
+			return fmt.Errorf("parsing notification template: %w", err)
+		}
+		o.tmpl = t
+		return nil
+	}
+}
+
+// WithNotifierAPIURL overrides the base URL of the service's API, for
+// self-hosted servers and testing.
+func WithNotifierAPIURL(u string) NotifierOption {
+	return func(o *notifierOptions) error {
+		o.apiURL = strings.TrimSuffix(u, "/")
+		return nil
+	}
+}
+
+func newNotifierOptions(apiURL, tmpl string, opts []NotifierOption) (notifierOptions, error) {
+	o := notifierOptions{
+		doer:   http.DefaultClient,
+		tmpl:   template.Must(template.New("notification").Parse(tmpl)),
+		apiURL: apiURL,
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return o, err
+		}
+	}
+	return o, nil
+}
+
+// render executes the notification template.
+func (o notifierOptions) render(n Notification) (string, error) {
+	var buf bytes.Buffer
+	if err := o.tmpl.Execute(&buf, n); err != nil {
+		return "", fmt.Errorf("rendering notification: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// do sends req and checks for a successful status, returning the body.
+func (o notifierOptions) do(req *http.Request) ([]byte, error) {
+	resp, err := o.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return b, fmt.Errorf("unexpected http response %d", resp.StatusCode)
+	}
+	return b, nil
+}
+
+// postJSON posts v as JSON to u.
+func (o notifierOptions) postJSON(ctx context.Context, u string, v interface{}) ([]byte, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return o.do(req)
+}
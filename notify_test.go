@@ -0,0 +1,50 @@
+package pskreporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotificationTemplate(t *testing.T) {
+	now := time.Unix(1599163380, 0).UTC()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	o, err := newNotifierOptions("", DefaultNotificationTemplate, nil)
+	require.NoError(t, err)
+
+	text, err := o.render(Notification{Title: "Heard in ZL", Message: "AG6K was spotted", Spots: testSpots[:1]})
+	require.NoError(t, err)
+	require.Equal(t, "Heard in ZL\nAG6K was spotted\nW5CJ EM55db92 ← AG6K DM14cc24 14.075MHz FT8 -19dB 0s ago", text)
+
+	text, err = o.render(Notification{Message: "AG6K was spotted"})
+	require.NoError(t, err)
+	require.Equal(t, "AG6K was spotted", text)
+
+	o, err = newNotifierOptions("", DefaultNotificationTemplate, []NotifierOption{WithNotificationTemplate("{{len .Spots}} new spots")})
+	require.NoError(t, err)
+	text, err = o.render(Notification{Spots: testSpots})
+	require.NoError(t, err)
+	require.Equal(t, "2 new spots", text)
+
+	_, err = newNotifierOptions("", DefaultNotificationTemplate, []NotifierOption{WithNotificationTemplate("{{.Title")})
+	require.Error(t, err)
+
+	o, err = newNotifierOptions("", DefaultNotificationTemplate, []NotifierOption{WithNotificationTemplate("{{.Missing}}")})
+	require.NoError(t, err)
+	_, err = o.render(Notification{})
+	require.Error(t, err)
+}
+
+func TestNotifierFunc(t *testing.T) {
+	var got Notification
+	var n Notifier = NotifierFunc(func(ctx context.Context, n Notification) error {
+		got = n
+		return nil
+	})
+	require.NoError(t, n.Notify(context.Background(), Notification{Title: "hi"}))
+	require.Equal(t, "hi", got.Title)
+}
@@ -0,0 +1,58 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultNtfyAPIURL is the public ntfy server.
+const DefaultNtfyAPIURL = "https://ntfy.sh"
+
+// NtfyNotifier publishes notifications to an ntfy topic.
+type NtfyNotifier struct {
+	topic string
+	token string
+	opts  notifierOptions
+}
+
+// NewNtfyNotifier creates a notifier that publishes to topic. token is an
+// access token for protected topics and may be empty. Use WithNotifierAPIURL
+// for a self-hosted server. The notification's title is sent as the ntfy
+// title and the body is rendered with DefaultBodyTemplate unless
+// WithNotificationTemplate is given.
+func NewNtfyNotifier(topic, token string, opts ...NotifierOption) (*NtfyNotifier, error) {
+	if topic == "" {
+		return nil, errors.New("ntfy topic is required")
+	}
+	o, err := newNotifierOptions(DefaultNtfyAPIURL, DefaultBodyTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &NtfyNotifier{topic: topic, token: token, opts: o}, nil
+}
+
+// Notify publishes the notification.
+func (n *NtfyNotifier) Notify(ctx context.Context, note Notification) error {
+	text, err := n.opts.render(note)
+	if err != nil {
+		return err
+	}
+
+	u := n.opts.apiURL + "/" + url.PathEscape(n.topic)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(text))
+	if err != nil {
+		return err
+	}
+	if note.Title != "" {
+		req.Header.Set("Title", note.Title)
+	}
+	if n.token != "" {
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	}
+
+	_, err = n.opts.do(req)
+	return err
+}
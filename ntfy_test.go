@@ -0,0 +1,46 @@
+package pskreporter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNtfyNotifier(t *testing.T) {
+	var req *http.Request
+	var body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req = r
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		if r.URL.Path == "/private" && r.Header.Get("Authorization") != "Bearer tk_secret" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	_, err := NewNtfyNotifier("", "")
+	require.Error(t, err)
+
+	ctx := context.Background()
+	n, err := NewNtfyNotifier("ag6k-alerts", "", WithNotifierAPIURL(srv.URL))
+	require.NoError(t, err)
+	require.NoError(t, n.Notify(ctx, Notification{Title: "First spot", Message: "AG6K heard by W5CJ"}))
+	require.Equal(t, http.MethodPost, req.Method)
+	require.Equal(t, "/ag6k-alerts", req.URL.Path)
+	require.Equal(t, "First spot", req.Header.Get("Title"))
+	require.Empty(t, req.Header.Get("Authorization"))
+	require.Equal(t, "AG6K heard by W5CJ", body)
+
+	n, err = NewNtfyNotifier("private", "", WithNotifierAPIURL(srv.URL))
+	require.NoError(t, err)
+	require.EqualError(t, n.Notify(ctx, Notification{Message: "hi"}), "unexpected http response 403")
+
+	n, err = NewNtfyNotifier("private", "tk_secret", WithNotifierAPIURL(srv.URL))
+	require.NoError(t, err)
+	require.NoError(t, n.Notify(ctx, Notification{Message: "hi"}))
+}
@@ -0,0 +1,137 @@
+package pskreporter
+
+import (
+	"context"
+	"math"
+	"strconv"
+)
+
+// minSegmentWidth is the narrowest frequency range in Hz that QueryComplete
+// will split further when it still hits the report limit.
+const minSegmentWidth = 1000
+
+// QueryComplete is like QueryContext, but works around the report limit. When
+// the query sets a limit with WithReportLimit and the response reaches it, the
+// query is repeated once per band of the client's IARU region with
+// WithFrequencyRange, splitting any band that still reaches the limit in half
+// until it doesn't or is narrower than 1kHz, and the responses are merged.
+// Whether a response reached the limit is judged by the reports the server
+// sent, before any are dropped by WithModes, WithCallsignPrefix or
+// WithMaxReceptionReports. Reports on frequencies outside every known band
+// are only taken from the first response. If the query sets frequency ranges,
+// only the parts of bands overlapping them are queried.
+func (c *Client) QueryComplete(ctx context.Context, opts ...QueryOption) (*Response, error) {
+	r, meta, err := c.QueryWithMeta(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := strconv.Atoi(r.Query.Values.Get("rptlimit"))
+	if err != nil || limit <= 0 || meta.Reports < limit {
+		return r, nil
+	}
+
+	ranges := r.Query.FrequencyRanges
+	if len(ranges) == 0 {
+		ranges = []FrequencyRange{{Lower: 0, Upper: math.MaxInt64}}
+	}
+
+	merged := r.Clone()
+	merged.ReceptionReports = merged.ReceptionReports[:0]
+	for _, rr := range r.ReceptionReports {
+		if f, err := strconv.ParseInt(rr.Frequency, 10, 64); err == nil {
+			if _, ok := BandForFrequencyIn(c.region, f); ok {
+				continue
+			}
+		}
+		merged.ReceptionReports = append(merged.ReceptionReports, rr)
+	}
+
+	m := newResponseMerger(merged)
+	for _, b := range bandPlanFor(c.region) {
+		for _, fr := range ranges {
+			lo, hi := b.lower, b.upper
+			if lo < fr.Lower {
+				lo = fr.Lower
+			}
+			if hi > fr.Upper {
+				hi = fr.Upper
+			}
+			if lo > hi {
+				continue
+			}
+			if err := c.querySegment(ctx, opts, limit, lo, hi, m); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return merged, nil
+}
+
+// querySegment queries the reports between lower and upper Hz, splitting the
+// range while the response reaches limit.
+func (c *Client) querySegment(ctx context.Context, opts []QueryOption, limit int, lower, upper int64, m *responseMerger) error {
+	segOpts := append(append([]QueryOption(nil), opts...), WithFrequencyRange(int(lower), int(upper)))
+	r, meta, err := c.QueryWithMeta(ctx, segOpts...)
+	if err != nil {
+		return err
+	}
+	if meta.Reports < limit || upper-lower < minSegmentWidth {
+		m.add(r)
+		return nil
+	}
+
+	mid := lower + (upper-lower)/2
+	if err := c.querySegment(ctx, opts, limit, lower, mid, m); err != nil {
+		return err
+	}
+	return c.querySegment(ctx, opts, limit, mid+1, upper, m)
+}
+
+// responseMerger adds the reports, receivers and callsigns of responses to a
+// single response, skipping duplicates.
+type responseMerger struct {
+	r         *Response
+	reports   map[ReceptionReport]bool
+	receivers map[ActiveReceiver]bool
+	callsigns map[ActiveCallsign]bool
+}
+
+func newResponseMerger(r *Response) *responseMerger {
+	m := &responseMerger{
+		r:         r,
+		reports:   make(map[ReceptionReport]bool),
+		receivers: make(map[ActiveReceiver]bool),
+		callsigns: make(map[ActiveCallsign]bool),
+	}
+	for _, rr := range r.ReceptionReports {
+		m.reports[rr] = true
+	}
+	for _, ar := range r.ActiveReceivers {
+		m.receivers[ar] = true
+	}
+	for _, ac := range r.ActiveCallsigns {
+		m.callsigns[ac] = true
+	}
+	return m
+}
+
+func (m *responseMerger) add(r *Response) {
+	for _, rr := range r.ReceptionReports {
+		if !m.reports[rr] {
+			m.reports[rr] = true
+			m.r.ReceptionReports = append(m.r.ReceptionReports, rr)
+		}
+	}
+	for _, ar := range r.ActiveReceivers {
+		if !m.receivers[ar] {
+			m.receivers[ar] = true
+			m.r.ActiveReceivers = append(m.r.ActiveReceivers, ar)
+		}
+	}
+	for _, ac := range r.ActiveCallsigns {
+		if !m.callsigns[ac] {
+			m.callsigns[ac] = true
+			m.r.ActiveCallsigns = append(m.r.ActiveCallsigns, ac)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newLimitServer serves reports honouring the frange and rptlimit parameters,
+// counting the requests made.
+func newLimitServer(t *testing.T, reports []ReceptionReport, requests *int) *httptest.Server {
+	t.Helper()
+	var mu sync.Mutex
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		*requests++
+		mu.Unlock()
+
+		q := req.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("rptlimit"))
+		lower, upper := int64(0), int64(1<<62)
+		if r := strings.SplitN(q.Get("frange"), "-", 2); len(r) == 2 {
+			lower, _ = strconv.ParseInt(r[0], 10, 64)
+			upper, _ = strconv.ParseInt(r[1], 10, 64)
+		}
+
+		resp := Response{ActiveReceivers: []ActiveReceiver{{Callsign: "W5CJ"}}}
+		for _, rr := range reports {
+			f, _ := strconv.ParseInt(rr.Frequency, 10, 64)
+			if f < lower || f > upper || (limit > 0 && len(resp.ReceptionReports) == limit) {
+				continue
+			}
+			resp.ReceptionReports = append(resp.ReceptionReports, rr)
+		}
+		require.NoError(t, xml.NewEncoder(w).Encode(resp))
+	}))
+	t.Cleanup(svr.Close)
+	return svr
+}
+
+func TestQueryComplete(t *testing.T) {
+	var reports []ReceptionReport
+	add := func(n int, freq int64) {
+		for i := 0; i < n; i++ {
+			reports = append(reports, ReceptionReport{
+				SenderCallsign: "AG6K",
+				Frequency:      strconv.FormatInt(freq+int64(i)*1000, 10),
+			})
+		}
+	}
+	add(3, 7074000)  // 40m
+	add(8, 14074000) // 20m, more than the limit
+	add(1, 28074000) // 10m
+	add(1, 12000000) // outside every band
+
+	t.Run("truncated", func(t *testing.T) {
+		var requests int
+		svr := newLimitServer(t, reports, &requests)
+		c, err := New(WithBaseURL(svr.URL))
+		require.NoError(t, err)
+
+		r, err := c.QueryComplete(context.Background(), WithReportLimit(5))
+		require.NoError(t, err)
+		require.ElementsMatch(t, reports[:len(reports)-1], r.ReceptionReports)
+		require.Equal(t, []ActiveReceiver{{Callsign: "W5CJ"}}, r.ActiveReceivers)
+		require.Greater(t, requests, len(bandPlan))
+	})
+
+	t.Run("frequency range", func(t *testing.T) {
+		var requests int
+		svr := newLimitServer(t, reports, &requests)
+		c, err := New(WithBaseURL(svr.URL))
+		require.NoError(t, err)
+
+		r, err := c.QueryComplete(context.Background(), WithReportLimit(2), WithFrequencyRange(7000000, 7100000))
+		require.NoError(t, err)
+		require.ElementsMatch(t, reports[:3], r.ReceptionReports)
+		require.Greater(t, requests, 2)
+	})
+
+	t.Run("frequency ranges", func(t *testing.T) {
+		var requests int
+		svr := newLimitServer(t, reports, &requests)
+		c, err := New(WithBaseURL(svr.URL))
+		require.NoError(t, err)
+
+		r, err := c.QueryComplete(context.Background(), WithReportLimit(2), WithBands(Band40m, Band10m))
+		require.NoError(t, err)
+		require.ElementsMatch(t, append(append([]ReceptionReport(nil), reports[:3]...), reports[11]), r.ReceptionReports)
+	})
+
+	t.Run("filtered", func(t *testing.T) {
+		// The server truncates at the limit before the client drops the CW
+		// reports, so fewer than the limit come back from a truncated query.
+		var mixed, ft8 []ReceptionReport
+		for i := 0; i < 8; i++ {
+			rr := ReceptionReport{
+				SenderCallsign: "AG6K",
+				Frequency:      strconv.Itoa(14074000 + i*1000),
+				Mode:           "FT8",
+			}
+			if i%2 == 1 {
+				rr.Mode = "CW"
+			} else {
+				ft8 = append(ft8, rr)
+			}
+			mixed = append(mixed, rr)
+		}
+
+		var requests int
+		svr := newLimitServer(t, mixed, &requests)
+		c, err := New(WithBaseURL(svr.URL))
+		require.NoError(t, err)
+
+		r, err := c.QueryComplete(context.Background(), WithReportLimit(5), WithModes("FT8", "FT4"))
+		require.NoError(t, err)
+		require.ElementsMatch(t, ft8, r.ReceptionReports)
+		require.Greater(t, requests, 1)
+	})
+
+	t.Run("not truncated", func(t *testing.T) {
+		var requests int
+		svr := newLimitServer(t, reports, &requests)
+		c, err := New(WithBaseURL(svr.URL))
+		require.NoError(t, err)
+
+		r, err := c.QueryComplete(context.Background(), WithReportLimit(100))
+		require.NoError(t, err)
+		require.Equal(t, reports, r.ReceptionReports)
+		require.Equal(t, 1, requests)
+
+		_, err = c.QueryComplete(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, requests)
+	})
+}
@@ -0,0 +1,126 @@
+package pskreporter
+
+import (
+	"math"
+	"time"
+)
+
+// DefaultPathRadius is the distance in kilometers from a path used by
+// ComparePath when no radius is given.
+const DefaultPathRadius = 500.0
+
+// PathBand summarizes the spots supporting a path on one band.
+type PathBand struct {
+	Band Band
+
+	// Direct is the number of spots with one end near each end of the path.
+	Direct int
+
+	// Near is the number of spots with one end near an end of the path and the
+	// other end near the great-circle path between them.
+	Near int
+
+	// BestSNR is the best signal report among the supporting spots, if any of
+	// them carried one.
+	BestSNR int
+	HasSNR  bool
+
+	// LastSeen is the time of the most recent supporting spot.
+	LastSeen time.Time
+}
+
+// Supported returns true if there is any evidence of the path being open on the
+// band.
+func (pb PathBand) Supported() bool {
+	return pb.Direct > 0 || pb.Near > 0
+}
+
+// ComparePath evaluates the spots along the path between two locators, such as
+// a home station and a DXpedition, and summarizes per band whether the path is
+// supported. Spots count in either direction. radius is how close in
+// kilometers a station needs to be to an end or the path to count, with zero
+// meaning DefaultPathRadius. Only bands with at least one supporting spot are
+// returned, ordered by frequency.
+func ComparePath(from, to string, spots []Spot, radius float64) ([]PathBand, error) {
+	a, err := ParseLocator(from)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ParseLocator(to)
+	if err != nil {
+		return nil, err
+	}
+	if radius <= 0 {
+		radius = DefaultPathRadius
+	}
+
+	byBand := make(map[Band]*PathBand)
+	for i := range spots {
+		s := &spots[i]
+		sender, receiver, ok := spotEnds(s)
+		if !ok {
+			continue
+		}
+		band, ok := spotBand(s)
+		if !ok {
+			continue
+		}
+
+		nearA := func(p LatLon) bool { return a.DistanceTo(p) <= radius }
+		nearB := func(p LatLon) bool { return b.DistanceTo(p) <= radius }
+		onPath := func(p LatLon) bool { return nearPath(a, b, p, radius) }
+
+		direct := (nearA(sender) && nearB(receiver)) || (nearB(sender) && nearA(receiver))
+		near := !direct &&
+			(((nearA(sender) || nearB(sender)) && onPath(receiver)) ||
+				((nearA(receiver) || nearB(receiver)) && onPath(sender)))
+		if !direct && !near {
+			continue
+		}
+
+		pb, ok := byBand[band]
+		if !ok {
+			pb = &PathBand{Band: band}
+			byBand[band] = pb
+		}
+		if direct {
+			pb.Direct++
+		} else {
+			pb.Near++
+		}
+		if s.HasSNR && (!pb.HasSNR || s.SNR > pb.BestSNR) {
+			pb.BestSNR, pb.HasSNR = s.SNR, true
+		}
+		if s.Time.After(pb.LastSeen) {
+			pb.LastSeen = s.Time
+		}
+	}
+
+	var result []PathBand
+	for _, band := range Bands() {
+		if pb, ok := byBand[band]; ok {
+			result = append(result, *pb)
+		}
+	}
+	return result, nil
+}
+
+// nearPath returns true if p is within radius kilometers of the great-circle
+// path between a and b, and lies between them.
+func nearPath(a, b, p LatLon, radius float64) bool {
+	dAP := a.DistanceTo(p) / earthRadiusKm
+	delta := radians(a.BearingTo(p) - a.BearingTo(b))
+
+	crossTrack := math.Asin(math.Sin(dAP) * math.Sin(delta))
+	if math.Abs(crossTrack)*earthRadiusKm > radius {
+		return false
+	}
+
+	// Points behind a have a negative along-track distance.
+	if math.Cos(delta) < 0 {
+		return false
+	}
+
+	alongTrack := math.Acos(math.Max(-1, math.Min(1, math.Cos(dAP)/math.Cos(crossTrack)))) * earthRadiusKm
+	return alongTrack <= a.DistanceTo(b)
+}
@@ -0,0 +1,64 @@
+package pskreporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComparePath(t *testing.T) {
+	// Southern California to Tokyo.
+	const home, dx = "DM14", "PM95"
+	base := time.Unix(1599163380, 0).UTC()
+
+	spots := []Spot{
+		// Direct, both directions.
+		{SenderLocator: "DM14cc", ReceiverLocator: "PM95tq", Frequency: 14074000, SNR: -15, HasSNR: true, Time: base},
+		{SenderLocator: "PM95", ReceiverLocator: "DM04", Frequency: 14074000, SNR: -8, HasSNR: true, Time: base.Add(time.Minute)},
+		// Home heard by a ship in the North Pacific, on the path.
+		{SenderLocator: "DM14", ReceiverLocator: "AN58", Frequency: 7074000, SNR: -20, HasSNR: true, Time: base},
+		// Home heard on the east coast, behind the home end of the path.
+		{SenderLocator: "DM14", ReceiverLocator: "FN31", Frequency: 10136000, Time: base},
+		// Unrelated path.
+		{SenderLocator: "JO63", ReceiverLocator: "IO91", Frequency: 14074000, Time: base},
+		// Missing locator.
+		{SenderLocator: "DM14", Frequency: 14074000, Time: base},
+	}
+
+	bands, err := ComparePath(home, dx, spots, 0)
+	require.NoError(t, err)
+	require.Equal(t, []PathBand{
+		{Band: Band40m, Near: 1, BestSNR: -20, HasSNR: true, LastSeen: base},
+		{Band: Band20m, Direct: 2, BestSNR: -8, HasSNR: true, LastSeen: base.Add(time.Minute)},
+	}, bands)
+	require.True(t, bands[0].Supported())
+	require.False(t, PathBand{}.Supported())
+
+	t.Run("small radius", func(t *testing.T) {
+		// DM04 is about 200km from the center of DM14.
+		bands, err := ComparePath(home, dx, spots, 100)
+		require.NoError(t, err)
+		require.Len(t, bands, 2)
+		require.Equal(t, Band20m, bands[1].Band)
+		require.Equal(t, 1, bands[1].Direct)
+		require.Equal(t, -15, bands[1].BestSNR)
+	})
+
+	t.Run("bad locators", func(t *testing.T) {
+		_, err := ComparePath("ZZ", dx, spots, 0)
+		require.Equal(t, errInvalidLocator, err)
+		_, err = ComparePath(home, "ZZ", spots, 0)
+		require.Equal(t, errInvalidLocator, err)
+	})
+}
+
+func TestNearPath(t *testing.T) {
+	a, b := LatLon{}, LatLon{Lon: 90}
+
+	require.True(t, nearPath(a, b, LatLon{Lon: 45}, 10))
+	require.True(t, nearPath(a, b, LatLon{Lat: 1, Lon: 45}, 200))
+	require.False(t, nearPath(a, b, LatLon{Lat: 5, Lon: 45}, 200))
+	require.False(t, nearPath(a, b, LatLon{Lon: 100}, 200))
+	require.False(t, nearPath(a, b, LatLon{Lon: -10}, 200))
+}
@@ -0,0 +1,67 @@
+package pskreporter
+
+import (
+	"math"
+)
+
+// Point is a position on a projected plane, in kilometers.
+type Point struct {
+	X float64 // east of the center
+	Y float64 // north of the center
+}
+
+// AzimuthalEquidistant projects positions onto an azimuthal-equidistant plane
+// centered on a point. Straight lines from the center of the projection are
+// great-circle paths, with their true bearing and distance, which makes it the
+// projection used for beam heading maps.
+type AzimuthalEquidistant struct {
+	center LatLon
+}
+
+// NewAzimuthalEquidistant creates a projection centered on the given position.
+func NewAzimuthalEquidistant(center LatLon) *AzimuthalEquidistant {
+	return &AzimuthalEquidistant{center: center}
+}
+
+// NewAzimuthalEquidistantFromLocator creates a projection centered on the given
+// Maidenhead locator.
+func NewAzimuthalEquidistantFromLocator(loc string) (*AzimuthalEquidistant, error) {
+	center, err := ParseLocator(loc)
+	if err != nil {
+		return nil, err
+	}
+	return NewAzimuthalEquidistant(center), nil
+}
+
+// Radius returns the distance from the center to the edge of the projection,
+// which is the center's antipode, in kilometers.
+func (a *AzimuthalEquidistant) Radius() float64 {
+	return math.Pi * earthRadiusKm
+}
+
+// Project returns the position of p on the projected plane.
+func (a *AzimuthalEquidistant) Project(p LatLon) Point {
+	d := a.center.DistanceTo(p)
+	if d == 0 {
+		return Point{}
+	}
+	theta := radians(a.center.BearingTo(p))
+	return Point{X: d * math.Sin(theta), Y: d * math.Cos(theta)}
+}
+
+// ProjectPath returns points along the great-circle path between the sender
+// and the receiver of the spot on the projected plane. It returns false if
+// either end of the spot lacks a valid locator.
+func (a *AzimuthalEquidistant) ProjectPath(s Spot) ([]Point, bool) {
+	from, to, ok := spotEnds(&s)
+	if !ok {
+		return nil, false
+	}
+
+	path := greatCirclePoints(from, to)
+	points := make([]Point, 0, len(path))
+	for _, p := range path {
+		points = append(points, a.Project(p))
+	}
+	return points, true
+}
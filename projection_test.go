@@ -0,0 +1,50 @@
+package pskreporter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzimuthalEquidistant(t *testing.T) {
+	a := NewAzimuthalEquidistant(LatLon{})
+
+	require.Equal(t, Point{}, a.Project(LatLon{}))
+
+	east := a.Project(LatLon{Lon: 90})
+	require.InDelta(t, 10007.5, east.X, 0.1)
+	require.InDelta(t, 0, east.Y, 0.000001)
+
+	north := a.Project(LatLon{Lat: 90})
+	require.InDelta(t, 0, north.X, 0.000001)
+	require.InDelta(t, 10007.5, north.Y, 0.1)
+
+	west := a.Project(LatLon{Lon: -45})
+	require.InDelta(t, -5003.8, west.X, 0.1)
+
+	require.InDelta(t, 20015.1, a.Radius(), 0.1)
+}
+
+func TestAzimuthalEquidistantFromLocator(t *testing.T) {
+	a, err := NewAzimuthalEquidistantFromLocator("DM14cc24")
+	require.NoError(t, err)
+
+	points, ok := a.ProjectPath(testSpots[0])
+	require.True(t, ok)
+	require.Len(t, points, greatCircleSteps+1)
+
+	// The path starts at the center of the projection, so it is a straight
+	// line with the spot's bearing.
+	require.InDelta(t, 0, points[0].X, 1)
+	require.InDelta(t, 0, points[0].Y, 1)
+	end := points[len(points)-1]
+	require.InDelta(t, 2570.1, math.Hypot(end.X, end.Y), 1)
+	require.InDelta(t, 79.6, degrees(math.Atan2(end.X, end.Y)), 0.1)
+
+	_, ok = a.ProjectPath(testSpots[1])
+	require.False(t, ok)
+
+	_, err = NewAzimuthalEquidistantFromLocator("ZZ")
+	require.Equal(t, errInvalidLocator, err)
+}
@@ -0,0 +1,77 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultPushoverAPIURL is the base URL of the Pushover API.
+	DefaultPushoverAPIURL = "https://api.pushover.net"
+
+	// Pushover's length limits.
+	pushoverMaxTitle   = 250
+	pushoverMaxMessage = 1024
+)
+
+// PushoverNotifier sends notifications to a Pushover user or group.
+type PushoverNotifier struct {
+	token string
+	user  string
+	opts  notifierOptions
+}
+
+// NewPushoverNotifier creates a notifier that sends messages with the
+// application token to the user or group key. The notification's title is
+// sent as the Pushover title and the body is rendered with
+// DefaultBodyTemplate unless WithNotificationTemplate is given.
+func NewPushoverNotifier(token, user string, opts ...NotifierOption) (*PushoverNotifier, error) {
+	if token == "" || user == "" {
+		return nil, errors.New("pushover application token and user key are required")
+	}
+	o, err := newNotifierOptions(DefaultPushoverAPIURL, DefaultBodyTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &PushoverNotifier{token: token, user: user, opts: o}, nil
+}
+
+// Notify sends the notification, truncated to Pushover's limits.
+func (p *PushoverNotifier) Notify(ctx context.Context, n Notification) error {
+	text, err := p.opts.render(n)
+	if err != nil {
+		return err
+	}
+
+	form := url.Values{
+		"token":   {p.token},
+		"user":    {p.user},
+		"message": {truncate(text, pushoverMaxMessage)},
+	}
+	if n.Title != "" {
+		form.Set("title", truncate(n.Title, pushoverMaxTitle))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.opts.apiURL+"/1/messages.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	b, err := p.opts.do(req)
+	if err != nil {
+		var resp struct {
+			Errors []string `json:"errors"`
+		}
+		if json.Unmarshal(b, &resp) == nil && len(resp.Errors) > 0 {
+			return fmt.Errorf("%w: %s", err, strings.Join(resp.Errors, "; "))
+		}
+		return err
+	}
+	return nil
+}
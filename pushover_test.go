@@ -0,0 +1,47 @@
+package pskreporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPushoverNotifier(t *testing.T) {
+	var path string
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		r.ParseForm()
+		got = r.PostForm
+		if got.Get("user") == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":0,"errors":["user identifier is invalid"]}`))
+			return
+		}
+		w.Write([]byte(`{"status":1}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewPushoverNotifier("app", "")
+	require.Error(t, err)
+
+	ctx := context.Background()
+	p, err := NewPushoverNotifier("app", "user", WithNotifierAPIURL(srv.URL))
+	require.NoError(t, err)
+	require.NoError(t, p.Notify(ctx, Notification{Title: "New continent", Message: "AG6K heard in Oceania"}))
+	require.Equal(t, "/1/messages.json", path)
+	require.Equal(t, url.Values{
+		"token":   {"app"},
+		"user":    {"user"},
+		"title":   {"New continent"},
+		"message": {"AG6K heard in Oceania"},
+	}, got)
+
+	p, err = NewPushoverNotifier("app", "bad", WithNotifierAPIURL(srv.URL))
+	require.NoError(t, err)
+	require.EqualError(t, p.Notify(ctx, Notification{Message: "hi"}), "unexpected http response 400: user identifier is invalid")
+}
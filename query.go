@@ -1,8 +1,12 @@
 package pskreporter
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
 	"crypto/md5"
-	"encoding/xml"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -10,11 +14,35 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime/debug"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const queryURL = "https://retrieve.pskreporter.info/query"
 
+// modulePath identifies the library in the default User-Agent.
+const modulePath = "github.com/jasonhancock/go-pskreporter"
+
+// DefaultUserAgent is the User-Agent sent with queries unless WithUserAgent is
+// used. It names the library, and its version when the program was built with
+// module support.
+var DefaultUserAgent = defaultUserAgent()
+
+func defaultUserAgent() string {
+	ua := "go-pskreporter"
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, m := range bi.Deps {
+			if m.Path == modulePath && m.Version != "" && m.Version != "(devel)" {
+				ua += "/" + m.Version
+				break
+			}
+		}
+	}
+	return ua + " (+https://" + modulePath + ")"
+}
+
 // Doer is an interface the http.Client conforms to.
 type Doer interface {
 	Do(req *http.Request) (*http.Response, error)
@@ -22,16 +50,34 @@ type Doer interface {
 
 // Client is a client that will communicate with the PSKReporter service.
 type Client struct {
-	doer          Doer
-	baseURL       string
-	cacheDir      string
-	cacheDuration time.Duration
+	doer           Doer
+	baseURL        string
+	cacheDir       string
+	cacheDuration  time.Duration
+	pipeline       *Pipeline
+	appContact     string
+	partialResults bool
+	station        StationProfile
+	region         IARURegion
+	limiter        *queryLimiter
+	limitPolicy    RateLimitPolicy
+	userAgent      string
+	debug          *debugDumper
+	proxy          *url.URL
+	tlsConfig      *tls.Config
+	customDoer     bool
+	endpoints      *endpointSet
+	strictCalls    bool
+	strictModes    bool
+	format         ResponseFormat
 }
 
 // WithHTTPClient set the http client to use.
 func WithHTTPClient(c Doer) ClientOption {
 	return func(o *clientOptions) error {
 		o.doer = c
+		o.customDoer = true
+		o.proxy, o.tlsConfig = nil, nil
 		return nil
 	}
 }
@@ -40,9 +86,10 @@ func WithHTTPClient(c Doer) ClientOption {
 func WithBaseURL(s string) ClientOption {
 	return func(o *clientOptions) error {
 		if _, err := url.Parse(s); err != nil {
-			return err
+			return badOption(err)
 		}
 		o.baseURL = s
+		o.endpoints = nil
 		return nil
 	}
 }
@@ -60,76 +107,393 @@ func WithCacheDir(dir string) ClientOption {
 func WithCacheDuration(dur time.Duration) ClientOption {
 	return func(o *clientOptions) error {
 		if dur < 0 {
-			return errors.New("cache duration must be positive")
+			return badOption(errors.New("cache duration must be positive"))
 		}
 		o.cacheDuration = dur
 		return nil
 	}
 }
 
+// WithPipeline sets the Pipeline used to enrich the spots returned by QuerySpots.
+func WithPipeline(p *Pipeline) ClientOption {
+	return func(o *clientOptions) error {
+		o.pipeline = p
+		return nil
+	}
+}
+
+// WithDefaultAppContact sets the contact email address sent with every query
+// that doesn't set one with WithAppContact.
+func WithDefaultAppContact(email string) ClientOption {
+	return func(o *clientOptions) error {
+		o.appContact = email
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent sent with every query. PSK Reporter's
+// operators ask clients to identify themselves, so applications should name
+// themselves here, for example "myapp/1.2 (me@example.com)". Defaults to
+// DefaultUserAgent.
+func WithUserAgent(ua string) ClientOption {
+	return func(o *clientOptions) error {
+		if strings.TrimSpace(ua) == "" {
+			return badOption(errors.New("user agent must not be empty"))
+		}
+		o.userAgent = ua
+		return nil
+	}
+}
+
+// WithPartialResults makes a query whose response ends part way through, as
+// happens when a proxy times out, return whatever was decoded before the end
+// instead of an error. Such responses have Partial set and are never cached.
+func WithPartialResults() ClientOption {
+	return func(o *clientOptions) error {
+		o.partialResults = true
+		return nil
+	}
+}
+
+// WithStrictCallsigns makes the callsign query options check their callsigns
+// with ValidateCallsign, so that a mistyped callsign fails the query with
+// ErrBadOption instead of returning no reports.
+func WithStrictCallsigns(b bool) ClientOption {
+	return func(o *clientOptions) error {
+		o.strictCalls = b
+		return nil
+	}
+}
+
+// WithStrictModes makes WithMode and WithModes check their modes with
+// NormalizeMode, failing the query with ErrBadOption if a mode isn't known
+// and otherwise sending the mode's usual name. Use RegisterMode to allow a
+// mode that isn't known yet.
+func WithStrictModes(b bool) ClientOption {
+	return func(o *clientOptions) error {
+		o.strictModes = b
+		return nil
+	}
+}
+
+// WithIARURegion sets the IARU region whose band plan the client uses to
+// classify spots into bands and to query bands. Defaults to RegionAny.
+func WithIARURegion(r IARURegion) ClientOption {
+	return func(o *clientOptions) error {
+		if r < RegionAny || r > Region3 {
+			return badOption(fmt.Errorf("unknown IARU region %d", r))
+		}
+		o.region = r
+		return nil
+	}
+}
+
 // New instantiates a new Client.
 func New(opts ...ClientOption) (*Client, error) {
 	o := &clientOptions{
 		doer:          http.DefaultClient,
 		baseURL:       queryURL,
 		cacheDuration: 280 * time.Second,
+		userAgent:     DefaultUserAgent,
 	}
 
+	return o.apply(opts)
+}
+
+// With returns a copy of the client with opts applied on top of its current
+// settings. The copy shares the client's HTTP client and cache directory
+// unless they are overridden.
+func (c *Client) With(opts ...ClientOption) (*Client, error) {
+	o := &clientOptions{
+		doer:           c.doer,
+		baseURL:        c.baseURL,
+		cacheDir:       c.cacheDir,
+		cacheDuration:  c.cacheDuration,
+		pipeline:       c.pipeline,
+		appContact:     c.appContact,
+		partialResults: c.partialResults,
+		station:        c.station,
+		region:         c.region,
+		limiter:        c.limiter,
+		limitPolicy:    c.limitPolicy,
+		userAgent:      c.userAgent,
+		debug:          c.debug,
+		proxy:          c.proxy,
+		tlsConfig:      c.tlsConfig,
+		customDoer:     c.customDoer,
+		endpoints:      c.endpoints,
+		strictCalls:    c.strictCalls,
+		strictModes:    c.strictModes,
+		format:         c.format,
+	}
+	return o.apply(opts)
+}
+
+type clientOptions struct {
+	doer           Doer
+	baseURL        string
+	cacheDir       string
+	cacheDuration  time.Duration
+	pipeline       *Pipeline
+	appContact     string
+	partialResults bool
+	station        StationProfile
+	region         IARURegion
+	limiter        *queryLimiter
+	limitPolicy    RateLimitPolicy
+	userAgent      string
+	debug          *debugDumper
+	proxy          *url.URL
+	tlsConfig      *tls.Config
+	buildTransport bool
+	customDoer     bool
+	endpoints      *endpointSet
+	strictCalls    bool
+	strictModes    bool
+	format         ResponseFormat
+}
+
+// apply applies opts and creates a Client from the result.
+func (o *clientOptions) apply(opts []ClientOption) (*Client, error) {
 	for _, opt := range opts {
 		if err := opt(o); err != nil {
 			return nil, err
 		}
 	}
+	if o.buildTransport {
+		if o.customDoer {
+			return nil, errTransportWithHTTPClient
+		}
+		o.doer = newHTTPClient(o.proxy, o.tlsConfig)
+	}
 
 	return &Client{
-		doer:          o.doer,
-		baseURL:       o.baseURL,
-		cacheDir:      o.cacheDir,
-		cacheDuration: o.cacheDuration,
+		doer:           o.doer,
+		baseURL:        o.baseURL,
+		cacheDir:       o.cacheDir,
+		cacheDuration:  o.cacheDuration,
+		pipeline:       o.pipeline,
+		appContact:     o.appContact,
+		partialResults: o.partialResults,
+		station:        o.station,
+		region:         o.region,
+		limiter:        o.limiter,
+		limitPolicy:    o.limitPolicy,
+		userAgent:      o.userAgent,
+		debug:          o.debug,
+		proxy:          o.proxy,
+		tlsConfig:      o.tlsConfig,
+		customDoer:     o.customDoer,
+		endpoints:      o.endpoints,
+		strictCalls:    o.strictCalls,
+		strictModes:    o.strictModes,
+		format:         o.format,
 	}, nil
 }
 
-type clientOptions struct {
-	doer          Doer
-	baseURL       string
-	cacheDir      string
-	cacheDuration time.Duration
-}
-
 // ClientOption is used to customize the client.
 type ClientOption func(*clientOptions) error
 
 // Query executes a search query against the PSK Reporter API.
 func (c *Client) Query(opts ...QueryOption) (*Response, error) {
+	return c.QueryContext(context.Background(), opts...)
+}
+
+// QueryContext executes a search query against the PSK Reporter API using the
+// provided context.
+func (c *Client) QueryContext(ctx context.Context, opts ...QueryOption) (*Response, error) {
+	r, _, err := c.QueryWithRaw(ctx, opts...)
+	return r, err
+}
+
+// QueryRaw executes a search query and returns the response body exactly as
+// the server sent it, after decompression, for applications that archive or
+// pass on responses. The body is checked to be a valid response, so it is
+// served from the cache like any other.
+func (c *Client) QueryRaw(opts ...QueryOption) ([]byte, error) {
+	return c.QueryRawContext(context.Background(), opts...)
+}
+
+// QueryRawContext is like QueryRaw, using the provided context.
+func (c *Client) QueryRawContext(ctx context.Context, opts ...QueryOption) ([]byte, error) {
+	_, b, err := c.QueryWithRaw(ctx, opts...)
+	return b, err
+}
+
+// QueryWithRaw executes a search query, returning both the decoded response
+// and the response body it was decoded from.
+func (c *Client) QueryWithRaw(ctx context.Context, opts ...QueryOption) (*Response, []byte, error) {
+	return c.query(ctx, opts, nil)
+}
+
+// QueryMeta describes how a query was answered.
+type QueryMeta struct {
+	// Cached is true if the response came from the cache, in which case
+	// StatusCode and Header are empty.
+	Cached bool
+
+	StatusCode int
+	Header     http.Header
+
+	// Duration is how long the query took, from sending the request, or
+	// opening the cached file, to decoding the response.
+	Duration time.Duration
+
+	// Size is the length of the response body in bytes, after decompression.
+	Size int
+
+	// Reports is the number of reception reports in the response, before
+	// those dropped by WithModes, WithCallsignPrefix and
+	// WithMaxReceptionReports.
+	Reports int
+
+	// Host is the host the request was sent to. With WithBaseURLs, that is
+	// the base URL that answered, or the last one tried if none did.
+	Host string
+}
+
+// errorHost returns the host to blame for a failed query of u: the one the
+// request was last sent to, if any.
+func (m *QueryMeta) errorHost(u *url.URL) string {
+	if m.Host != "" {
+		return m.Host
+	}
+	return u.Host
+}
+
+// QueryWithMeta executes a search query, returning the response along with
+// how it was answered. The QueryMeta is returned even if the query fails, with
+// whatever was known by then, so a failed request's status and headers can be
+// inspected.
+func (c *Client) QueryWithMeta(ctx context.Context, opts ...QueryOption) (*Response, *QueryMeta, error) {
+	meta := &QueryMeta{}
+	r, _, err := c.query(ctx, opts, meta)
+	return r, meta, err
+}
+
+// query executes a search query, filling in meta if it isn't nil.
+func (c *Client) query(ctx context.Context, opts []QueryOption, meta *QueryMeta) (*Response, []byte, error) {
+	u, o, err := c.queryURL(opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if meta == nil {
+		meta = &QueryMeta{}
+	}
+	lim := o.decodeLimits()
+	lim.reports = &meta.Reports
+	start := time.Now()
+	r, b, err := c.fetch(ctx, u, meta, lim)
+	meta.Duration = time.Since(start)
+	meta.Size = len(b)
+	if err != nil {
+		return nil, nil, &QueryError{Host: meta.errorHost(u), Params: sanitizeParams(o.vals), Err: err}
+	}
+	r.Query = newQueryParams(o.vals)
+	r.Query.Modes = o.modes
+	r.Query.CallsignPrefix = o.prefix
+	return r, b, nil
+}
+
+// BuildURL validates opts and returns the URL a query made with them would
+// request, without making it. The URL includes the app contact, if any, and
+// uses the client's first base URL.
+func (c *Client) BuildURL(opts ...QueryOption) (string, error) {
+	u, _, err := c.queryURL(opts)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// QueryValues applies opts and returns the query parameters they set, without
+// a client or making a query, so that tools can log, compare or key on a
+// query. Encode the result for the query's canonical string, with parameters
+// sorted by name. Bands are taken from RegionAny's band plan and callsigns
+// and modes aren't checked strictly; use Client.BuildURL to apply a client's
+// settings, including its app contact.
+func QueryValues(opts ...QueryOption) (url.Values, error) {
+	o := queryOptions{vals: make(url.Values)}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+	return o.vals, nil
+}
+
+// queryURL builds the URL for a query made with opts, returning the options
+// along with it.
+func (c *Client) queryURL(opts []QueryOption) (*url.URL, *queryOptions, error) {
 	u, err := url.Parse(c.baseURL)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	o := queryOptions{
-		vals: u.Query(),
+		vals:        u.Query(),
+		region:      c.region,
+		strictCalls: c.strictCalls,
+		strictModes: c.strictModes,
+	}
+	if c.appContact != "" {
+		o.vals.Set("appcontact", c.appContact)
+	}
+	if c.format == FormatJSON {
+		o.vals.Set("callback", jsonpCallback)
 	}
 
 	for _, opt := range opts {
 		if err := opt(&o); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
 	u.RawQuery = o.vals.Encode()
+	return u, &o, nil
+}
+
+// newQueryParams describes the query made with vals.
+func newQueryParams(vals url.Values) QueryParams {
+	p := QueryParams{
+		Callsign:         vals.Get("callsign"),
+		SenderCallsign:   vals.Get("senderCallsign"),
+		ReceiverCallsign: vals.Get("receiverCallsign"),
+		Mode:             vals.Get("mode"),
+		Values:           sanitizeParams(vals),
+	}
+	if vals.Get("modify") == "grid" {
+		p.GridSquare, p.Callsign = p.Callsign, ""
+	}
+	p.FlowStartSeconds, _ = strconv.Atoi(vals.Get("flowStartSeconds"))
+	if p.FrequencyRanges = frequencyRanges(vals); len(p.FrequencyRanges) > 0 {
+		p.LowerFrequency = p.FrequencyRanges[0].Lower
+		p.UpperFrequency = p.FrequencyRanges[0].Upper
+	}
+	return p
+}
 
+// fetch retrieves and decodes the response for u, from the cache if possible,
+// returning the body along with it. meta, if not nil, is told whether the
+// cache was used and about the HTTP response.
+func (c *Client) fetch(ctx context.Context, u *url.URL, meta *QueryMeta, lim decodeLimits) (*Response, []byte, error) {
 	if c.cacheDir != "" {
 		file := filepath.Join(c.cacheDir, hash(u.RawQuery))
 		if fi, err := os.Stat(file); err == nil {
 			if fi.ModTime().After(time.Now().Add(-1 * c.cacheDuration)) {
 				fh, err := os.Open(file)
 				if err != nil {
-					return nil, fmt.Errorf("opening cached file: %w", err)
+					return nil, nil, fmt.Errorf("opening cached file: %w", err)
 				}
 				defer fh.Close()
-				var r Response
-				if err := xml.NewDecoder(fh).Decode(&r); err == nil {
-					return &r, nil
+				if b, err := io.ReadAll(fh); err == nil {
+					if r, err := c.decode(b, lim); err == nil {
+						if meta != nil {
+							meta.Cached = true
+						}
+						return r, b, nil
+					}
 				}
 				// If we're here, there was an error, with the cached result, so go ahead and
 				// make the request.
@@ -137,45 +501,220 @@ func (c *Client) Query(opts ...QueryOption) (*Response, error) {
 		}
 	}
 
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	body, err := c.get(ctx, u, meta)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer body.Close()
+
+	b, err := readBody(body)
+	if err != nil && (!c.partialResults || len(b) == 0) {
+		return nil, nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	r, err := c.decode(b, lim)
+	if err != nil {
+		if c.partialResults && c.format == FormatXML {
+			if r, ok := decodePartial(b); ok {
+				lim.apply(r)
+				return r, b, nil
+			}
+		}
+		return nil, nil, err
+	}
+
+	if c.cacheDir != "" {
+		file := filepath.Join(c.cacheDir, hash(u.RawQuery))
+		fh, err := os.Create(file)
+		if err == nil {
+			defer fh.Close()
+			fh.Write(b)
+		}
+	}
+
+	return r, b, nil
+}
+
+// maxSizeHint caps how much readBody allocates up front on the strength of a
+// Content-Length header.
+const maxSizeHint = 64 << 20
+
+// readBody reads all of body. When do knows the body's length, the buffer is
+// allocated once at that size, rather than grown as with io.ReadAll, which
+// halves the memory allocated for the buffer.
+func readBody(body io.Reader) ([]byte, error) {
+	pb, ok := body.(*prefixedBody)
+	if !ok || pb.size <= 0 {
+		return io.ReadAll(body)
+	}
+
+	size := pb.size
+	if size > maxSizeHint {
+		size = maxSizeHint
+	}
+	// One byte extra so reaching the end doesn't need to grow the buffer.
+	b := make([]byte, 0, size+1)
+	for {
+		if len(b) == cap(b) {
+			b = append(b, 0)[:len(b)]
+		}
+		n, err := body.Read(b[len(b):cap(b)])
+		b = b[:len(b)+n]
+		if err == io.EOF {
+			return b, nil
+		}
+		if err != nil {
+			return b, err
+		}
+	}
+}
+
+// get requests u, returning the body of a successful response. It waits for
+// the client's minimum query interval, if any, and fails over between the
+// client's base URLs if there are several. Responses are requested
+// gzipped, and decompressed here. Throttling pages are turned into
+// ThrottledErrors. meta, if not nil, is given the response's status and
+// headers.
+func (c *Client) get(ctx context.Context, u *url.URL, meta *QueryMeta) (io.ReadCloser, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx, c.limitPolicy, c.cacheDir); err != nil {
+			return nil, err
+		}
+	}
+	if c.endpoints != nil {
+		return c.getFailover(ctx, u, meta)
+	}
+	return c.do(ctx, u, meta)
+}
+
+// do sends a single request for u, returning the body of a successful
+// response.
+func (c *Client) do(ctx context.Context, u *url.URL, meta *QueryMeta) (io.ReadCloser, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
 		return nil, err
 	}
+	// Setting this ourselves stops http.Transport from decompressing for us,
+	// but makes compression work with any Doer.
+	req.Header.Set("Accept-Encoding", "gzip")
+	req.Header.Set("User-Agent", c.userAgent)
 
+	if meta != nil {
+		meta.Host = u.Host
+	}
 	resp, err := c.doer.Do(req)
 	if err != nil {
+		if c.debug != nil {
+			c.debug.dumpError(req, err)
+		}
 		return nil, err
 	}
-	defer resp.Body.Close()
+	if meta != nil {
+		meta.StatusCode = resp.StatusCode
+		meta.Header = resp.Header
+	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected http response %d", resp.StatusCode)
+		body := resp.Body
+		if c.debug != nil {
+			body = c.debug.dump(req, resp, body)
+		}
+		b, _ := io.ReadAll(io.LimitReader(body, errorBodyLimit))
+		resp.Body.Close()
+		statusErr := &HTTPStatusError{Code: resp.StatusCode, Body: b}
+		if err := newRateLimitError(resp, statusErr); err != nil {
+			return nil, err
+		}
+		return nil, statusErr
+	}
+
+	body := resp.Body
+	size := resp.ContentLength
+	if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+		size = -1
+		zr, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			if c.debug != nil {
+				c.debug.dumpError(req, err)
+			}
+			resp.Body.Close()
+			return nil, fmt.Errorf("decompressing response: %w", err)
+		}
+		body = &gzipBody{Reader: zr, body: resp.Body}
+	}
+	if c.debug != nil {
+		body = c.debug.dump(req, resp, body)
+	}
+
+	// The server sometimes answers with an HTML page instead of a response
+	// when it is throttling the client.
+	br := bufio.NewReader(body)
+	if start, _ := br.Peek(512); isHTML(start) {
+		b, _ := io.ReadAll(io.LimitReader(br, throttlePageLimit))
+		if err := throttled(b); err != nil {
+			body.Close()
+			return nil, err
+		}
+		return &prefixedBody{Reader: io.MultiReader(bytes.NewReader(b), br), body: body, size: size}, nil
 	}
+	return &prefixedBody{Reader: br, body: body, size: size}, nil
+}
+
+// gzipBody decompresses a response body, closing it when done.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// QuerySpots executes a search query and returns the reception reports as
+// Spots. Missing DXCC details are filled in from the response's active callsign
+// and receiver lists, then the client's Pipeline, if any, is applied.
+func (c *Client) QuerySpots(opts ...QueryOption) ([]Spot, error) {
+	return c.QuerySpotsContext(context.Background(), opts...)
+}
 
-	b, err := io.ReadAll(resp.Body)
+// QuerySpotsContext is like QuerySpots, but uses the provided context.
+func (c *Client) QuerySpotsContext(ctx context.Context, opts ...QueryOption) ([]Spot, error) {
+	return c.querySpots(ctx, c.pipeline, opts...)
+}
+
+func (c *Client) querySpots(ctx context.Context, p *Pipeline, opts ...QueryOption) ([]Spot, error) {
+	r, err := c.QueryContext(ctx, opts...)
 	if err != nil {
-		return nil, fmt.Errorf("reading response: %w", err)
+		return nil, err
 	}
 
-	var r Response
-	if err := xml.Unmarshal(b, &r); err != nil {
+	spots, err := r.Spots()
+	if err != nil {
 		return nil, err
 	}
 
-	if c.cacheDir != "" {
-		file := filepath.Join(c.cacheDir, hash(u.RawQuery))
-		fh, err := os.Create(file)
-		if err == nil {
-			defer fh.Close()
-			fh.Write(b)
-		}
+	enrichers := []Enricher{DXCCEnricher(r)}
+	if p != nil {
+		enrichers = append(enrichers, p)
+	}
+
+	if err := NewPipeline(enrichers...).Apply(spots); err != nil {
+		return nil, err
 	}
 
-	return &r, nil
+	return spots, nil
 }
 
 type queryOptions struct {
-	vals url.Values
+	vals        url.Values
+	region      IARURegion // the client's region, for band options
+	modes       []string   // modes to filter reports by, set by WithModes
+	strictCalls bool       // whether callsigns are checked by ValidateCallsign
+	strictModes bool       // whether modes are checked by NormalizeMode
+	prefix      string     // callsign prefix to filter reports by
+	maxReports  int        // most reports to decode, zero for no limit
+	noActive    bool       // whether active receivers are dropped when decoding
 }
 
 // QueryOption is used to customize the query.
@@ -184,12 +723,21 @@ type QueryOption func(*queryOptions) error
 // WithSenderCallsign set the sender callsign in the query.
 func WithSenderCallsign(s string) QueryOption {
 	return func(o *queryOptions) error {
+		if o.vals.Get("modify") == "grid" {
+			return errGridExclusive
+		}
+		if o.prefix != "" {
+			return errPrefixExclusive
+		}
 		if _, ok := o.vals["receiverCallsign"]; ok {
 			return errCallsignExclusive
 		}
 		if _, ok := o.vals["callsign"]; ok {
 			return errCallsignExclusive
 		}
+		if err := o.checkCallsign(s); err != nil {
+			return err
+		}
 		o.vals.Set("senderCallsign", s)
 		return nil
 	}
@@ -198,22 +746,51 @@ func WithSenderCallsign(s string) QueryOption {
 // WithReceiverCallsign set the receiver callsign in the query.
 func WithReceiverCallsign(s string) QueryOption {
 	return func(o *queryOptions) error {
+		if o.vals.Get("modify") == "grid" {
+			return errGridExclusive
+		}
+		if o.prefix != "" {
+			return errPrefixExclusive
+		}
 		if _, ok := o.vals["senderCallsign"]; ok {
 			return errCallsignExclusive
 		}
 		if _, ok := o.vals["callsign"]; ok {
 			return errCallsignExclusive
 		}
+		if err := o.checkCallsign(s); err != nil {
+			return err
+		}
 		o.vals.Set("receiverCallsign", s)
 		return nil
 	}
 }
 
-var errCallsignExclusive = errors.New("only one of callsign, senderCallsign, or receiverCallsign can be specified at a time")
+var errCallsignExclusive = badOption(errors.New("only one of callsign, senderCallsign, or receiverCallsign can be specified at a time"))
+
+// checkCallsign checks s with ValidateCallsign if the client asked for
+// strict callsigns.
+func (o *queryOptions) checkCallsign(s string) error {
+	if !o.strictCalls {
+		return nil
+	}
+	if err := ValidateCallsign(s); err != nil {
+		return badOption(err)
+	}
+	return nil
+}
+
+var errGridExclusive = badOption(errors.New("a grid square can't be combined with callsign, senderCallsign, or receiverCallsign"))
 
 // WithCallsign sets the Callsign of interest.
 func WithCallsign(s string) QueryOption {
 	return func(o *queryOptions) error {
+		if o.vals.Get("modify") == "grid" {
+			return errGridExclusive
+		}
+		if o.prefix != "" {
+			return errPrefixExclusive
+		}
 		if _, ok := o.vals["senderCallsign"]; ok {
 			return errCallsignExclusive
 		}
@@ -221,15 +798,172 @@ func WithCallsign(s string) QueryOption {
 			return errCallsignExclusive
 		}
 
+		if err := o.checkCallsign(s); err != nil {
+			return err
+		}
 		o.vals.Set("callsign", s)
 		return nil
 	}
 }
 
+// WithGridSquare searches for reports by Maidenhead grid square, such as
+// "DM04", rather than by callsign. The grid is sent as the callsign along with
+// modify=grid, so it can't be combined with the callsign options.
+func WithGridSquare(grid string) QueryOption {
+	return func(o *queryOptions) error {
+		for _, k := range []string{"callsign", "senderCallsign", "receiverCallsign"} {
+			if _, ok := o.vals[k]; ok {
+				return errGridExclusive
+			}
+		}
+		if o.prefix != "" {
+			return errPrefixExclusive
+		}
+		grid = strings.TrimSpace(grid)
+		loc, err := NormalizeLocator(grid, len(grid))
+		if err != nil {
+			return badOption(fmt.Errorf("grid square %q: %w", grid, err))
+		}
+		o.vals.Set("callsign", loc)
+		o.vals.Set("modify", "grid")
+		return nil
+	}
+}
+
+var errPrefixExclusive = badOption(errors.New("a callsign prefix can't be combined with callsign, senderCallsign, receiverCallsign, or a grid square"))
+
+// WithCallsignPrefix limits the query to reports sent or received by
+// callsigns starting with prefix, such as "VK9" or "VK9*", ignoring case. The
+// query API has no prefix search, so the query asks for every callsign and
+// the reception reports are filtered by the client, as with WithModes; narrow
+// the query with options such as WithBand and WithFlowStartDuration to keep
+// the response small. It can't be combined with the callsign options or
+// WithGridSquare.
+func WithCallsignPrefix(prefix string) QueryOption {
+	return func(o *queryOptions) error {
+		for _, k := range []string{"callsign", "senderCallsign", "receiverCallsign"} {
+			if _, ok := o.vals[k]; ok {
+				return errPrefixExclusive
+			}
+		}
+		prefix = strings.ToUpper(strings.TrimSuffix(strings.TrimSpace(prefix), "*"))
+		if prefix == "" || strings.IndexFunc(prefix, func(r rune) bool {
+			return (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '/'
+		}) >= 0 {
+			return badOption(fmt.Errorf("invalid callsign prefix %q", prefix))
+		}
+		o.prefix = prefix
+		return nil
+	}
+}
+
 // WithMode sets the mode of operation in the query.
 func WithMode(s string) QueryOption {
 	return func(o *queryOptions) error {
+		s, err := o.mode(s)
+		if err != nil {
+			return err
+		}
 		o.vals.Set("mode", s)
+		o.modes = nil
+		return nil
+	}
+}
+
+// mode returns s normalized with NormalizeMode if the client asked for strict
+// modes, or unchanged otherwise.
+func (o *queryOptions) mode(s string) (string, error) {
+	if !o.strictModes {
+		return s, nil
+	}
+	m, ok := NormalizeMode(s)
+	if !ok {
+		return "", badOption(fmt.Errorf("unknown mode %q", s))
+	}
+	return m, nil
+}
+
+var errNoModes = badOption(errors.New("at least one mode must be given"))
+
+// WithModes limits the query to reports in any of the given modes, such as
+// WithModes("FT8", "FT4"). The server only filters by one mode, so with more
+// than one the query asks for every mode and the reception reports are
+// filtered by the client, ignoring case. Only the reception reports are
+// filtered, not the raw response or the active callsigns, and the report
+// limit applies before filtering. It replaces any mode already set.
+func WithModes(modes ...string) QueryOption {
+	return func(o *queryOptions) error {
+		if len(modes) == 0 {
+			return errNoModes
+		}
+		if len(modes) == 1 {
+			return WithMode(modes[0])(o)
+		}
+		normalized := make([]string, 0, len(modes))
+		for _, m := range modes {
+			m, err := o.mode(m)
+			if err != nil {
+				return err
+			}
+			normalized = append(normalized, m)
+		}
+		o.vals.Del("mode")
+		o.modes = normalized
+		return nil
+	}
+}
+
+// decodeLimits returns the limits the options set on decoding the response.
+func (o *queryOptions) decodeLimits() decodeLimits {
+	lim := decodeLimits{maxReports: o.maxReports, noActiveReceivers: o.noActive}
+	if len(o.modes) > 0 || o.prefix != "" {
+		lim.keep = o.keep
+	}
+	return lim
+}
+
+// keep reports whether rr passes the filters set by WithModes and
+// WithCallsignPrefix, if any.
+func (o *queryOptions) keep(rr ReceptionReport) bool {
+	if o.prefix != "" &&
+		!strings.HasPrefix(strings.ToUpper(rr.SenderCallsign), o.prefix) &&
+		!strings.HasPrefix(strings.ToUpper(rr.ReceiverCallsign), o.prefix) {
+		return false
+	}
+	if len(o.modes) == 0 {
+		return true
+	}
+	for _, m := range o.modes {
+		if strings.EqualFold(rr.Mode, m) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithMaxReceptionReports keeps at most n reception reports of the response,
+// after those filtered out by WithModes or WithCallsignPrefix, even if the
+// server ignores WithReportLimit and sends more. The rest aren't decoded, so
+// the Response stays small; the raw response is still read in full for the
+// cache, but QueryToSink stops reading once it has written n spots. It
+// doesn't ask the server for fewer reports; use WithReportLimit for that.
+func WithMaxReceptionReports(n int) QueryOption {
+	return func(o *queryOptions) error {
+		if n < 1 {
+			return badOption(errors.New("max reception reports must be positive"))
+		}
+		o.maxReports = n
+		return nil
+	}
+}
+
+// WithoutActiveReceivers asks the server to leave out the active receivers,
+// as WithActiveReceivers(false) does, and drops any it sends anyway without
+// decoding them.
+func WithoutActiveReceivers() QueryOption {
+	return func(o *queryOptions) error {
+		setFlag(o.vals, "noactive", true)
+		o.noActive = true
 		return nil
 	}
 }
@@ -242,8 +976,10 @@ func WithReportLimit(s int) QueryOption {
 	}
 }
 
-var errFlowStartGreaterDay = errors.New("WithFlowStartSeconds cannot be greater than 24 hours")
-var errFlowStartNotNegative = errors.New("WithFlowStartSeconds must be negative")
+var errFlowStartGreaterDay = badOption(errors.New("WithFlowStartSeconds cannot be greater than 24 hours"))
+var errFlowStartNotNegative = badOption(errors.New("WithFlowStartSeconds must be negative"))
+var errFlowStartDurationGreaterDay = badOption(errors.New("WithFlowStartDuration cannot be greater than 24 hours"))
+var errFlowStartDurationNotPositive = badOption(errors.New("WithFlowStartDuration must be positive"))
 
 // WithFlowStartSeconds is the negative number of seconds to indicate how much
 // data to retrieve. This cannot be more than 24 hours.
@@ -262,6 +998,24 @@ func WithFlowStartSeconds(s int) QueryOption {
 	}
 }
 
+// WithFlowStartDuration retrieves the data from the last d, which cannot be
+// more than 24 hours. It is WithFlowStartSeconds with d converted to a
+// negative number of seconds, rounded up to the next whole second.
+func WithFlowStartDuration(d time.Duration) QueryOption {
+	return func(o *queryOptions) error {
+		if d <= 0 {
+			return errFlowStartDurationNotPositive
+		}
+		if d > maxLookback {
+			return errFlowStartDurationGreaterDay
+		}
+
+		s := int((d + time.Second - 1) / time.Second)
+		o.vals.Set("flowStartSeconds", fmt.Sprintf("%d", -s))
+		return nil
+	}
+}
+
 // WithNoActive will not return the active monitors if non zero.
 func WithNoActive(i int) QueryOption {
 	return func(o *queryOptions) error {
@@ -286,7 +1040,7 @@ func WithRROnly(i int) QueryOption {
 	}
 }
 
-var errLowerFrequencyGreaterThanUpper = errors.New("lower frequency must be less than upper frequency")
+var errLowerFrequencyGreaterThanUpper = badOption(errors.New("lower frequency must be less than upper frequency"))
 
 // WithFrequencyRange sets a lower and upper bound for frequencies. Example: 14000000-14100000
 func WithFrequencyRange(lower, upper int) QueryOption {
@@ -299,6 +1053,97 @@ func WithFrequencyRange(lower, upper int) QueryOption {
 	}
 }
 
+// WithBand limits the query to the given band, such as Band20m or "20m",
+// using its edges in the client's IARU region. It replaces any frequency range
+// already set.
+func WithBand(b Band) QueryOption {
+	return func(o *queryOptions) error {
+		lower, upper, err := bandEdges(b, o.region)
+		if err != nil {
+			return err
+		}
+		o.vals.Set("frange", fmt.Sprintf("%d-%d", lower, upper))
+		return nil
+	}
+}
+
+// WithFrequencyRanges adds frequency ranges to the query, which then returns
+// reports in any of them. Ranges set by earlier options are kept. The ranges
+// must not overlap each other or those already set.
+func WithFrequencyRanges(ranges ...FrequencyRange) QueryOption {
+	return func(o *queryOptions) error {
+		return o.addFrequencyRanges(ranges)
+	}
+}
+
+// WithBands adds bands to the query, such as WithBands("40m", "20m"), using
+// their edges in the client's IARU region. Like WithFrequencyRanges, bands
+// and ranges set by earlier options are kept, and none may overlap.
+func WithBands(bands ...Band) QueryOption {
+	return func(o *queryOptions) error {
+		ranges := make([]FrequencyRange, 0, len(bands))
+		for _, b := range bands {
+			lower, upper, err := bandEdges(b, o.region)
+			if err != nil {
+				return err
+			}
+			ranges = append(ranges, FrequencyRange{Lower: lower, Upper: upper})
+		}
+		return o.addFrequencyRanges(ranges)
+	}
+}
+
+var errNoFrequencyRanges = badOption(errors.New("at least one frequency range or band must be given"))
+
+// addFrequencyRanges adds a frange parameter for each of ranges, checking
+// that none of them overlap.
+func (o *queryOptions) addFrequencyRanges(ranges []FrequencyRange) error {
+	if len(ranges) == 0 {
+		return errNoFrequencyRanges
+	}
+	set := frequencyRanges(o.vals)
+	for _, r := range ranges {
+		if r.Lower > r.Upper {
+			return errLowerFrequencyGreaterThanUpper
+		}
+		for _, s := range set {
+			if r.Overlaps(s) {
+				return badOption(fmt.Errorf("frequency range %s overlaps %s", r, s))
+			}
+		}
+		set = append(set, r)
+	}
+	for _, r := range ranges {
+		o.vals.Add("frange", r.String())
+	}
+	return nil
+}
+
+// frequencyRanges returns the frequency ranges set in vals, skipping any it
+// can't parse.
+func frequencyRanges(vals url.Values) []FrequencyRange {
+	var ranges []FrequencyRange
+	for _, v := range vals["frange"] {
+		if r, ok := parseFrequencyRange(v); ok {
+			ranges = append(ranges, r)
+		}
+	}
+	return ranges
+}
+
+// bandEdges returns the edges of the band named b in region.
+func bandEdges(b Band, region IARURegion) (lower, upper int64, err error) {
+	b = Band(strings.ToLower(strings.TrimSpace(string(b))))
+	if _, _, ok := b.FrequencyRange(); !ok {
+		return 0, 0, badOption(fmt.Errorf("unknown band %q", b))
+	}
+	lower, upper, ok := b.FrequencyRangeIn(region)
+	if !ok {
+		return 0, 0, badOption(fmt.Errorf("band %s is not allocated in IARU region %d", b, region))
+	}
+	return lower, upper, nil
+}
+
 // WithNoLocator will return reception reports without a locator if non-zero.
 func WithNoLocator(i int) QueryOption {
 	return func(o *queryOptions) error {
@@ -315,6 +1160,45 @@ func WithStatistics(i int) QueryOption {
 	}
 }
 
+// WithActiveReceivers sets whether the response includes the active
+// receivers, which it does by default. WithActiveReceivers(false) is
+// WithNoActive(1).
+func WithActiveReceivers(b bool) QueryOption {
+	return func(o *queryOptions) error {
+		setFlag(o.vals, "noactive", !b)
+		return nil
+	}
+}
+
+// WithReceptionReportsOnly sets whether the response includes only the
+// reception reports. WithReceptionReportsOnly(true) is WithRROnly(1).
+func WithReceptionReportsOnly(b bool) QueryOption {
+	return func(o *queryOptions) error {
+		setFlag(o.vals, "rronly", b)
+		return nil
+	}
+}
+
+// WithLocatorRequired sets whether reception reports without a locator are
+// left out, which they are by default. WithLocatorRequired(false) is
+// WithNoLocator(1).
+func WithLocatorRequired(b bool) QueryOption {
+	return func(o *queryOptions) error {
+		setFlag(o.vals, "nolocator", !b)
+		return nil
+	}
+}
+
+// setFlag sets the flag parameter key to 1 if on, and removes it otherwise,
+// leaving the server's default.
+func setFlag(vals url.Values, key string, on bool) {
+	if on {
+		vals.Set(key, "1")
+		return
+	}
+	vals.Del(key)
+}
+
 // WithLastSequenceNumber sets the last sequence number in the query.
 func WithLastSequenceNumber(s string) QueryOption {
 	return func(o *queryOptions) error {
@@ -323,6 +1207,53 @@ func WithLastSequenceNumber(s string) QueryOption {
 	}
 }
 
+// WithLastSequenceNumberUint64 is WithLastSequenceNumber for a sequence
+// number read with LastSequenceNumber.Uint64.
+func WithLastSequenceNumberUint64(n uint64) QueryOption {
+	return func(o *queryOptions) error {
+		o.vals.Set("lastseqno", strconv.FormatUint(n, 10))
+		return nil
+	}
+}
+
+// structuredParams are the query parameters set by the options of this
+// package, which WithParam refuses to set.
+var structuredParams = map[string]string{
+	"appcontact":       "WithAppContact",
+	"callback":         "WithResponseFormat",
+	"callsign":         "WithCallsign",
+	"flowStartSeconds": "WithFlowStartDuration",
+	"frange":           "WithFrequencyRange",
+	"lastseqno":        "WithLastSequenceNumber",
+	"mode":             "WithMode",
+	"modify":           "WithGridSquare",
+	"noactive":         "WithActiveReceivers",
+	"nolocator":        "WithLocatorRequired",
+	"receiverCallsign": "WithReceiverCallsign",
+	"rptlimit":         "WithReportLimit",
+	"rronly":           "WithReceptionReportsOnly",
+	"senderCallsign":   "WithSenderCallsign",
+	"statistics":       "WithStatistics",
+}
+
+// WithParam sets the query parameter key to value, for parameters this
+// package has no option for yet. It returns an error for the parameters that
+// have an option, so that their checks can't be bypassed.
+func WithParam(key, value string) QueryOption {
+	return func(o *queryOptions) error {
+		if key == "" {
+			return badOption(errors.New("query parameter name cannot be empty"))
+		}
+		for k, opt := range structuredParams {
+			if strings.EqualFold(k, key) {
+				return badOption(fmt.Errorf("query parameter %s is set with %s", key, opt))
+			}
+		}
+		o.vals.Set(key, value)
+		return nil
+	}
+}
+
 // hash computes the md5 checksum of the given strings
 func hash(args ...string) string {
 	h := md5.New()
@@ -1,13 +1,19 @@
 package pskreporter
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/require"
@@ -39,6 +45,54 @@ func TestQuery(t *testing.T) {
 			checkResponse(t, resp)
 		})
 
+		t.Run("gzip", func(t *testing.T) {
+			var gzipped bool
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				b, err := os.ReadFile("testdata/output.xml")
+				require.NoError(t, err)
+				if !strings.Contains(req.Header.Get("Accept-Encoding"), "gzip") {
+					w.Write(b)
+					return
+				}
+				gzipped = true
+				w.Header().Set("Content-Encoding", "gzip")
+				zw := gzip.NewWriter(w)
+				zw.Write(b)
+				zw.Close()
+			}))
+			defer svr.Close()
+
+			c, err := New(WithBaseURL(svr.URL), WithCacheDir(t.TempDir()))
+			require.NoError(t, err)
+
+			resp, err := c.Query(WithCallsign("AG6K"))
+			require.NoError(t, err)
+			require.True(t, gzipped)
+			checkResponse(t, resp)
+
+			// The cache holds the decompressed response.
+			resp, err = c.Query(WithCallsign("AG6K"))
+			require.NoError(t, err)
+			checkResponse(t, resp)
+
+			n, err := c.QueryToSink(context.Background(), &recordingSink{}, WithCallsign("AG6K"))
+			require.NoError(t, err)
+			require.Equal(t, len(resp.ReceptionReports), n)
+		})
+
+		t.Run("bad gzip", func(t *testing.T) {
+			svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Encoding", "gzip")
+				w.Write([]byte("<receptionReports/>"))
+			}))
+			defer svr.Close()
+
+			c, err := New(WithBaseURL(svr.URL))
+			require.NoError(t, err)
+			_, err = c.Query()
+			require.Error(t, err)
+		})
+
 		t.Run("caching enabled", func(t *testing.T) {
 			mux := http.NewServeMux()
 			count := 0
@@ -178,6 +232,339 @@ func TestQuery(t *testing.T) {
 	})
 }
 
+func TestQuerySpots(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/foo", func(w http.ResponseWriter, req *http.Request) {
+		http.ServeFile(w, req, "testdata/output.xml")
+	})
+
+	svr := httptest.NewServer(mux)
+	defer svr.Close()
+
+	t.Run("without pipeline", func(t *testing.T) {
+		c, err := New(WithBaseURL(svr.URL + "/foo"))
+		require.NoError(t, err)
+
+		spots, err := c.QuerySpots(WithSenderCallsign("AG6K"))
+		require.NoError(t, err)
+		require.Len(t, spots, 340)
+		require.Equal(t, Band(""), spots[0].Band)
+	})
+
+	t.Run("with pipeline", func(t *testing.T) {
+		c, err := New(
+			WithBaseURL(svr.URL+"/foo"),
+			WithPipeline(DefaultPipeline()),
+		)
+		require.NoError(t, err)
+
+		spots, err := c.QuerySpots(WithSenderCallsign("AG6K"))
+		require.NoError(t, err)
+		require.Len(t, spots, 340)
+		require.Equal(t, Band20m, spots[0].Band)
+		require.NotZero(t, spots[0].Distance)
+	})
+}
+
+func TestQueryPartialResults(t *testing.T) {
+	b, err := os.ReadFile("testdata/output.xml")
+	require.NoError(t, err)
+
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		// Claim the full length but stop half way, like a proxy timing out.
+		w.Header().Set("Content-Length", strconv.Itoa(len(b)))
+		w.Write(b[:len(b)/2])
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+	_, err = c.Query(WithCallsign("AG6K"))
+	require.Error(t, err)
+
+	dir := t.TempDir()
+	c, err = c.With(WithPartialResults(), WithCacheDir(dir))
+	require.NoError(t, err)
+	resp, err := c.Query(WithCallsign("AG6K"))
+	require.NoError(t, err)
+	require.True(t, resp.Partial)
+	require.NotEmpty(t, resp.ActiveReceivers)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries, "partial responses must not be cached")
+
+	t.Run("complete response", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Write(b)
+		}))
+		defer svr.Close()
+
+		c, err := c.With(WithBaseURL(svr.URL))
+		require.NoError(t, err)
+		resp, err := c.Query(WithCallsign("AG6K"))
+		require.NoError(t, err)
+		require.False(t, resp.Partial)
+		checkResponse(t, resp)
+	})
+}
+
+func TestQueryParams(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.ServeFile(w, req, "testdata/output.xml")
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL), WithDefaultAppContact("me@example.com"))
+	require.NoError(t, err)
+
+	resp, err := c.Query(
+		WithSenderCallsign("AG6K"),
+		WithMode("FT8"),
+		WithFlowStartSeconds(-3600),
+		WithFrequencyRange(14000000, 14350000),
+	)
+	require.NoError(t, err)
+	require.Equal(t, QueryParams{
+		SenderCallsign:   "AG6K",
+		Mode:             "FT8",
+		FlowStartSeconds: -3600,
+		LowerFrequency:   14000000,
+		UpperFrequency:   14350000,
+		FrequencyRanges:  []FrequencyRange{{Lower: 14000000, Upper: 14350000}},
+		Values: url.Values{
+			"senderCallsign":   {"AG6K"},
+			"mode":             {"FT8"},
+			"flowStartSeconds": {"-3600"},
+			"frange":           {"14000000-14350000"},
+		},
+	}, resp.Query)
+}
+
+func TestClientWith(t *testing.T) {
+	doer := &http.Client{}
+	c, err := New(
+		WithHTTPClient(doer),
+		WithCacheDir("/tmp"),
+		WithDefaultAppContact("me@example.com"),
+	)
+	require.NoError(t, err)
+
+	d, err := c.With(WithCacheDuration(time.Minute))
+	require.NoError(t, err)
+	require.NotSame(t, c, d)
+	require.Same(t, doer, d.doer)
+	require.Equal(t, "/tmp", d.cacheDir)
+	require.Equal(t, "me@example.com", d.appContact)
+	require.Equal(t, time.Minute, d.cacheDuration)
+	require.Equal(t, 280*time.Second, c.cacheDuration)
+
+	_, err = c.With(WithCacheDuration(-time.Minute))
+	require.Error(t, err)
+
+	d, err = c.With(WithIARURegion(Region1))
+	require.NoError(t, err)
+	require.Equal(t, Region1, d.region)
+	e, err := d.With()
+	require.NoError(t, err)
+	require.Equal(t, Region1, e.region)
+
+	_, err = c.With(WithIARURegion(IARURegion(4)))
+	require.Error(t, err)
+}
+
+func TestWithFrequencyRanges(t *testing.T) {
+	c, err := New(WithBaseURL("https://example.com/query"))
+	require.NoError(t, err)
+
+	u, err := c.BuildURL(
+		WithBand(Band40m),
+		WithBands(Band20m, "15m"),
+		WithFrequencyRanges(FrequencyRange{Lower: 50313000, Upper: 50316000}),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/query?frange=7000000-7300000&frange=14000000-14350000&frange=21000000-21450000&frange=50313000-50316000", u)
+
+	p := newQueryParams(url.Values{"frange": {"7000000-7300000", "junk", "14000000-14350000"}})
+	require.Equal(t, []FrequencyRange{{7000000, 7300000}, {14000000, 14350000}}, p.FrequencyRanges)
+	require.Equal(t, int64(7000000), p.LowerFrequency)
+	require.Equal(t, int64(7300000), p.UpperFrequency)
+}
+
+func TestWithGridSquare(t *testing.T) {
+	c, err := New(WithBaseURL("https://example.com/query"))
+	require.NoError(t, err)
+
+	_, err = c.BuildURL(WithGridSquare("DM0"))
+	require.ErrorIs(t, err, ErrBadOption)
+	_, err = c.BuildURL(WithGridSquare("ZZ99"))
+	require.ErrorIs(t, err, ErrBadOption)
+
+	p := newQueryParams(url.Values{"callsign": {"DM04"}, "modify": {"grid"}})
+	require.Equal(t, "DM04", p.GridSquare)
+	require.Empty(t, p.Callsign)
+}
+
+func TestWithModes(t *testing.T) {
+	var mode []string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mode = req.URL.Query()["mode"]
+		w.Write([]byte(`<receptionReports>
+<receptionReport senderCallsign="AG6K" frequency="14074000" mode="FT8"/>
+<receptionReport senderCallsign="AG6K" frequency="14080000" mode="FT4"/>
+<receptionReport senderCallsign="AG6K" frequency="14030000" mode="CW"/>
+<receptionReport senderCallsign="AG6K" frequency="14250000" mode="SSB"/>
+</receptionReports>`))
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	r, err := c.Query(WithModes("cw", "SSB"))
+	require.NoError(t, err)
+	require.Nil(t, mode)
+	require.Len(t, r.ReceptionReports, 2)
+	require.Equal(t, "CW", r.ReceptionReports[0].Mode)
+	require.Equal(t, "SSB", r.ReceptionReports[1].Mode)
+	require.Equal(t, []string{"cw", "SSB"}, r.Query.Modes)
+
+	r, err = c.Query(WithModes("FT4"))
+	require.NoError(t, err)
+	require.Equal(t, []string{"FT4"}, mode)
+	require.Nil(t, r.Query.Modes)
+}
+
+func TestStrictCallsigns(t *testing.T) {
+	c, err := New(WithBaseURL("https://example.com/query"))
+	require.NoError(t, err)
+	_, err = c.BuildURL(WithCallsign("AG6"))
+	require.NoError(t, err)
+
+	c, err = c.With(WithStrictCallsigns(true))
+	require.NoError(t, err)
+	for _, opt := range []QueryOption{WithCallsign("AG6"), WithSenderCallsign("AG6"), WithReceiverCallsign("AG6")} {
+		_, err = c.BuildURL(opt)
+		require.ErrorIs(t, err, ErrBadOption)
+	}
+	u, err := c.BuildURL(WithSenderCallsign("AG6K/P"))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/query?senderCallsign=AG6K%2FP", u)
+}
+
+func TestStrictModes(t *testing.T) {
+	c, err := New(WithBaseURL("https://example.com/query"))
+	require.NoError(t, err)
+	u, err := c.BuildURL(WithMode("ft8"))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/query?mode=ft8", u)
+
+	c, err = c.With(WithStrictModes(true))
+	require.NoError(t, err)
+	u, err = c.BuildURL(WithMode("ft8"))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/query?mode=FT8", u)
+
+	_, err = c.BuildURL(WithMode("FT9"))
+	require.ErrorIs(t, err, ErrBadOption)
+	_, err = c.BuildURL(WithModes("FT8", "FT9"))
+	require.ErrorIs(t, err, ErrBadOption)
+}
+
+func TestQueryValues(t *testing.T) {
+	vals, err := QueryValues(WithReportLimit(10), WithCallsign("AG6K"), WithBands(Band40m, Band20m))
+	require.NoError(t, err)
+	require.Equal(t, url.Values{
+		"callsign": {"AG6K"},
+		"rptlimit": {"10"},
+		"frange":   {"7000000-7300000", "14000000-14350000"},
+	}, vals)
+	require.Equal(t, "callsign=AG6K&frange=7000000-7300000&frange=14000000-14350000&rptlimit=10", vals.Encode())
+
+	vals, err = QueryValues()
+	require.NoError(t, err)
+	require.Empty(t, vals)
+
+	_, err = QueryValues(WithCallsign("AG6K"), WithSenderCallsign("W5CJ"))
+	require.Equal(t, errCallsignExclusive, err)
+}
+
+func TestWithCallsignPrefix(t *testing.T) {
+	var got url.Values
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = req.URL.Query()
+		w.Write([]byte(`<receptionReports>
+<receptionReport senderCallsign="VK9XX" receiverCallsign="W5CJ" frequency="14074000" mode="FT8"/>
+<receptionReport senderCallsign="AG6K" receiverCallsign="vk9nz" frequency="14074000" mode="FT4"/>
+<receptionReport senderCallsign="VK2ABC" receiverCallsign="W5CJ" frequency="14074000" mode="FT8"/>
+</receptionReports>`))
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	r, err := c.Query(WithCallsignPrefix("vk9*"), WithBand(Band20m))
+	require.NoError(t, err)
+	require.Equal(t, url.Values{"frange": {"14000000-14350000"}}, got)
+	require.Equal(t, "VK9", r.Query.CallsignPrefix)
+	require.Len(t, r.ReceptionReports, 2)
+	require.Equal(t, "VK9XX", r.ReceptionReports[0].SenderCallsign)
+	require.Equal(t, "vk9nz", r.ReceptionReports[1].ReceiverCallsign)
+
+	r, err = c.Query(WithCallsignPrefix("VK9"), WithModes("FT8", "JT65"))
+	require.NoError(t, err)
+	require.Len(t, r.ReceptionReports, 1)
+
+	n, err := c.QueryToSink(context.Background(), &recordingSink{}, WithCallsignPrefix("VK"))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+
+	for _, opt := range []QueryOption{WithCallsign("AG6K"), WithSenderCallsign("AG6K"), WithReceiverCallsign("AG6K"), WithGridSquare("DM04")} {
+		_, err = c.BuildURL(WithCallsignPrefix("VK9"), opt)
+		require.Equal(t, errPrefixExclusive, err)
+	}
+}
+
+func TestDecodeLimitOptions(t *testing.T) {
+	var got url.Values
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = req.URL.Query()
+		http.ServeFile(w, req, "testdata/output.xml")
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	r, err := c.Query(WithMaxReceptionReports(2), WithoutActiveReceivers())
+	require.NoError(t, err)
+	require.Equal(t, url.Values{"noactive": {"1"}}, got)
+	require.Len(t, r.ReceptionReports, 2)
+	require.Nil(t, r.ActiveReceivers)
+	require.NotEmpty(t, r.ActiveCallsigns)
+
+	sink := &recordingSink{}
+	n, err := c.QueryToSink(context.Background(), sink, WithMaxReceptionReports(3))
+	require.NoError(t, err)
+	require.Equal(t, 3, n)
+	require.Len(t, sink.spots, 3)
+	require.Equal(t, 1, sink.flushes)
+}
+
+func TestWithBandRegion(t *testing.T) {
+	c, err := New(WithBaseURL("https://example.com/query"), WithIARURegion(Region1))
+	require.NoError(t, err)
+
+	u, err := c.BuildURL(WithBand(Band40m))
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/query?frange=7000000-7200000", u)
+
+	_, err = c.BuildURL(WithBand(Band125cm))
+	require.ErrorIs(t, err, ErrBadOption)
+	require.EqualError(t, err, "band 1.25m is not allocated in IARU region 1")
+}
+
 type doerError struct{}
 
 func (d *doerError) Do(*http.Request) (*http.Response, error) {
@@ -221,6 +608,31 @@ func TestQueryOptions(t *testing.T) {
 				[]QueryOption{WithFlowStartSeconds(-10)},
 				map[string]string{"flowStartSeconds": "-10"},
 			},
+			{
+				"WithFlowStartDuration",
+				[]QueryOption{WithFlowStartDuration(30 * time.Minute)},
+				map[string]string{"flowStartSeconds": "-1800"},
+			},
+			{
+				"WithFlowStartDuration rounds up",
+				[]QueryOption{WithFlowStartDuration(1500 * time.Millisecond)},
+				map[string]string{"flowStartSeconds": "-2"},
+			},
+			{
+				"WithFlowStartDuration 24 hours",
+				[]QueryOption{WithFlowStartDuration(24 * time.Hour)},
+				map[string]string{"flowStartSeconds": "-86400"},
+			},
+			{
+				"WithModes one mode",
+				[]QueryOption{WithModes("FT8")},
+				map[string]string{"mode": "FT8"},
+			},
+			{
+				"WithModes replaces mode",
+				[]QueryOption{WithMode("FT8"), WithModes("FT4", "JT65")},
+				map[string]string{"mode": ""},
+			},
 			{
 				"WithAppContact",
 				[]QueryOption{WithAppContact("foo@example.com")},
@@ -231,11 +643,31 @@ func TestQueryOptions(t *testing.T) {
 				[]QueryOption{WithFrequencyRange(123, 456)},
 				map[string]string{"frange": "123-456"},
 			},
+			{
+				"WithBand",
+				[]QueryOption{WithBand(Band20m)},
+				map[string]string{"frange": "14000000-14350000"},
+			},
+			{
+				"WithBand by name",
+				[]QueryOption{WithFrequencyRange(1, 2), WithBand("17M")},
+				map[string]string{"frange": "18068000-18168000"},
+			},
+			{
+				"WithGridSquare",
+				[]QueryOption{WithGridSquare(" dm04 ")},
+				map[string]string{"callsign": "DM04", "modify": "grid"},
+			},
 			{
 				"WithLastSequenceNumber",
 				[]QueryOption{WithLastSequenceNumber("abc123")},
 				map[string]string{"lastseqno": "abc123"},
 			},
+			{
+				"WithLastSequenceNumberUint64",
+				[]QueryOption{WithLastSequenceNumberUint64(18446744073709551615)},
+				map[string]string{"lastseqno": "18446744073709551615"},
+			},
 			{
 				"WithNoActive",
 				[]QueryOption{WithNoActive(2)},
@@ -251,6 +683,41 @@ func TestQueryOptions(t *testing.T) {
 				[]QueryOption{WithRROnly(2)},
 				map[string]string{"rronly": "2"},
 			},
+			{
+				"WithActiveReceivers false",
+				[]QueryOption{WithActiveReceivers(false)},
+				map[string]string{"noactive": "1"},
+			},
+			{
+				"WithActiveReceivers true",
+				[]QueryOption{WithNoActive(1), WithActiveReceivers(true)},
+				map[string]string{"noactive": ""},
+			},
+			{
+				"WithReceptionReportsOnly",
+				[]QueryOption{WithReceptionReportsOnly(true)},
+				map[string]string{"rronly": "1"},
+			},
+			{
+				"WithReceptionReportsOnly false",
+				[]QueryOption{WithRROnly(1), WithReceptionReportsOnly(false)},
+				map[string]string{"rronly": ""},
+			},
+			{
+				"WithLocatorRequired false",
+				[]QueryOption{WithLocatorRequired(false)},
+				map[string]string{"nolocator": "1"},
+			},
+			{
+				"WithLocatorRequired true",
+				[]QueryOption{WithNoLocator(1), WithLocatorRequired(true)},
+				map[string]string{"nolocator": ""},
+			},
+			{
+				"WithParam",
+				[]QueryOption{WithParam("encap", "1")},
+				map[string]string{"encap": "1"},
+			},
 			{
 				"WithStatistics",
 				[]QueryOption{WithStatistics(2)},
@@ -288,6 +755,102 @@ func TestQueryOptions(t *testing.T) {
 				nil,
 				errLowerFrequencyGreaterThanUpper,
 			},
+			{
+				"WithBand unknown",
+				WithBand("11m"),
+				nil,
+				badOption(errors.New(`unknown band "11m"`)),
+			},
+			{
+				"WithFrequencyRanges none",
+				WithFrequencyRanges(),
+				nil,
+				errNoFrequencyRanges,
+			},
+			{
+				"WithFrequencyRanges lower > upper",
+				WithFrequencyRanges(FrequencyRange{Lower: 2, Upper: 1}),
+				nil,
+				errLowerFrequencyGreaterThanUpper,
+			},
+			{
+				"WithFrequencyRanges overlapping",
+				WithFrequencyRanges(FrequencyRange{Lower: 1, Upper: 10}, FrequencyRange{Lower: 10, Upper: 20}),
+				nil,
+				badOption(errors.New("frequency range 10-20 overlaps 1-10")),
+			},
+			{
+				"WithBands overlapping range already set",
+				WithBands(Band20m),
+				url.Values{"frange": []string{"14070000-14080000"}},
+				badOption(errors.New("frequency range 14000000-14350000 overlaps 14070000-14080000")),
+			},
+			{
+				"WithGridSquare - callsign set",
+				WithGridSquare("DM04"),
+				url.Values{"callsign": []string{"foo"}},
+				errGridExclusive,
+			},
+			{
+				"WithGridSquare - receiverCallsign set",
+				WithGridSquare("DM04"),
+				url.Values{"receiverCallsign": []string{"foo"}},
+				errGridExclusive,
+			},
+			{
+				"WithCallsign - grid set",
+				WithCallsign("a"),
+				url.Values{"callsign": []string{"DM04"}, "modify": []string{"grid"}},
+				errGridExclusive,
+			},
+			{
+				"WithSenderCallsign - grid set",
+				WithSenderCallsign("a"),
+				url.Values{"callsign": []string{"DM04"}, "modify": []string{"grid"}},
+				errGridExclusive,
+			},
+			{
+				"WithModes none",
+				WithModes(),
+				nil,
+				errNoModes,
+			},
+			{
+				"WithParam empty",
+				WithParam("", "1"),
+				nil,
+				badOption(errors.New("query parameter name cannot be empty")),
+			},
+			{
+				"WithParam structured",
+				WithParam("Mode", "FT8"),
+				nil,
+				badOption(errors.New("query parameter Mode is set with WithMode")),
+			},
+			{
+				"WithCallsignPrefix empty",
+				WithCallsignPrefix("*"),
+				nil,
+				badOption(errors.New(`invalid callsign prefix ""`)),
+			},
+			{
+				"WithCallsignPrefix invalid",
+				WithCallsignPrefix("VK-9"),
+				nil,
+				badOption(errors.New(`invalid callsign prefix "VK-9"`)),
+			},
+			{
+				"WithCallsignPrefix - callsign set",
+				WithCallsignPrefix("VK9"),
+				url.Values{"callsign": []string{"foo"}},
+				errPrefixExclusive,
+			},
+			{
+				"WithMaxReceptionReports not positive",
+				WithMaxReceptionReports(0),
+				nil,
+				badOption(errors.New("max reception reports must be positive")),
+			},
 			{
 				"WithFlowStartSeconds not negative",
 				WithFlowStartSeconds(1),
@@ -300,6 +863,18 @@ func TestQueryOptions(t *testing.T) {
 				nil,
 				errFlowStartGreaterDay,
 			},
+			{
+				"WithFlowStartDuration not positive",
+				WithFlowStartDuration(0),
+				nil,
+				errFlowStartDurationNotPositive,
+			},
+			{
+				"WithFlowStartDuration greater 1 day",
+				WithFlowStartDuration(24*time.Hour + time.Second),
+				nil,
+				errFlowStartDurationGreaterDay,
+			},
 			{
 				"WithCallsign - senderCallsign set",
 				WithCallsign("a"),
@@ -355,3 +930,153 @@ func TestQueryOptions(t *testing.T) {
 func TestHash(t *testing.T) {
 	require.Equal(t, "e99a18c428cb38d5f260853678922e03", hash("abc123"))
 }
+
+func TestUserAgent(t *testing.T) {
+	var ua string
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		ua = req.UserAgent()
+		w.Write([]byte("<receptionReports/>"))
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+	_, err = c.Query()
+	require.NoError(t, err)
+	require.Equal(t, DefaultUserAgent, ua)
+	require.True(t, strings.HasPrefix(ua, "go-pskreporter"))
+	require.Contains(t, ua, "github.com/jasonhancock/go-pskreporter")
+
+	d, err := c.With(WithUserAgent("myapp/1.2 (me@example.com)"))
+	require.NoError(t, err)
+	_, err = d.Query()
+	require.NoError(t, err)
+	require.Equal(t, "myapp/1.2 (me@example.com)", ua)
+
+	_, err = c.With(WithUserAgent(" "))
+	require.Error(t, err)
+}
+
+func TestQueryRaw(t *testing.T) {
+	want, err := os.ReadFile("testdata/output.xml")
+	require.NoError(t, err)
+
+	var requests int
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests++
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		zw.Write(want)
+		zw.Close()
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL), WithCacheDir(t.TempDir()))
+	require.NoError(t, err)
+
+	b, err := c.QueryRaw(WithCallsign("AG6K"))
+	require.NoError(t, err)
+	require.Equal(t, want, b)
+
+	r, b, err := c.QueryWithRaw(context.Background(), WithCallsign("AG6K"))
+	require.NoError(t, err)
+	require.Equal(t, want, b)
+	checkResponse(t, r)
+	require.Equal(t, "AG6K", r.Query.Callsign)
+	require.Equal(t, 1, requests)
+
+	_, err = c.QueryRaw(WithCallsign("AG6K"), WithSenderCallsign("AG6K"))
+	require.Equal(t, errCallsignExclusive, err)
+}
+
+func TestQueryWithMeta(t *testing.T) {
+	status := http.StatusOK
+	body := `<receptionReports><receptionReport mode="FT8"/><receptionReport mode="FT8"/><lastSequenceNumber value="42"/></receptionReports>`
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("X-Test", "yes")
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL), WithCacheDir(t.TempDir()))
+	require.NoError(t, err)
+
+	r, meta, err := c.QueryWithMeta(context.Background(), WithCallsign("AG6K"), WithMaxReceptionReports(1))
+	require.NoError(t, err)
+	require.Equal(t, "42", r.LastSequenceNumber.Value)
+	require.Len(t, r.ReceptionReports, 1)
+	require.Equal(t, 2, meta.Reports)
+	require.False(t, meta.Cached)
+	require.Equal(t, http.StatusOK, meta.StatusCode)
+	require.Equal(t, "yes", meta.Header.Get("X-Test"))
+	require.Equal(t, len(body), meta.Size)
+	require.Greater(t, meta.Duration, time.Duration(0))
+
+	_, meta, err = c.QueryWithMeta(context.Background(), WithCallsign("AG6K"))
+	require.NoError(t, err)
+	require.True(t, meta.Cached)
+	require.Zero(t, meta.StatusCode)
+	require.Nil(t, meta.Header)
+	require.Equal(t, len(body), meta.Size)
+	require.Equal(t, 2, meta.Reports)
+
+	status = http.StatusBadGateway
+	_, meta, err = c.QueryWithMeta(context.Background(), WithCallsign("W5CJ"))
+	require.Error(t, err)
+	require.NotNil(t, meta)
+	require.Equal(t, http.StatusBadGateway, meta.StatusCode)
+	require.Equal(t, "yes", meta.Header.Get("X-Test"))
+	require.Zero(t, meta.Size)
+}
+
+func TestReadBody(t *testing.T) {
+	data := []byte(strings.Repeat("<receptionReports/>", 100))
+	for _, size := range []int64{-1, 0, 10, int64(len(data)), int64(len(data)) + 50} {
+		b, err := readBody(&prefixedBody{Reader: bytes.NewReader(data), size: size})
+		require.NoError(t, err, size)
+		require.Equal(t, data, b, size)
+		if size == int64(len(data)) {
+			require.Equal(t, len(data)+1, cap(b))
+		}
+	}
+
+	b, err := readBody(&prefixedBody{Reader: io.MultiReader(bytes.NewReader(data[:10]), iotest.ErrReader(io.ErrUnexpectedEOF)), size: int64(len(data))})
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.Equal(t, data[:10], b)
+}
+
+func BenchmarkReadBody(b *testing.B) {
+	data, err := os.ReadFile("testdata/output.xml")
+	require.NoError(b, err)
+
+	for _, bm := range []struct {
+		name string
+		size int64
+	}{
+		{"unknown size", -1},
+		{"known size", int64(len(data))},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				if _, err := readBody(&prefixedBody{Reader: bytes.NewReader(data), size: bm.size}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildURL(t *testing.T) {
+	c, err := New(WithDefaultAppContact("me@example.com"))
+	require.NoError(t, err)
+
+	u, err := c.BuildURL(WithSenderCallsign("AG6K"), WithFlowStartSeconds(-900))
+	require.NoError(t, err)
+	require.Equal(t, queryURL+"?appcontact=me%40example.com&flowStartSeconds=-900&senderCallsign=AG6K", u)
+
+	_, err = c.BuildURL(WithCallsign("AG6K"), WithReceiverCallsign("W5CJ"))
+	require.Equal(t, errCallsignExclusive, err)
+}
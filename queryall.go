@@ -0,0 +1,80 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// QuerySpec is one of the queries run by QueryAll.
+type QuerySpec struct {
+	Name    string // optional label, e.g. the callsign being queried
+	Options []QueryOption
+}
+
+// QueryResult is the outcome of one QuerySpec.
+type QueryResult struct {
+	Spec     QuerySpec
+	Response *Response
+	Err      error
+}
+
+// QueryAllOption is used to configure QueryAll.
+type QueryAllOption func(*queryAllOptions) error
+
+type queryAllOptions struct {
+	concurrency int
+}
+
+// WithQueryConcurrency sets how many of QueryAll's queries may run at once.
+// Defaults to 1, running them one after another.
+func WithQueryConcurrency(n int) QueryAllOption {
+	return func(o *queryAllOptions) error {
+		if n < 1 {
+			return errors.New("query concurrency must be positive")
+		}
+		o.concurrency = n
+		return nil
+	}
+}
+
+// QueryAll runs many queries, such as one per member of a club, returning a
+// result for each spec in the same order. A failed query doesn't stop the
+// others; its error is in its result. Queries are spaced out by the client's
+// minimum query interval, so use WithMinQueryInterval to keep within PSK
+// Reporter's limits. If ctx is done before every query has run, the queries
+// that didn't run have ctx's error as theirs, and QueryAll returns it too.
+func (c *Client) QueryAll(ctx context.Context, specs []QuerySpec, opts ...QueryAllOption) ([]QueryResult, error) {
+	o := queryAllOptions{concurrency: 1}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]QueryResult, len(specs))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, o.concurrency)
+	for i, spec := range specs {
+		results[i].Spec = spec
+		if err := ctx.Err(); err != nil {
+			results[i].Err = err
+			continue
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(r *QueryResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			r.Response, r.Err = c.QueryContext(ctx, r.Spec.Options...)
+		}(&results[i])
+	}
+	wg.Wait()
+	return results, ctx.Err()
+}
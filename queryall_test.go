@@ -0,0 +1,95 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryAll(t *testing.T) {
+	var (
+		inFlight, maxInFlight int32
+		mu                    sync.Mutex
+	)
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		mu.Lock()
+		if n > maxInFlight {
+			maxInFlight = n
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+
+		if req.URL.Query().Get("callsign") == "BAD" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`<receptionReports><lastSequenceNumber value="` + req.URL.Query().Get("callsign") + `"/></receptionReports>`))
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	var specs []QuerySpec
+	for _, cs := range []string{"AG6K", "W5CJ", "BAD", "N7HPX", "K1ABC"} {
+		specs = append(specs, QuerySpec{Name: cs, Options: []QueryOption{WithCallsign(cs)}})
+	}
+
+	t.Run("sequential", func(t *testing.T) {
+		maxInFlight = 0
+		results, err := c.QueryAll(context.Background(), specs)
+		require.NoError(t, err)
+		require.Len(t, results, len(specs))
+		for i, r := range results {
+			require.Equal(t, specs[i].Name, r.Spec.Name)
+			if r.Spec.Name == "BAD" {
+				var se *HTTPStatusError
+				require.True(t, errors.As(r.Err, &se))
+				require.Nil(t, r.Response)
+				continue
+			}
+			require.NoError(t, r.Err)
+			require.Equal(t, r.Spec.Name, r.Response.LastSequenceNumber.Value)
+		}
+		require.Equal(t, int32(1), maxInFlight)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		maxInFlight = 0
+		results, err := c.QueryAll(context.Background(), specs, WithQueryConcurrency(2))
+		require.NoError(t, err)
+		require.Equal(t, "K1ABC", results[4].Response.LastSequenceNumber.Value)
+		require.Equal(t, int32(2), maxInFlight)
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		c, err := c.With(WithMinQueryInterval(20 * time.Millisecond))
+		require.NoError(t, err)
+		start := time.Now()
+		_, err = c.QueryAll(context.Background(), specs[:3], WithQueryConcurrency(3))
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		results, err := c.QueryAll(ctx, specs)
+		require.ErrorIs(t, err, context.Canceled)
+		for _, r := range results {
+			require.ErrorIs(t, r.Err, context.Canceled)
+		}
+	})
+
+	_, err = c.QueryAll(context.Background(), specs, WithQueryConcurrency(0))
+	require.Error(t, err)
+}
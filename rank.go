@@ -0,0 +1,184 @@
+package pskreporter
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RankWeights are the relative weights of the components of a spot's rank.
+// Only their proportions matter.
+type RankWeights struct {
+	Recency  float64
+	Distance float64
+	SNR      float64
+	Receiver float64 // the quality of the receiving station
+}
+
+// DefaultRankWeights weighs every component equally.
+var DefaultRankWeights = RankWeights{Recency: 1, Distance: 1, SNR: 1, Receiver: 1}
+
+// Levels used to scale rank components.
+const (
+	// DefaultRecencyHalfLife is how old a spot is when its recency score has
+	// halved, unless set with WithRecencyHalfLife.
+	DefaultRecencyHalfLife = 15 * time.Minute
+
+	// rankFullDistance is the distance in kilometers that earns a full
+	// distance score, about half way around the world.
+	rankFullDistance = 20000
+
+	// rankMinSNR and rankMaxSNR are the signal reports in dB that earn no and
+	// a full SNR score.
+	rankMinSNR = -30
+	rankMaxSNR = 10
+
+	// rankNeutral is the score given to components that can't be judged.
+	rankNeutral = 0.5
+)
+
+// RankerOption is used to configure a Ranker.
+type RankerOption func(*rankerOptions) error
+
+type rankerOptions struct {
+	weights   RankWeights
+	halfLife  time.Duration
+	receivers []ReceiverScore
+}
+
+// WithRankWeights sets the weights of the rank components. Defaults to
+// DefaultRankWeights.
+func WithRankWeights(w RankWeights) RankerOption {
+	return func(o *rankerOptions) error {
+		if w.Recency < 0 || w.Distance < 0 || w.SNR < 0 || w.Receiver < 0 {
+			return errors.New("rank weights must not be negative")
+		}
+		if w.Recency+w.Distance+w.SNR+w.Receiver == 0 {
+			return errors.New("at least one rank weight must be positive")
+		}
+		o.weights = w
+		return nil
+	}
+}
+
+// WithRecencyHalfLife sets how old a spot is when its recency score has
+// halved. Defaults to DefaultRecencyHalfLife.
+func WithRecencyHalfLife(d time.Duration) RankerOption {
+	return func(o *rankerOptions) error {
+		if d <= 0 {
+			return errors.New("recency half life must be positive")
+		}
+		o.halfLife = d
+		return nil
+	}
+}
+
+// WithReceiverScores sets the receiver scores used to judge receiver quality,
+// such as from ScoreReceivers over a longer history. By default receivers are
+// scored from the spots being ranked.
+func WithReceiverScores(scores []ReceiverScore) RankerOption {
+	return func(o *rankerOptions) error {
+		o.receivers = scores
+		return nil
+	}
+}
+
+// RankedSpot is a spot with its rank. Each component is from 0 to 1.
+type RankedSpot struct {
+	Spot Spot
+
+	Recency  float64
+	Distance float64
+	SNR      float64
+	Receiver float64
+	Score    float64 // weighted average of the above
+}
+
+// Ranker orders spots from most to least interesting, scoring each by a
+// weighted combination of its recency, distance, SNR and the quality of its
+// receiver.
+type Ranker struct {
+	weights   RankWeights
+	halfLife  time.Duration
+	receivers map[string]float64 // nil to score from the spots being ranked
+}
+
+// NewRanker creates a Ranker.
+func NewRanker(opts ...RankerOption) (*Ranker, error) {
+	o := rankerOptions{
+		weights:  DefaultRankWeights,
+		halfLife: DefaultRecencyHalfLife,
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	r := &Ranker{weights: o.weights, halfLife: o.halfLife}
+	if o.receivers != nil {
+		r.receivers = receiverScoreMap(o.receivers)
+	}
+	return r, nil
+}
+
+// Rank scores spots as of now and returns them ordered by score, highest
+// first. Spots with equal scores keep their order.
+func (r *Ranker) Rank(spots []Spot, now time.Time) []RankedSpot {
+	receivers := r.receivers
+	if receivers == nil {
+		receivers = receiverScoreMap(ScoreReceivers(spots, nil))
+	}
+
+	w := r.weights
+	total := w.Recency + w.Distance + w.SNR + w.Receiver
+	ranked := make([]RankedSpot, 0, len(spots))
+	for _, s := range spots {
+		rs := RankedSpot{
+			Spot:     s,
+			Recency:  r.recency(s, now),
+			Distance: rankNeutral,
+			SNR:      rankNeutral,
+			Receiver: rankNeutral,
+		}
+		if d, ok := spotDistance(&s); ok {
+			rs.Distance = capOne(d / rankFullDistance)
+		}
+		if s.HasSNR {
+			rs.SNR = math.Max(0, capOne(float64(s.SNR-rankMinSNR)/(rankMaxSNR-rankMinSNR)))
+		}
+		if q, ok := receivers[strings.ToUpper(s.ReceiverCallsign)]; ok {
+			rs.Receiver = q
+		}
+		rs.Score = (w.Recency*rs.Recency + w.Distance*rs.Distance + w.SNR*rs.SNR + w.Receiver*rs.Receiver) / total
+		ranked = append(ranked, rs)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
+
+// recency halves with every half life of age. Spots from the future count as
+// brand new, and spots without a time get a neutral score.
+func (r *Ranker) recency(s Spot, now time.Time) float64 {
+	if s.Time.IsZero() {
+		return rankNeutral
+	}
+	age := now.Sub(s.Time)
+	if age <= 0 {
+		return 1
+	}
+	return math.Pow(0.5, float64(age)/float64(r.halfLife))
+}
+
+func receiverScoreMap(scores []ReceiverScore) map[string]float64 {
+	m := make(map[string]float64, len(scores))
+	for _, rs := range scores {
+		m[strings.ToUpper(rs.Callsign)] = rs.Score
+	}
+	return m
+}
@@ -0,0 +1,63 @@
+package pskreporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRanker(t *testing.T) {
+	now := time.Unix(1599163380, 0).UTC()
+	spots := []Spot{
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Distance: 1500, SNR: -20, HasSNR: true, Time: now.Add(-time.Hour)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "VK2XYZ", Distance: 12000, SNR: -5, HasSNR: true, Time: now.Add(-time.Minute)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "N7HPX", Distance: 900, SNR: -24, HasSNR: true, Time: now.Add(-15 * time.Minute)},
+	}
+
+	r, err := NewRanker(WithReceiverScores([]ReceiverScore{{Callsign: "w5cj", Score: 0.9}}))
+	require.NoError(t, err)
+
+	ranked := r.Rank(spots, now)
+	require.Len(t, ranked, 3)
+	require.Equal(t, "VK2XYZ", ranked[0].Spot.ReceiverCallsign)
+	for i := 1; i < len(ranked); i++ {
+		require.GreaterOrEqual(t, ranked[i-1].Score, ranked[i].Score)
+	}
+
+	n7hpx := ranked[2]
+	require.Equal(t, "N7HPX", n7hpx.Spot.ReceiverCallsign)
+	require.InDelta(t, 0.5, n7hpx.Recency, 1e-9)
+	require.InDelta(t, 900.0/20000, n7hpx.Distance, 1e-9)
+	require.InDelta(t, 0.15, n7hpx.SNR, 1e-9)
+	require.Equal(t, rankNeutral, n7hpx.Receiver)
+	require.InDelta(t, (n7hpx.Recency+n7hpx.Distance+n7hpx.SNR+n7hpx.Receiver)/4, n7hpx.Score, 1e-9)
+	require.Equal(t, 0.9, ranked[1].Receiver)
+
+	t.Run("weights", func(t *testing.T) {
+		r, err := NewRanker(WithRankWeights(RankWeights{Recency: 1}))
+		require.NoError(t, err)
+		ranked := r.Rank(spots, now)
+		require.Equal(t, []string{"VK2XYZ", "N7HPX", "W5CJ"}, []string{
+			ranked[0].Spot.ReceiverCallsign, ranked[1].Spot.ReceiverCallsign, ranked[2].Spot.ReceiverCallsign,
+		})
+	})
+
+	t.Run("unknown components", func(t *testing.T) {
+		r, err := NewRanker()
+		require.NoError(t, err)
+		ranked := r.Rank([]Spot{{ReceiverCallsign: "W5CJ", Time: now.Add(time.Minute)}}, now)
+		require.Equal(t, 1.0, ranked[0].Recency)
+		require.Equal(t, rankNeutral, ranked[0].Distance)
+		require.Equal(t, rankNeutral, ranked[0].SNR)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		_, err := NewRanker(WithRankWeights(RankWeights{}))
+		require.Error(t, err)
+		_, err = NewRanker(WithRankWeights(RankWeights{Recency: -1, SNR: 2}))
+		require.Error(t, err)
+		_, err = NewRanker(WithRecencyHalfLife(0))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,122 @@
+package pskreporter
+
+import (
+	"sort"
+	"time"
+)
+
+// Trend is the direction in which a spot rate is moving.
+type Trend int
+
+// The possible trends.
+const (
+	TrendSteady Trend = iota
+	TrendRising
+	TrendFalling
+)
+
+// String returns the lower case name of the trend.
+func (t Trend) String() string {
+	switch t {
+	case TrendRising:
+		return "rising"
+	case TrendFalling:
+		return "falling"
+	}
+	return "steady"
+}
+
+// MarshalText implements encoding.TextMarshaler so trends are encoded by name
+// in JSON.
+func (t Trend) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// SpotRate is the rate of spots for one band and mode.
+type SpotRate struct {
+	Band     Band    `json:"band"`
+	Mode     string  `json:"mode"`
+	Current  float64 `json:"current"`  // spots per minute in the most recent half of the window
+	Previous float64 `json:"previous"` // spots per minute in the earlier half of the window
+	Trend    Trend   `json:"trend"`
+}
+
+// minTrendChange is the relative change between the two halves of the window
+// needed for the rate to be considered rising or falling.
+const minTrendChange = 0.2
+
+// SpotRates computes the spots per minute for each band and mode over the
+// window ending at now. The window is split into two halves, and the rate in
+// the most recent half is compared with the earlier one to determine the
+// trend. Results are ordered by band frequency, then by mode. It returns nil
+// for a window too short to split, such as zero or a negative one.
+func SpotRates(spots []Spot, window time.Duration, now time.Time) []SpotRate {
+	type key struct {
+		band Band
+		mode string
+	}
+	type counts struct {
+		current, previous int
+	}
+
+	half := window / 2
+	if half <= 0 {
+		return nil
+	}
+	mid := now.Add(-half)
+	start := now.Add(-window)
+
+	byKey := make(map[key]*counts)
+	for i := range spots {
+		s := &spots[i]
+		if s.Time.Before(start) || s.Time.After(now) {
+			continue
+		}
+		b, ok := spotBand(s)
+		if !ok {
+			continue
+		}
+
+		k := key{band: b, mode: s.Mode}
+		c, ok := byKey[k]
+		if !ok {
+			c = &counts{}
+			byKey[k] = c
+		}
+		if s.Time.Before(mid) {
+			c.previous++
+		} else {
+			c.current++
+		}
+	}
+
+	order := make(map[Band]int)
+	for i, b := range Bands() {
+		order[b] = i
+	}
+
+	rates := make([]SpotRate, 0, len(byKey))
+	for k, c := range byKey {
+		r := SpotRate{
+			Band:     k.band,
+			Mode:     k.mode,
+			Current:  float64(c.current) / half.Minutes(),
+			Previous: float64(c.previous) / half.Minutes(),
+		}
+		switch {
+		case r.Current > r.Previous*(1+minTrendChange):
+			r.Trend = TrendRising
+		case r.Current < r.Previous*(1-minTrendChange):
+			r.Trend = TrendFalling
+		}
+		rates = append(rates, r)
+	}
+
+	sort.Slice(rates, func(i, j int) bool {
+		if rates[i].Band != rates[j].Band {
+			return order[rates[i].Band] < order[rates[j].Band]
+		}
+		return rates[i].Mode < rates[j].Mode
+	})
+	return rates
+}
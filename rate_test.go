@@ -0,0 +1,55 @@
+package pskreporter
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpotRates(t *testing.T) {
+	now := time.Date(2020, 9, 3, 20, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) time.Time { return now.Add(-d) }
+
+	var spots []Spot
+	add := func(b Band, mode string, n int, at time.Time) {
+		for i := 0; i < n; i++ {
+			spots = append(spots, Spot{Band: b, Mode: mode, Time: at})
+		}
+	}
+
+	// 20m FT8 opening: 5 spots in the first half, 20 in the second.
+	add(Band20m, "FT8", 5, ago(8*time.Minute))
+	add(Band20m, "FT8", 20, ago(2*time.Minute))
+	// 20m FT4 steady.
+	add(Band20m, "FT4", 10, ago(7*time.Minute))
+	add(Band20m, "FT4", 11, ago(time.Minute))
+	// 40m FT8 closing.
+	add(Band40m, "FT8", 10, ago(6*time.Minute))
+	add(Band40m, "FT8", 2, ago(4*time.Minute))
+	// Outside the window, and without a band.
+	add(Band40m, "FT8", 50, ago(time.Hour))
+	add("", "FT8", 50, ago(time.Minute))
+
+	rates := SpotRates(spots, 10*time.Minute, now)
+	require.Equal(t, []SpotRate{
+		{Band: Band40m, Mode: "FT8", Current: 0.4, Previous: 2, Trend: TrendFalling},
+		{Band: Band20m, Mode: "FT4", Current: 2.2, Previous: 2, Trend: TrendSteady},
+		{Band: Band20m, Mode: "FT8", Current: 4, Previous: 1, Trend: TrendRising},
+	}, rates)
+
+	b, err := json.Marshal(rates[0])
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"trend":"falling"`)
+
+	for _, window := range []time.Duration{0, -time.Minute, time.Nanosecond} {
+		require.Nil(t, SpotRates(spots, window, now), window)
+	}
+}
+
+func TestTrendString(t *testing.T) {
+	require.Equal(t, "steady", TrendSteady.String())
+	require.Equal(t, "rising", TrendRising.String())
+	require.Equal(t, "falling", TrendFalling.String())
+}
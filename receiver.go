@@ -0,0 +1,141 @@
+package pskreporter
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// ReceiverScore rates how trustworthy a receiving station is.
+type ReceiverScore struct {
+	Callsign string
+	Locator  string
+	Reports  int
+
+	// SNRStdDev is the standard deviation of the signal reports, or zero if
+	// there were fewer than two of them.
+	SNRStdDev float64
+
+	Volume      float64 // 0 to 1, from the number of reports
+	Consistency float64 // 0 to 1, from the spread of the signal reports
+	Precision   float64 // 0 to 1, from the length of the locator
+	Score       float64 // average of the above
+}
+
+// Levels used to scale receiver scores.
+const (
+	// receiverFullVolume is the number of reports that earns a full volume
+	// score. The score grows logarithmically up to it.
+	receiverFullVolume = 100
+
+	// receiverMaxSNRStdDev is the spread of signal reports in dB at which the
+	// consistency score drops to zero.
+	receiverMaxSNRStdDev = 20
+
+	// receiverNeutralConsistency is the consistency score given to receivers
+	// with too few signal reports to judge.
+	receiverNeutralConsistency = 0.5
+)
+
+// ScoreReceivers ranks receiving stations by their report volume and the
+// consistency of their signal reports in spots, and by the precision of their
+// locator from either spots or the active receiver list. Stations that only
+// appear in receivers are included with no volume. The result is ordered from
+// best to worst.
+func ScoreReceivers(spots []Spot, receivers []ActiveReceiver) []ReceiverScore {
+	type acc struct {
+		callsign string
+		locator  string
+		reports  int
+		snrs     []float64
+	}
+	byCall := make(map[string]*acc)
+	get := func(call string) *acc {
+		key := strings.ToUpper(call)
+		a, ok := byCall[key]
+		if !ok {
+			a = &acc{callsign: call}
+			byCall[key] = a
+		}
+		return a
+	}
+
+	for _, s := range spots {
+		if s.ReceiverCallsign == "" {
+			continue
+		}
+		a := get(s.ReceiverCallsign)
+		a.reports++
+		if s.HasSNR {
+			a.snrs = append(a.snrs, float64(s.SNR))
+		}
+		if len(s.ReceiverLocator) > len(a.locator) {
+			a.locator = s.ReceiverLocator
+		}
+	}
+	for _, r := range receivers {
+		if r.Callsign == "" {
+			continue
+		}
+		a := get(r.Callsign)
+		if len(r.Locator) > len(a.locator) {
+			a.locator = r.Locator
+		}
+	}
+
+	scores := make([]ReceiverScore, 0, len(byCall))
+	for _, a := range byCall {
+		rs := ReceiverScore{
+			Callsign:    a.callsign,
+			Locator:     a.locator,
+			Reports:     a.reports,
+			Volume:      capOne(math.Log1p(float64(a.reports)) / math.Log1p(receiverFullVolume)),
+			Consistency: receiverNeutralConsistency,
+			Precision:   locatorPrecision(a.locator),
+		}
+		if len(a.snrs) >= 2 {
+			rs.SNRStdDev = stdDev(a.snrs)
+			rs.Consistency = 1 - capOne(rs.SNRStdDev/receiverMaxSNRStdDev)
+		}
+		rs.Score = (rs.Volume + rs.Consistency + rs.Precision) / 3
+		scores = append(scores, rs)
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].Callsign < scores[j].Callsign
+	})
+	return scores
+}
+
+// locatorPrecision rates a locator by how precisely it places the station.
+func locatorPrecision(loc string) float64 {
+	if _, err := ParseLocator(loc); err != nil {
+		return 0
+	}
+	switch len(loc) {
+	case 2:
+		return 0.1
+	case 4:
+		return 0.5
+	case 6:
+		return 0.9
+	}
+	return 1
+}
+
+func stdDev(vals []float64) float64 {
+	var sum float64
+	for _, v := range vals {
+		sum += v
+	}
+	mean := sum / float64(len(vals))
+
+	var sq float64
+	for _, v := range vals {
+		sq += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sq / float64(len(vals)))
+}
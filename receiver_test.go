@@ -0,0 +1,60 @@
+package pskreporter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreReceivers(t *testing.T) {
+	var spots []Spot
+	// A busy skimmer with a precise locator and steady reports.
+	for i := 0; i < 100; i++ {
+		spots = append(spots, Spot{ReceiverCallsign: "W5CJ", ReceiverLocator: "EM55db92", SNR: -10 + i%2, HasSNR: true})
+	}
+	// A one-off reporter with a coarse locator.
+	spots = append(spots, Spot{ReceiverCallsign: "N7HPX", ReceiverLocator: "DN13", SNR: -5, HasSNR: true})
+	// An erratic reporter.
+	for _, snr := range []int{-25, 15, -25, 15} {
+		spots = append(spots, Spot{ReceiverCallsign: "K1ABC", ReceiverLocator: "FN31pr", SNR: snr, HasSNR: true})
+	}
+
+	receivers := []ActiveReceiver{
+		{Callsign: "n7hpx", Locator: "DN13vj"},
+		{Callsign: "DL0046SWL", Locator: "JO63HM"},
+	}
+
+	scores := ScoreReceivers(spots, receivers)
+	require.Len(t, scores, 4)
+
+	require.Equal(t, "W5CJ", scores[0].Callsign)
+	require.Equal(t, 100, scores[0].Reports)
+	require.Equal(t, 1.0, scores[0].Volume)
+	require.Equal(t, 0.5, scores[0].SNRStdDev)
+	require.InDelta(t, 0.975, scores[0].Consistency, 0.000001)
+	require.Equal(t, 1.0, scores[0].Precision)
+
+	require.Equal(t, "N7HPX", scores[1].Callsign)
+	require.Equal(t, "DN13vj", scores[1].Locator)
+	require.InDelta(t, math.Log1p(1)/math.Log1p(100), scores[1].Volume, 0.000001)
+	require.Equal(t, receiverNeutralConsistency, scores[1].Consistency)
+	require.Equal(t, 0.9, scores[1].Precision)
+
+	require.Equal(t, "DL0046SWL", scores[2].Callsign)
+	require.Zero(t, scores[2].Reports)
+	require.Zero(t, scores[2].Volume)
+
+	require.Equal(t, "K1ABC", scores[3].Callsign)
+	require.Equal(t, 20.0, scores[3].SNRStdDev)
+	require.Zero(t, scores[3].Consistency)
+}
+
+func TestLocatorPrecision(t *testing.T) {
+	require.Equal(t, 0.0, locatorPrecision(""))
+	require.Equal(t, 0.0, locatorPrecision("ZZ99"))
+	require.Equal(t, 0.1, locatorPrecision("EM"))
+	require.Equal(t, 0.5, locatorPrecision("EM55"))
+	require.Equal(t, 0.9, locatorPrecision("EM55db"))
+	require.Equal(t, 1.0, locatorPrecision("EM55db92"))
+}
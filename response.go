@@ -2,6 +2,10 @@ package pskreporter
 
 import (
 	"encoding/xml"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 // query response is defined here: https://pskreporter.info/pskdev.html
@@ -17,6 +21,35 @@ type Response struct {
 	ReceptionReports    []ReceptionReport   `xml:"receptionReport"`
 	SenderSearch        SenderSearch        `xml:"senderSearch"`
 	ActiveCallsigns     []ActiveCallsign    `xml:"activeCallsign"`
+
+	// Query holds the parameters of the query that produced the response.
+	Query QueryParams `xml:"-"`
+
+	// Partial is set if the response ended part way through and only holds
+	// what was decoded before the end. See WithPartialResults.
+	Partial bool `xml:"-"`
+}
+
+// QueryParams describes the query that produced a Response. Fields for
+// parameters that weren't set are left empty.
+type QueryParams struct {
+	Callsign         string
+	SenderCallsign   string
+	ReceiverCallsign string
+	GridSquare       string // set instead of Callsign by WithGridSquare
+	CallsignPrefix   string // set by WithCallsignPrefix
+	Mode             string
+	Modes            []string // set by WithModes with more than one mode
+	FlowStartSeconds int
+	LowerFrequency   int64
+	UpperFrequency   int64
+
+	// FrequencyRanges holds every frequency range of the query. Lower and
+	// UpperFrequency are the edges of the first.
+	FrequencyRanges []FrequencyRange
+
+	// Values holds every query parameter, except the app contact.
+	Values url.Values
 }
 
 // ActiveCallsign represents an active call sign in the response.
@@ -77,3 +110,58 @@ type LastSequenceNumber struct {
 	Text  string `xml:",chardata"`
 	Value string `xml:"value,attr"`
 }
+
+// Uint64 returns the sequence number as an integer, for passing to
+// WithLastSequenceNumberUint64 when polling for new reports.
+func (n LastSequenceNumber) Uint64() (uint64, error) {
+	v, err := strconv.ParseUint(strings.TrimSpace(n.Value), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing last sequence number: %w", err)
+	}
+	return v, nil
+}
+
+// Clone returns a deep copy of the response.
+func (r *Response) Clone() *Response {
+	if r == nil {
+		return nil
+	}
+	c := *r
+	c.ActiveReceivers = append(r.ActiveReceivers[:0:0], r.ActiveReceivers...)
+	c.ReceptionReports = append(r.ReceptionReports[:0:0], r.ReceptionReports...)
+	c.ActiveCallsigns = append(r.ActiveCallsigns[:0:0], r.ActiveCallsigns...)
+	c.Query.FrequencyRanges = append(r.Query.FrequencyRanges[:0:0], r.Query.FrequencyRanges...)
+	c.Query.Modes = append(r.Query.Modes[:0:0], r.Query.Modes...)
+	if r.Query.Values != nil {
+		c.Query.Values = copyValues(r.Query.Values)
+	}
+	return &c
+}
+
+// ResponseCounts summarizes the size of a Response.
+type ResponseCounts struct {
+	Reports         int
+	ActiveReceivers int
+	ActiveCallsigns int
+	UniqueSenders   int
+	UniqueReceivers int
+}
+
+// Counts returns the number of items in the response and of distinct
+// callsigns among its reception reports. It is computed on each call, so it
+// reflects any changes made to the response.
+func (r *Response) Counts() ResponseCounts {
+	senders := make(map[string]struct{})
+	receivers := make(map[string]struct{})
+	for _, rr := range r.ReceptionReports {
+		senders[strings.ToUpper(rr.SenderCallsign)] = struct{}{}
+		receivers[strings.ToUpper(rr.ReceiverCallsign)] = struct{}{}
+	}
+	return ResponseCounts{
+		Reports:         len(r.ReceptionReports),
+		ActiveReceivers: len(r.ActiveReceivers),
+		ActiveCallsigns: len(r.ActiveCallsigns),
+		UniqueSenders:   len(senders),
+		UniqueReceivers: len(receivers),
+	}
+}
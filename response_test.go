@@ -2,21 +2,27 @@ package pskreporter
 
 import (
 	"encoding/xml"
+	"net/url"
 	"os"
+	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 )
 
 func TestParsing(t *testing.T) {
+	checkResponse(t, loadResponse(t))
+}
+
+func loadResponse(t *testing.T) *Response {
 	fh, err := os.Open("testdata/output.xml")
 	require.NoError(t, err)
 	defer fh.Close()
 
 	var resp Response
 	require.NoError(t, xml.NewDecoder(fh).Decode(&resp))
-
-	checkResponse(t, &resp)
+	return &resp
 }
 
 func checkResponse(t *testing.T, resp *Response) {
@@ -38,3 +44,72 @@ func checkResponse(t *testing.T, resp *Response) {
 	require.Len(t, resp.ActiveReceivers, 4695)
 	require.Equal(t, "DL0046SWL", resp.ActiveReceivers[0].Callsign)
 }
+
+func TestClone(t *testing.T) {
+	require.Nil(t, (*Response)(nil).Clone())
+
+	orig := loadResponse(t)
+	orig.Query = QueryParams{
+		Callsign:        "AG6K",
+		Modes:           []string{"FT8", "FT4"},
+		FrequencyRanges: []FrequencyRange{{Lower: 14000000, Upper: 14350000}},
+		Values:          url.Values{"callsign": {"AG6K"}},
+	}
+
+	c := orig.Clone()
+	require.Equal(t, orig, c)
+
+	c.ReceptionReports[0].SenderCallsign = "CHANGED"
+	c.ActiveReceivers[0].Callsign = "CHANGED"
+	c.ActiveCallsigns[0].Callsign = "CHANGED"
+	c.Query.Values.Set("callsign", "CHANGED")
+	c.Query.Modes[0] = "CHANGED"
+	c.Query.FrequencyRanges[0].Lower = 0
+	c.ReceptionReports = c.ReceptionReports[:1]
+
+	checkResponse(t, orig)
+	require.NotEqual(t, "CHANGED", orig.ReceptionReports[0].SenderCallsign)
+	require.NotEqual(t, "CHANGED", orig.ActiveCallsigns[0].Callsign)
+	require.Equal(t, "AG6K", orig.Query.Values.Get("callsign"))
+	require.Equal(t, "FT8", orig.Query.Modes[0])
+	require.Equal(t, int64(14000000), orig.Query.FrequencyRanges[0].Lower)
+
+	// Empty slices stay empty rather than becoming nil, and nil ones stay nil.
+	empty := &Response{
+		ReceptionReports: []ReceptionReport{},
+		ActiveReceivers:  []ActiveReceiver{},
+		Query:            QueryParams{Modes: []string{}, Values: url.Values{"mode": {}}},
+	}
+	require.True(t, reflect.DeepEqual(empty, empty.Clone()))
+	require.Nil(t, empty.Clone().ActiveCallsigns)
+}
+
+func TestLastSequenceNumberUint64(t *testing.T) {
+	resp := loadResponse(t)
+	n, err := resp.LastSequenceNumber.Uint64()
+	require.NoError(t, err)
+	require.Equal(t, resp.LastSequenceNumber.Value, strconv.FormatUint(n, 10))
+
+	_, err = LastSequenceNumber{}.Uint64()
+	require.Error(t, err)
+	_, err = LastSequenceNumber{Value: "-1"}.Uint64()
+	require.Error(t, err)
+}
+
+func TestCounts(t *testing.T) {
+	resp := loadResponse(t)
+	c := resp.Counts()
+	require.Equal(t, 340, c.Reports)
+	require.Equal(t, 4695, c.ActiveReceivers)
+	require.Equal(t, 20, c.ActiveCallsigns)
+	require.Equal(t, 1, c.UniqueSenders)
+	require.Equal(t, 340, c.UniqueReceivers)
+
+	require.Equal(t, ResponseCounts{}, (&Response{}).Counts())
+	require.Equal(t, ResponseCounts{Reports: 2, UniqueSenders: 1, UniqueReceivers: 1}, (&Response{
+		ReceptionReports: []ReceptionReport{
+			{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ"},
+			{SenderCallsign: "ag6k", ReceiverCallsign: "w5cj"},
+		},
+	}).Counts())
+}
@@ -0,0 +1,85 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetentionPolicy limits how many spots a store keeps. Zero values disable the
+// corresponding limit. When a limit is exceeded the oldest spots are removed
+// first.
+type RetentionPolicy struct {
+	// MaxAge removes spots older than this.
+	MaxAge time.Duration
+
+	// MaxSpots caps the total number of spots kept.
+	MaxSpots int
+
+	// BandQuotas caps the number of spots kept per band. Spots with no known
+	// band are only subject to MaxAge and MaxSpots.
+	BandQuotas map[Band]int
+}
+
+// Pruner is implemented by stores that can enforce a RetentionPolicy.
+type Pruner interface {
+	// Prune removes the spots that fall outside policy as of now, returning
+	// how many were removed.
+	Prune(ctx context.Context, policy RetentionPolicy, now time.Time) (int, error)
+}
+
+// RunRetention prunes p according to policy immediately and then once every
+// interval until ctx is cancelled. Errors from pruning are passed to onError,
+// if not nil, and don't stop the loop. The interval must be positive.
+func RunRetention(ctx context.Context, p Pruner, policy RetentionPolicy, interval time.Duration, onError func(error)) error {
+	if interval <= 0 {
+		return errors.New("retention interval must be positive")
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Prune(ctx, policy, time.Now()); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			if onError != nil {
+				onError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// retained reports which of spots, ordered by time, are kept by policy as of
+// now.
+func retained(spots []Spot, policy RetentionPolicy, now time.Time) []bool {
+	keep := make([]bool, len(spots))
+	perBand := make(map[Band]int)
+	total := 0
+
+	// Walk from newest to oldest so the quotas favor recent spots.
+	for i := len(spots) - 1; i >= 0; i-- {
+		s := &spots[i]
+		if policy.MaxAge > 0 && now.Sub(s.Time) > policy.MaxAge {
+			continue
+		}
+		if policy.MaxSpots > 0 && total >= policy.MaxSpots {
+			continue
+		}
+		if b, ok := spotBand(s); ok {
+			if quota, ok := policy.BandQuotas[b]; ok && perBand[b] >= quota {
+				continue
+			}
+			perBand[b]++
+		}
+		keep[i] = true
+		total++
+	}
+	return keep
+}
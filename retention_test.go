@@ -0,0 +1,98 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetained(t *testing.T) {
+	now := time.Unix(1599163380, 0).UTC()
+	spots := []Spot{
+		{Frequency: 14097100, Time: now.Add(-3 * time.Hour)},
+		{Frequency: 7038600, Time: now.Add(-90 * time.Minute)},
+		{Frequency: 14097100, Time: now.Add(-time.Hour)},
+		{Frequency: 1, Time: now.Add(-30 * time.Minute)}, // no band
+		{Frequency: 14097100, Time: now.Add(-10 * time.Minute)},
+		{Frequency: 14097100, Time: now},
+	}
+
+	tests := []struct {
+		desc   string
+		policy RetentionPolicy
+		want   []bool
+	}{
+		{"no limits", RetentionPolicy{}, []bool{true, true, true, true, true, true}},
+		{"max age", RetentionPolicy{MaxAge: 2 * time.Hour}, []bool{false, true, true, true, true, true}},
+		{"max spots", RetentionPolicy{MaxSpots: 2}, []bool{false, false, false, false, true, true}},
+		{"band quota", RetentionPolicy{BandQuotas: map[Band]int{Band20m: 1}}, []bool{false, true, false, true, false, true}},
+		{
+			"combined",
+			RetentionPolicy{MaxAge: 2 * time.Hour, MaxSpots: 3, BandQuotas: map[Band]int{Band20m: 1, Band40m: 0}},
+			[]bool{false, false, false, true, false, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			require.Equal(t, tt.want, retained(spots, tt.policy, now))
+		})
+	}
+}
+
+func TestMemoryStorePrune(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1599163380, 0).UTC()
+
+	s := NewMemoryStore()
+	require.NoError(t, s.Put(ctx, []Spot{
+		{SenderCallsign: "A", Frequency: 14097100, Time: now.Add(-2 * time.Hour)},
+		{SenderCallsign: "B", Frequency: 14097100, Time: now.Add(-time.Minute)},
+		{SenderCallsign: "C", Frequency: 14097100, Time: now},
+	}))
+
+	removed, err := s.Prune(ctx, RetentionPolicy{MaxAge: time.Hour}, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, removed)
+
+	spots, err := s.QueryByTimeRange(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, spots, 2)
+	require.Equal(t, "B", spots[0].SenderCallsign)
+	require.Equal(t, "C", spots[1].SenderCallsign)
+}
+
+type pruneFunc func(context.Context, RetentionPolicy, time.Time) (int, error)
+
+func (f pruneFunc) Prune(ctx context.Context, policy RetentionPolicy, now time.Time) (int, error) {
+	return f(ctx, policy, now)
+}
+
+func TestRunRetention(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+	var errs []error
+	p := pruneFunc(func(context.Context, RetentionPolicy, time.Time) (int, error) {
+		calls++
+		if calls == 3 {
+			cancel()
+		}
+		return 0, errors.New("disk on fire")
+	})
+
+	err := RunRetention(ctx, p, RetentionPolicy{}, time.Millisecond, func(err error) {
+		errs = append(errs, err)
+	})
+	require.Equal(t, context.Canceled, err)
+	require.Equal(t, 3, calls)
+	require.Len(t, errs, 2)
+
+	calls = 0
+	require.Error(t, RunRetention(context.Background(), p, RetentionPolicy{}, 0, nil))
+	require.Zero(t, calls)
+}
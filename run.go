@@ -0,0 +1,99 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var errNegativeLimit = badOption(errors.New("report limit cannot be negative"))
+
+// Query describes a search query with fields rather than QueryOptions, for
+// building queries declaratively or from a configuration file. Empty fields
+// are left out of the query.
+type Query struct {
+	// Only one of Callsign, SenderCallsign, ReceiverCallsign and GridSquare
+	// may be set.
+	Callsign         string `json:"callsign,omitempty" yaml:"callsign,omitempty"`
+	SenderCallsign   string `json:"senderCallsign,omitempty" yaml:"senderCallsign,omitempty"`
+	ReceiverCallsign string `json:"receiverCallsign,omitempty" yaml:"receiverCallsign,omitempty"`
+	GridSquare       string `json:"gridSquare,omitempty" yaml:"gridSquare,omitempty"`
+
+	Mode string `json:"mode,omitempty" yaml:"mode,omitempty"`
+	Band Band   `json:"band,omitempty" yaml:"band,omitempty"`
+
+	// Limit is the most reception reports to return.
+	Limit int `json:"limit,omitempty" yaml:"limit,omitempty"`
+
+	// Since is how far back to look for reports, up to 24 hours.
+	Since Duration `json:"since,omitempty" yaml:"since,omitempty"`
+
+	// LastSequenceNumber asks for reports newer than those of an earlier
+	// response.
+	LastSequenceNumber uint64 `json:"lastSequenceNumber,omitempty" yaml:"lastSequenceNumber,omitempty"`
+
+	ReceptionReportsOnly bool `json:"receptionReportsOnly,omitempty" yaml:"receptionReportsOnly,omitempty"`
+	NoActiveReceivers    bool `json:"noActiveReceivers,omitempty" yaml:"noActiveReceivers,omitempty"`
+	IncludeNoLocator     bool `json:"includeNoLocator,omitempty" yaml:"includeNoLocator,omitempty"`
+}
+
+// Options returns the QueryOptions equivalent to q.
+func (q Query) Options() []QueryOption {
+	var opts []QueryOption
+	if q.Callsign != "" {
+		opts = append(opts, WithCallsign(q.Callsign))
+	}
+	if q.SenderCallsign != "" {
+		opts = append(opts, WithSenderCallsign(q.SenderCallsign))
+	}
+	if q.ReceiverCallsign != "" {
+		opts = append(opts, WithReceiverCallsign(q.ReceiverCallsign))
+	}
+	if q.GridSquare != "" {
+		opts = append(opts, WithGridSquare(q.GridSquare))
+	}
+	if q.Mode != "" {
+		opts = append(opts, WithMode(q.Mode))
+	}
+	if q.Band != "" {
+		opts = append(opts, WithBand(q.Band))
+	}
+	if q.Limit != 0 {
+		opts = append(opts, WithReportLimit(q.Limit))
+	}
+	if q.Since != 0 {
+		opts = append(opts, WithFlowStartDuration(time.Duration(q.Since)))
+	}
+	if q.LastSequenceNumber != 0 {
+		opts = append(opts, WithLastSequenceNumberUint64(q.LastSequenceNumber))
+	}
+	if q.ReceptionReportsOnly {
+		opts = append(opts, WithReceptionReportsOnly(true))
+	}
+	if q.NoActiveReceivers {
+		opts = append(opts, WithActiveReceivers(false))
+	}
+	if q.IncludeNoLocator {
+		opts = append(opts, WithLocatorRequired(false))
+	}
+	return opts
+}
+
+// Validate returns an error, matching ErrBadOption, if q can't be run. It
+// checks bands against RegionAny and doesn't apply a client's strict
+// settings, so Run may still reject a query that passes.
+func (q Query) Validate() error {
+	if q.Limit < 0 {
+		return errNegativeLimit
+	}
+	_, err := QueryValues(q.Options()...)
+	return err
+}
+
+// Run executes q, as QueryContext does with q's Options.
+func (c *Client) Run(ctx context.Context, q Query) (*Response, error) {
+	if q.Limit < 0 {
+		return nil, errNegativeLimit
+	}
+	return c.QueryContext(ctx, q.Options()...)
+}
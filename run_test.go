@@ -0,0 +1,86 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryStructOptions(t *testing.T) {
+	var q Query
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"senderCallsign": "AG6K",
+		"mode": "FT8",
+		"band": "20m",
+		"limit": 100,
+		"since": "30m",
+		"lastSequenceNumber": 14631964162,
+		"receptionReportsOnly": true,
+		"noActiveReceivers": true,
+		"includeNoLocator": true
+	}`), &q))
+	require.NoError(t, q.Validate())
+
+	o := queryOptions{vals: make(url.Values)}
+	for _, opt := range q.Options() {
+		require.NoError(t, opt(&o))
+	}
+	require.Equal(t, url.Values{
+		"senderCallsign":   {"AG6K"},
+		"mode":             {"FT8"},
+		"frange":           {"14000000-14350000"},
+		"rptlimit":         {"100"},
+		"flowStartSeconds": {"-1800"},
+		"lastseqno":        {"14631964162"},
+		"rronly":           {"1"},
+		"noactive":         {"1"},
+		"nolocator":        {"1"},
+	}, o.vals)
+
+	require.Empty(t, Query{}.Options())
+}
+
+func TestQueryValidate(t *testing.T) {
+	tests := []struct {
+		desc string
+		q    Query
+	}{
+		{"two callsigns", Query{Callsign: "AG6K", SenderCallsign: "W5CJ"}},
+		{"callsign and grid", Query{Callsign: "AG6K", GridSquare: "DM04"}},
+		{"unknown band", Query{Band: "11m"}},
+		{"since too long", Query{Since: Duration(25 * time.Hour)}},
+		{"negative since", Query{Since: Duration(-time.Minute)}},
+		{"negative limit", Query{Limit: -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			require.ErrorIs(t, tt.q.Validate(), ErrBadOption)
+		})
+	}
+}
+
+func TestClientRun(t *testing.T) {
+	var got url.Values
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		got = req.URL.Query()
+		w.Write([]byte("<receptionReports/>"))
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	r, err := c.Run(context.Background(), Query{Callsign: "AG6K", Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, url.Values{"callsign": {"AG6K"}, "rptlimit": {"10"}}, got)
+	require.Equal(t, "AG6K", r.Query.Callsign)
+
+	_, err = c.Run(context.Background(), Query{Limit: -1})
+	require.ErrorIs(t, err, ErrBadOption)
+}
@@ -0,0 +1,91 @@
+package pskreporter
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// scanConcurrency is how many band queries ScanBands runs at once.
+const scanConcurrency = 4
+
+// ScanBands runs the query once per band, restricted to the band's frequency
+// range in the client's IARU region, and returns the responses by band along
+// with a summary of every band that had spots. Up to four queries run at once,
+// subject to the client's minimum query interval, and the first error cancels
+// the remaining queries. Band names are matched ignoring case, and an unknown
+// band returns an error matching ErrBadOption.
+func (c *Client) ScanBands(ctx context.Context, bands []Band, opts ...QueryOption) (map[Band]*Response, []BandAggregate, error) {
+	type scan struct {
+		band         Band
+		lower, upper int64
+	}
+	scans := make([]scan, 0, len(bands))
+	seen := make(map[Band]bool, len(bands))
+	for _, b := range bands {
+		lower, upper, err := bandEdges(b, c.region)
+		if err != nil {
+			return nil, nil, err
+		}
+		b = Band(strings.ToLower(strings.TrimSpace(string(b))))
+		if seen[b] {
+			continue
+		}
+		seen[b] = true
+		scans = append(scans, scan{band: b, lower: lower, upper: upper})
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu        sync.Mutex
+		firstErr  error
+		responses = make(map[Band]*Response, len(scans))
+		wg        sync.WaitGroup
+		sem       = make(chan struct{}, scanConcurrency)
+	)
+	for _, s := range scans {
+		wg.Add(1)
+		go func(s scan) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				return
+			}
+
+			bandOpts := append(append([]QueryOption(nil), opts...), WithFrequencyRange(int(s.lower), int(s.upper)))
+			r, err := c.QueryContext(ctx, bandOpts...)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			responses[s.band] = r
+		}(s)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var spots []Spot
+	for _, s := range scans {
+		bandSpots, err := responses[s.band].Spots()
+		if err != nil {
+			return nil, nil, err
+		}
+		spots = append(spots, bandSpots...)
+	}
+	return responses, aggregateByBand(spots), nil
+}
@@ -0,0 +1,62 @@
+package pskreporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanBands(t *testing.T) {
+	reports := []ReceptionReport{
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Frequency: "7074000", SNR: "-10"},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "N7HPX", Frequency: "14074000", SNR: "-12"},
+		{SenderCallsign: "K1ABC", ReceiverCallsign: "W5CJ", Frequency: "14075000", SNR: "-3"},
+	}
+	var requests int
+	svr := newLimitServer(t, reports, &requests)
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	responses, summary, err := c.ScanBands(context.Background(), []Band{Band40m, Band20m, Band10m, "20M"}, WithFlowStartSeconds(-3600))
+	require.NoError(t, err)
+	require.Equal(t, 3, requests)
+	require.Len(t, responses, 3)
+	require.Len(t, responses[Band40m].ReceptionReports, 1)
+	require.Len(t, responses[Band20m].ReceptionReports, 2)
+	require.Empty(t, responses[Band10m].ReceptionReports)
+	require.Equal(t, "-3600", responses[Band20m].Query.Values.Get("flowStartSeconds"))
+	require.Equal(t, []BandAggregate{
+		{Band: Band40m, Spots: 1, Senders: 1, Receivers: 1, BestSNR: -10, HasSNR: true},
+		{Band: Band20m, Spots: 2, Senders: 2, Receivers: 2, BestSNR: -3, HasSNR: true},
+	}, summary)
+
+	t.Run("unknown band", func(t *testing.T) {
+		_, _, err := c.ScanBands(context.Background(), []Band{"11m"})
+		require.ErrorIs(t, err, ErrBadOption)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var calls int32
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			if req.URL.Query().Get("frange") == strconv.Itoa(14000000)+"-"+strconv.Itoa(14350000) {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Write([]byte("<receptionReports/>"))
+		}))
+		defer svr.Close()
+
+		c, err := New(WithBaseURL(svr.URL))
+		require.NoError(t, err)
+		_, _, err = c.ScanBands(context.Background(), []Band{Band40m, Band20m})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unexpected http response 500")
+	})
+}
@@ -0,0 +1,106 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+var errNegativePage = errors.New("limit and offset cannot be negative")
+
+// SpotQuery combines filters over stored spots. Empty fields don't filter.
+type SpotQuery struct {
+	Callsign    string // sender or receiver
+	Band        Band
+	Start       time.Time
+	End         time.Time
+	MinDistance float64 // kilometers
+
+	// Limit caps the number of spots returned, zero for no limit. Offset
+	// skips that many matching spots first.
+	Limit  int
+	Offset int
+}
+
+// SpotPage is one page of the results of a SpotQuery.
+type SpotPage struct {
+	Spots []Spot
+	Total int // matching spots across all pages
+
+	// NextOffset is the Offset of the following page, or zero if this is the
+	// last page.
+	NextOffset int
+}
+
+// Searcher is implemented by stores that can run a SpotQuery natively.
+type Searcher interface {
+	Search(ctx context.Context, q SpotQuery) (SpotPage, error)
+}
+
+// Search runs q against s. If s implements Searcher the query is delegated to
+// it, otherwise the narrowest of the Store query methods is used and the rest
+// of the filters are applied to its results.
+func Search(ctx context.Context, s Store, q SpotQuery) (SpotPage, error) {
+	if q.Limit < 0 || q.Offset < 0 {
+		return SpotPage{}, errNegativePage
+	}
+	if searcher, ok := s.(Searcher); ok {
+		return searcher.Search(ctx, q)
+	}
+
+	var spots []Spot
+	var err error
+	switch {
+	case q.Callsign != "":
+		spots, err = s.QueryByCallsign(ctx, q.Callsign, q.Start, q.End)
+	case q.Band != "":
+		spots, err = s.QueryByBand(ctx, q.Band, q.Start, q.End)
+	default:
+		spots, err = s.QueryByTimeRange(ctx, q.Start, q.End)
+	}
+	if err != nil {
+		return SpotPage{}, err
+	}
+
+	var matched []Spot
+	for i := range spots {
+		if q.matches(&spots[i]) {
+			matched = append(matched, spots[i])
+		}
+	}
+	return q.page(matched), nil
+}
+
+// matches reports whether s passes every filter in q except the time range.
+func (q SpotQuery) matches(s *Spot) bool {
+	if q.Callsign != "" && !strings.EqualFold(s.SenderCallsign, q.Callsign) && !strings.EqualFold(s.ReceiverCallsign, q.Callsign) {
+		return false
+	}
+	if q.Band != "" {
+		if b, ok := spotBand(s); !ok || b != q.Band {
+			return false
+		}
+	}
+	if q.MinDistance > 0 {
+		if d, ok := spotDistance(s); !ok || d < q.MinDistance {
+			return false
+		}
+	}
+	return true
+}
+
+// page slices the page selected by q out of matched.
+func (q SpotQuery) page(matched []Spot) SpotPage {
+	p := SpotPage{Total: len(matched)}
+	if q.Offset >= len(matched) {
+		return p
+	}
+	end := len(matched)
+	if q.Limit > 0 && q.Offset+q.Limit < end {
+		end = q.Offset + q.Limit
+		p.NextOffset = end
+	}
+	p.Spots = matched[q.Offset:end]
+	return p
+}
@@ -0,0 +1,64 @@
+package pskreporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// storeOnly hides any optional interfaces implemented by the wrapped Store.
+type storeOnly struct {
+	Store
+}
+
+func TestSearch(t *testing.T) {
+	ctx := context.Background()
+	base := time.Unix(1599163380, 0).UTC()
+
+	mem := NewMemoryStore()
+	require.NoError(t, mem.Put(ctx, []Spot{
+		{SenderCallsign: "AG6K", ReceiverCallsign: "W5CJ", Frequency: 14097100, Distance: 1500, Time: base},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "N7HPX", Frequency: 14097050, Distance: 900, Time: base.Add(time.Minute)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "JA1XYZ", Frequency: 14097000, Distance: 8800, Time: base.Add(2 * time.Minute)},
+		{SenderCallsign: "AG6K", ReceiverCallsign: "VK3ABC", Frequency: 7038600, Distance: 12000, Time: base.Add(3 * time.Minute)},
+		{SenderCallsign: "K1ABC", ReceiverCallsign: "W5CJ", Frequency: 14097100, Distance: 2000, Time: base.Add(4 * time.Minute)},
+	}))
+
+	stores := map[string]Store{
+		"searcher": mem,
+		"fallback": storeOnly{mem},
+	}
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			page, err := Search(ctx, s, SpotQuery{Callsign: "ag6k", Band: Band20m, MinDistance: 1000})
+			require.NoError(t, err)
+			require.Equal(t, 2, page.Total)
+			require.Zero(t, page.NextOffset)
+			require.Equal(t, "W5CJ", page.Spots[0].ReceiverCallsign)
+			require.Equal(t, "JA1XYZ", page.Spots[1].ReceiverCallsign)
+
+			page, err = Search(ctx, s, SpotQuery{Band: Band20m, Start: base.Add(time.Minute), Limit: 2})
+			require.NoError(t, err)
+			require.Equal(t, 3, page.Total)
+			require.Equal(t, 2, page.NextOffset)
+			require.Len(t, page.Spots, 2)
+			require.Equal(t, "N7HPX", page.Spots[0].ReceiverCallsign)
+
+			page, err = Search(ctx, s, SpotQuery{Band: Band20m, Start: base.Add(time.Minute), Limit: 2, Offset: page.NextOffset})
+			require.NoError(t, err)
+			require.Zero(t, page.NextOffset)
+			require.Len(t, page.Spots, 1)
+			require.Equal(t, "K1ABC", page.Spots[0].SenderCallsign)
+
+			page, err = Search(ctx, s, SpotQuery{Offset: 10})
+			require.NoError(t, err)
+			require.Equal(t, 5, page.Total)
+			require.Empty(t, page.Spots)
+
+			_, err = Search(ctx, s, SpotQuery{Limit: -1})
+			require.Equal(t, errNegativePage, err)
+		})
+	}
+}
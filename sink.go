@@ -0,0 +1,187 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Sink is a destination for spots.
+type Sink interface {
+	// Write sends the spots to the destination. Implementations may buffer the
+	// spots until Flush is called.
+	Write(ctx context.Context, spots []Spot) error
+
+	// Flush forces any buffered spots to be written out.
+	Flush() error
+
+	// Close flushes any buffered spots and releases the resources held by the
+	// sink.
+	Close() error
+}
+
+// MultiSink fans spots out to several sinks.
+type MultiSink struct {
+	sinks []Sink
+}
+
+// NewMultiSink creates a sink that writes every spot to each of the given sinks.
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+// Write writes the spots to each sink in turn, stopping at the first error.
+func (m *MultiSink) Write(ctx context.Context, spots []Spot) error {
+	for _, s := range m.sinks {
+		if err := s.Write(ctx, spots); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush flushes every sink, returning the first error encountered.
+func (m *MultiSink) Flush() error {
+	var firstErr error
+	for _, s := range m.sinks {
+		if err := s.Flush(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Close closes every sink, returning the first error encountered.
+func (m *MultiSink) Close() error {
+	var firstErr error
+	for _, s := range m.sinks {
+		if err := s.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// JSONLSink writes spots as newline delimited JSON.
+type JSONLSink struct {
+	w   io.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLSink creates a sink that writes one JSON object per spot to w. If w
+// is an io.Closer, it is closed when the sink is closed.
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{w: w, enc: json.NewEncoder(w)}
+}
+
+// Write encodes the spots to the underlying writer.
+func (s *JSONLSink) Write(ctx context.Context, spots []Spot) error {
+	for _, spot := range spots {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := s.enc.Encode(spot); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush is a no-op; every spot is written as it is received.
+func (s *JSONLSink) Flush() error {
+	return nil
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (s *JSONLSink) Close() error {
+	return closeWriter(s.w)
+}
+
+var csvHeader = []string{
+	"time",
+	"senderCallsign",
+	"senderLocator",
+	"receiverCallsign",
+	"receiverLocator",
+	"frequency",
+	"band",
+	"mode",
+	"snr",
+	"distance",
+}
+
+// CSVSink writes spots as CSV records, preceded by a header row.
+type CSVSink struct {
+	w             io.Writer
+	cw            *csv.Writer
+	headerWritten bool
+}
+
+// NewCSVSink creates a sink that writes spots to w as CSV. If w is an
+// io.Closer, it is closed when the sink is closed.
+func NewCSVSink(w io.Writer) *CSVSink {
+	return &CSVSink{w: w, cw: csv.NewWriter(w)}
+}
+
+// Write buffers the spots as CSV records.
+func (s *CSVSink) Write(ctx context.Context, spots []Spot) error {
+	if !s.headerWritten {
+		if err := s.cw.Write(csvHeader); err != nil {
+			return err
+		}
+		s.headerWritten = true
+	}
+
+	for _, spot := range spots {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		var snr string
+		if spot.HasSNR {
+			snr = strconv.Itoa(spot.SNR)
+		}
+
+		err := s.cw.Write([]string{
+			spot.Time.UTC().Format(time.RFC3339),
+			spot.SenderCallsign,
+			spot.SenderLocator,
+			spot.ReceiverCallsign,
+			spot.ReceiverLocator,
+			strconv.FormatInt(spot.Frequency, 10),
+			string(spot.Band),
+			spot.Mode,
+			snr,
+			strconv.FormatFloat(spot.Distance, 'f', 1, 64),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes any buffered records to the underlying writer.
+func (s *CSVSink) Flush() error {
+	s.cw.Flush()
+	return s.cw.Error()
+}
+
+// Close flushes the sink and closes the underlying writer if it is an
+// io.Closer.
+func (s *CSVSink) Close() error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+	return closeWriter(s.w)
+}
+
+func closeWriter(w io.Writer) error {
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package pskreporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testSpots = []Spot{
+	{
+		SenderCallsign:   "AG6K",
+		SenderLocator:    "DM14cc24",
+		ReceiverCallsign: "W5CJ",
+		ReceiverLocator:  "EM55db92",
+		Frequency:        14075311,
+		Band:             Band20m,
+		Mode:             "FT8",
+		SNR:              -19,
+		HasSNR:           true,
+		Time:             time.Unix(1599163380, 0).UTC(),
+		Distance:         2570.1,
+	},
+	{
+		SenderCallsign:   "AG6K",
+		ReceiverCallsign: "N7HPX",
+		Frequency:        7075301,
+		Mode:             "FT8",
+		Time:             time.Unix(1599163378, 0).UTC(),
+	},
+}
+
+func TestJSONLSink(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewJSONLSink(&buf)
+	require.NoError(t, s.Write(context.Background(), testSpots))
+	require.NoError(t, s.Close())
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var got Spot
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &got))
+	require.Equal(t, testSpots[0], got)
+}
+
+func TestCSVSink(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewCSVSink(&buf)
+	require.NoError(t, s.Write(context.Background(), testSpots[:1]))
+	require.NoError(t, s.Write(context.Background(), testSpots[1:]))
+	require.NoError(t, s.Close())
+
+	expected := "time,senderCallsign,senderLocator,receiverCallsign,receiverLocator,frequency,band,mode,snr,distance\n" +
+		"2020-09-03T20:03:00Z,AG6K,DM14cc24,W5CJ,EM55db92,14075311,20m,FT8,-19,2570.1\n" +
+		"2020-09-03T20:02:58Z,AG6K,,N7HPX,,7075301,,FT8,,0.0\n"
+	require.Equal(t, expected, buf.String())
+}
+
+func TestMultiSink(t *testing.T) {
+	a, b := &recordingSink{}, &recordingSink{}
+	m := NewMultiSink(a, b)
+
+	require.NoError(t, m.Write(context.Background(), testSpots))
+	require.NoError(t, m.Flush())
+	require.NoError(t, m.Close())
+
+	for _, s := range []*recordingSink{a, b} {
+		require.Equal(t, testSpots, s.spots)
+		require.Equal(t, 1, s.flushes)
+		require.True(t, s.closed)
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		a, b := &recordingSink{err: errBoom}, &recordingSink{}
+		m := NewMultiSink(a, b)
+
+		require.Equal(t, errBoom, m.Write(context.Background(), testSpots))
+		require.Empty(t, b.spots)
+
+		require.Equal(t, errBoom, m.Close())
+		require.True(t, b.closed)
+	})
+}
+
+type recordingSink struct {
+	spots   []Spot
+	flushes int
+	closed  bool
+	err     error
+}
+
+func (s *recordingSink) Write(ctx context.Context, spots []Spot) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.spots = append(s.spots, spots...)
+	return nil
+}
+
+func (s *recordingSink) Flush() error {
+	s.flushes++
+	return s.err
+}
+
+func (s *recordingSink) Close() error {
+	s.closed = true
+	return s.err
+}
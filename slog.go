@@ -0,0 +1,66 @@
+package pskreporter
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer, grouping the spot's attributes by
+// station. Empty fields are left out.
+func (s Spot) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.Any("receiver", stationValue(s.Receiver())),
+		slog.Any("sender", stationValue(s.Sender())),
+	}
+	if s.Frequency != 0 {
+		attrs = append(attrs, slog.Int64("frequency", s.Frequency))
+	}
+	if b, ok := spotBand(&s); ok {
+		attrs = append(attrs, slog.String("band", string(b)))
+	}
+	if s.Mode != "" {
+		attrs = append(attrs, slog.String("mode", s.Mode))
+	}
+	if s.HasSNR {
+		attrs = append(attrs, slog.Int("snr", s.SNR))
+	}
+	if d, ok := spotDistance(&s); ok {
+		attrs = append(attrs, slog.Float64("distance", d))
+	}
+	if !s.Time.IsZero() {
+		attrs = append(attrs, slog.Time("time", s.Time))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer. The report is logged like a Spot, or
+// with its raw fields if they can't be parsed.
+func (r ReceptionReport) LogValue() slog.Value {
+	if s, err := NewSpot(r); err == nil {
+		return s.LogValue()
+	}
+	var attrs []slog.Attr
+	for _, kv := range [][2]string{
+		{"receiverCallsign", r.ReceiverCallsign},
+		{"receiverLocator", r.ReceiverLocator},
+		{"senderCallsign", r.SenderCallsign},
+		{"senderLocator", r.SenderLocator},
+		{"frequency", r.Frequency},
+		{"flowStartSeconds", r.FlowStartSeconds},
+		{"mode", r.Mode},
+		{"snr", r.SNR},
+	} {
+		if kv[1] != "" {
+			attrs = append(attrs, slog.String(kv[0], kv[1]))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func stationValue(st Station) slog.Value {
+	attrs := []slog.Attr{slog.String("callsign", st.Callsign)}
+	if st.Locator != "" {
+		attrs = append(attrs, slog.String("locator", st.Locator))
+	}
+	if st.DXCC != "" {
+		attrs = append(attrs, slog.String("dxcc", st.DXCC))
+	}
+	return slog.GroupValue(attrs...)
+}
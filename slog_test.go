@@ -0,0 +1,58 @@
+package pskreporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func logJSON(t *testing.T, v interface{}) map[string]interface{} {
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("spot", "spot", v)
+
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+	return out["spot"].(map[string]interface{})
+}
+
+func TestSpotLogValue(t *testing.T) {
+	got := logJSON(t, testSpots[0])
+	require.Equal(t, map[string]interface{}{
+		"receiver":  map[string]interface{}{"callsign": "W5CJ", "locator": "EM55db92"},
+		"sender":    map[string]interface{}{"callsign": "AG6K", "locator": "DM14cc24"},
+		"frequency": 14075311.0,
+		"band":      "20m",
+		"mode":      "FT8",
+		"snr":       -19.0,
+		"distance":  2570.1,
+		"time":      "2020-09-03T20:03:00Z",
+	}, got)
+}
+
+func TestReceptionReportLogValue(t *testing.T) {
+	rr := ReceptionReport{
+		ReceiverCallsign: "N7HPX",
+		SenderCallsign:   "AG6K",
+		Frequency:        "7075301",
+		Mode:             "FT8",
+	}
+	require.Equal(t, map[string]interface{}{
+		"receiver":  map[string]interface{}{"callsign": "N7HPX"},
+		"sender":    map[string]interface{}{"callsign": "AG6K"},
+		"frequency": 7075301.0,
+		"band":      "40m",
+		"mode":      "FT8",
+	}, logJSON(t, rr))
+
+	rr.SNR = "loud"
+	require.Equal(t, map[string]interface{}{
+		"receiverCallsign": "N7HPX",
+		"senderCallsign":   "AG6K",
+		"frequency":        "7075301",
+		"mode":             "FT8",
+		"snr":              "loud",
+	}, logJSON(t, rr))
+}
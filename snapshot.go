@@ -0,0 +1,85 @@
+package pskreporter
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// snapshotFormat and snapshotVersion identify store snapshots. The version is
+// bumped whenever the layout changes incompatibly.
+const (
+	snapshotFormat  = "pskreporter-snapshot"
+	snapshotVersion = 1
+)
+
+var errNotSnapshot = errors.New("not a store snapshot")
+
+// A snapshot is a gzip compressed stream of JSON values: a snapshotHeader,
+// followed by one snapshotRecord per spot or summary.
+type snapshotHeader struct {
+	Format  string `json:"format"`
+	Version int    `json:"version"`
+}
+
+type snapshotRecord struct {
+	Spot    *Spot        `json:"spot,omitempty"`
+	Summary *SpotSummary `json:"summary,omitempty"`
+}
+
+// writeSnapshot writes spots and summaries to w as a snapshot.
+func writeSnapshot(w io.Writer, spots []Spot, summaries []SpotSummary) error {
+	zw := gzip.NewWriter(w)
+	enc := json.NewEncoder(zw)
+	if err := enc.Encode(snapshotHeader{Format: snapshotFormat, Version: snapshotVersion}); err != nil {
+		return err
+	}
+	for i := range spots {
+		if err := enc.Encode(snapshotRecord{Spot: &spots[i]}); err != nil {
+			return err
+		}
+	}
+	for i := range summaries {
+		if err := enc.Encode(snapshotRecord{Summary: &summaries[i]}); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// readSnapshot reads the spots and summaries in the snapshot from r.
+func readSnapshot(r io.Reader) ([]Spot, []SpotSummary, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", errNotSnapshot, err)
+	}
+	defer zr.Close()
+
+	dec := json.NewDecoder(zr)
+	var h snapshotHeader
+	if err := dec.Decode(&h); err != nil || h.Format != snapshotFormat {
+		return nil, nil, errNotSnapshot
+	}
+	if h.Version != snapshotVersion {
+		return nil, nil, fmt.Errorf("unsupported snapshot version %d", h.Version)
+	}
+
+	var spots []Spot
+	var summaries []SpotSummary
+	for {
+		var rec snapshotRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			return spots, summaries, nil
+		} else if err != nil {
+			return nil, nil, fmt.Errorf("decoding snapshot: %w", err)
+		}
+		switch {
+		case rec.Spot != nil:
+			spots = append(spots, *rec.Spot)
+		case rec.Summary != nil:
+			summaries = append(summaries, *rec.Summary)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package pskreporter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	hour := time.Date(2020, 9, 3, 20, 0, 0, 0, time.UTC)
+
+	src := NewMemoryStore()
+	require.NoError(t, src.Put(ctx, testSpots))
+	require.NoError(t, src.Put(ctx, []Spot{
+		{SenderCallsign: "K1ABC", ReceiverCallsign: "W5CJ", Frequency: 14097100, SNR: -3, HasSNR: true, Time: hour.Add(-time.Hour)},
+	}))
+	_, err := src.Downsample(ctx, hour)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, src.Snapshot(ctx, &buf))
+
+	dst := NewMemoryStore()
+	require.NoError(t, dst.Put(ctx, []Spot{{SenderCallsign: "REPLACED"}}))
+	require.NoError(t, dst.Restore(ctx, &buf))
+
+	want, err := src.QueryByTimeRange(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	got, err := dst.QueryByTimeRange(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	wantSummaries, err := src.Summaries(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, wantSummaries, 1)
+	gotSummaries, err := dst.Summaries(ctx, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Equal(t, wantSummaries, gotSummaries)
+}
+
+func TestRestoreErrors(t *testing.T) {
+	ctx := context.Background()
+	gz := func(s string) *bytes.Buffer {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, err := zw.Write([]byte(s))
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		return &buf
+	}
+	header := func(version int) string {
+		b, err := json.Marshal(snapshotHeader{Format: snapshotFormat, Version: version})
+		require.NoError(t, err)
+		return string(b) + "\n"
+	}
+
+	s := NewMemoryStore()
+	require.Error(t, s.Restore(ctx, strings.NewReader("plain text")))
+	require.Equal(t, errNotSnapshot, s.Restore(ctx, gz(`{"format":"other"}`)))
+	require.EqualError(t, s.Restore(ctx, gz(header(99))), "unsupported snapshot version 99")
+	require.Error(t, s.Restore(ctx, gz(header(snapshotVersion)+`{"spot":`)))
+	require.NoError(t, s.Restore(ctx, gz(header(snapshotVersion))))
+}
@@ -0,0 +1,148 @@
+package pskreporter
+
+import (
+	"bufio"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultSolarURL is N0NBH's solar data feed, used by FetchSolarIndices when no
+// other source is given.
+const DefaultSolarURL = "https://www.hamqsl.com/solarxml.php"
+
+// SolarIndices describe the space weather conditions at a point in time.
+type SolarIndices struct {
+	SolarFlux int       `json:"solarFlux"` // 10.7cm solar flux index (SFI)
+	AIndex    int       `json:"aIndex"`
+	KIndex    int       `json:"kIndex"`
+	Updated   time.Time `json:"updated"` // when the source last updated the indices, if known
+}
+
+type solarResponse struct {
+	XMLName   xml.Name `xml:"solar"`
+	SolarData struct {
+		Updated   string `xml:"updated"`
+		SolarFlux string `xml:"solarflux"`
+		AIndex    string `xml:"aindex"`
+		KIndex    string `xml:"kindex"`
+	} `xml:"solardata"`
+}
+
+// FetchSolarIndices retrieves the current solar indices from url, which must
+// serve XML in the format of DefaultSolarURL. If url is empty, DefaultSolarURL
+// is used. If doer is nil, http.DefaultClient is used.
+func FetchSolarIndices(ctx context.Context, doer Doer, url string) (SolarIndices, error) {
+	if url == "" {
+		url = DefaultSolarURL
+	}
+	if doer == nil {
+		doer = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return SolarIndices{}, err
+	}
+
+	resp, err := doer.Do(req)
+	if err != nil {
+		return SolarIndices{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return SolarIndices{}, fmt.Errorf("unexpected http response %d", resp.StatusCode)
+	}
+
+	var sr solarResponse
+	dec := xml.NewDecoder(resp.Body)
+	dec.CharsetReader = charsetReader
+	if err := dec.Decode(&sr); err != nil {
+		return SolarIndices{}, fmt.Errorf("decoding solar data: %w", err)
+	}
+
+	var si SolarIndices
+	for _, f := range []struct {
+		name string
+		val  string
+		dst  *int
+	}{
+		{"solarflux", sr.SolarData.SolarFlux, &si.SolarFlux},
+		{"aindex", sr.SolarData.AIndex, &si.AIndex},
+		{"kindex", sr.SolarData.KIndex, &si.KIndex},
+	} {
+		v, err := strconv.Atoi(strings.TrimSpace(f.val))
+		if err != nil {
+			return SolarIndices{}, fmt.Errorf("parsing %s %q: %w", f.name, f.val, err)
+		}
+		*f.dst = v
+	}
+
+	// The update time is informational, so a value in an unexpected format is
+	// ignored rather than failing the whole fetch.
+	if t, err := time.Parse("02 Jan 2006 1504 MST", strings.TrimSpace(sr.SolarData.Updated)); err == nil {
+		si.Updated = t.UTC()
+	}
+
+	return si, nil
+}
+
+// charsetReader converts ISO-8859-1 documents, which is what the N0NBH feed
+// declares, to UTF-8 for the XML decoder.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "iso-8859-1", "latin1":
+		return &latin1Reader{r: bufio.NewReader(input)}, nil
+	}
+	return nil, fmt.Errorf("unsupported charset %q", charset)
+}
+
+type latin1Reader struct {
+	r       *bufio.Reader
+	pending []byte
+}
+
+func (l *latin1Reader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(l.pending) > 0 {
+			c := copy(p[n:], l.pending)
+			l.pending = l.pending[c:]
+			n += c
+			continue
+		}
+
+		b, err := l.r.ReadByte()
+		if err != nil {
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+
+		if b < utf8.RuneSelf {
+			p[n] = b
+			n++
+			continue
+		}
+		var buf [utf8.UTFMax]byte
+		l.pending = buf[:utf8.EncodeRune(buf[:], rune(b))]
+	}
+	return n, nil
+}
+
+// SolarEnricher annotates spots with the given solar indices. All spots share
+// the same SolarIndices value.
+func SolarEnricher(si SolarIndices) Enricher {
+	return EnricherFunc(func(s *Spot) error {
+		s.Solar = &si
+		return nil
+	})
+}
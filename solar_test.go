@@ -0,0 +1,92 @@
+package pskreporter
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testSolarXML = `<?xml version="1.0" encoding="ISO-8859-1"?>
+<solar>
+	<solardata>
+		<source url="http://www.hamqsl.com/solar.html">N0NBH</source>
+		<updated> 03 Sep 2020 1928 GMT</updated>
+		<solarflux>71</solarflux>
+		<aindex> 5</aindex>
+		<kindex>2</kindex>
+	</solardata>
+</solar>`
+
+func TestFetchSolarIndices(t *testing.T) {
+	t.Run("no error", func(t *testing.T) {
+		svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			w.Write([]byte(testSolarXML))
+		}))
+		defer svr.Close()
+
+		si, err := FetchSolarIndices(context.Background(), nil, svr.URL)
+		require.NoError(t, err)
+		require.Equal(t, SolarIndices{
+			SolarFlux: 71,
+			AIndex:    5,
+			KIndex:    2,
+			Updated:   time.Date(2020, 9, 3, 19, 28, 0, 0, time.UTC),
+		}, si)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		tests := []struct {
+			desc    string
+			status  int
+			body    string
+			message string
+		}{
+			{"http 500", http.StatusInternalServerError, "", "unexpected http response"},
+			{"bad xml", http.StatusOK, "hello world", "decoding solar data"},
+			{"bad index", http.StatusOK, "<solar><solardata><solarflux>abc</solarflux></solardata></solar>", "parsing solarflux"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.desc, func(t *testing.T) {
+				svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+					w.WriteHeader(tt.status)
+					w.Write([]byte(tt.body))
+				}))
+				defer svr.Close()
+
+				_, err := FetchSolarIndices(context.Background(), nil, svr.URL)
+				require.Error(t, err)
+				require.Contains(t, err.Error(), tt.message)
+			})
+		}
+
+		t.Run("doer error", func(t *testing.T) {
+			_, err := FetchSolarIndices(context.Background(), &doerError{}, "")
+			require.Error(t, err)
+		})
+	})
+}
+
+func TestCharsetReader(t *testing.T) {
+	r, err := charsetReader("ISO-8859-1", strings.NewReader("caf\xe9 \xb0C"))
+	require.NoError(t, err)
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.Equal(t, "café °C", string(b))
+
+	_, err = charsetReader("koi8-r", strings.NewReader(""))
+	require.Error(t, err)
+}
+
+func TestSolarEnricher(t *testing.T) {
+	si := SolarIndices{SolarFlux: 71, KIndex: 2}
+	var s Spot
+	require.NoError(t, SolarEnricher(si).Enrich(&s))
+	require.Equal(t, &si, s.Solar)
+}
@@ -0,0 +1,131 @@
+package pskreporter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Spot is a reception report with its numeric fields parsed, plus room for
+// derived data filled in by an Enricher.
+type Spot struct {
+	ReceiverCallsign string    `json:"receiverCallsign"`
+	ReceiverLocator  string    `json:"receiverLocator,omitempty"`
+	ReceiverDXCC     string    `json:"receiverDXCC,omitempty"`
+	ReceiverDXCCCode string    `json:"receiverDXCCCode,omitempty"`
+	SenderCallsign   string    `json:"senderCallsign"`
+	SenderLocator    string    `json:"senderLocator,omitempty"`
+	SenderDXCC       string    `json:"senderDXCC,omitempty"`
+	SenderDXCCCode   string    `json:"senderDXCCCode,omitempty"`
+	Frequency        int64     `json:"frequency,omitempty"` // Hz
+	Mode             string    `json:"mode,omitempty"`
+	SNR              int       `json:"snr,omitempty"`
+	HasSNR           bool      `json:"hasSNR,omitempty"`
+	Time             time.Time `json:"time"`
+	IsSender         bool      `json:"isSender,omitempty"`
+
+	// The following fields are populated by enrichers.
+	Band              Band          `json:"band,omitempty"`
+	Distance          float64       `json:"distance,omitempty"`          // kilometers between sender and receiver
+	Bearing           float64       `json:"bearing,omitempty"`           // degrees from the sender to the receiver
+	Greyline          bool          `json:"greyline,omitempty"`          // both ends were near the day/night terminator
+	SenderContinent   string        `json:"senderContinent,omitempty"`   // such as ContinentEurope
+	ReceiverContinent string        `json:"receiverContinent,omitempty"` // such as ContinentEurope
+	Solar             *SolarIndices `json:"solar,omitempty"`             // space weather at the time of the spot
+}
+
+// NewSpot converts a ReceptionReport into a Spot.
+func NewSpot(r ReceptionReport) (Spot, error) {
+	s := Spot{
+		ReceiverCallsign: r.ReceiverCallsign,
+		ReceiverLocator:  r.ReceiverLocator,
+		ReceiverDXCC:     r.ReceiverDXCC,
+		ReceiverDXCCCode: r.ReceiverDXCCCode,
+		SenderCallsign:   r.SenderCallsign,
+		SenderLocator:    r.SenderLocator,
+		Mode:             r.Mode,
+		IsSender:         r.IsSender == "1",
+	}
+
+	if r.Frequency != "" {
+		f, err := parseFrequency(r.Frequency)
+		if err != nil {
+			return s, fmt.Errorf("parsing frequency %q: %w", r.Frequency, err)
+		}
+		s.Frequency = f
+	}
+
+	if r.FlowStartSeconds != "" {
+		t, err := parseUnixSeconds(r.FlowStartSeconds)
+		if err != nil {
+			return s, fmt.Errorf("parsing flowStartSeconds %q: %w", r.FlowStartSeconds, err)
+		}
+		s.Time = t
+	}
+
+	if r.SNR != "" {
+		snr, err := strconv.ParseInt(r.SNR, 10, 32)
+		if err != nil {
+			return s, fmt.Errorf("parsing sNR %q: %w", r.SNR, err)
+		}
+		s.SNR = int(snr)
+		s.HasSNR = true
+	}
+
+	return s, nil
+}
+
+// The range of Unix times accepted in reports: the years 1 to 9999, which is
+// what RFC 3339, and so JSON, can represent.
+const (
+	minUnixSeconds = -62135596800
+	maxUnixSeconds = 253402300799
+)
+
+// parseFrequency parses a frequency in Hz, which must not be negative.
+func parseFrequency(v string) (int64, error) {
+	f, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if f < 0 {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: v, Err: strconv.ErrRange}
+	}
+	return f, nil
+}
+
+// parseUnixSeconds parses a Unix time in seconds.
+func parseUnixSeconds(v string) (time.Time, error) {
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if sec < minUnixSeconds || sec > maxUnixSeconds {
+		return time.Time{}, &strconv.NumError{Func: "ParseInt", Num: v, Err: strconv.ErrRange}
+	}
+	return time.Unix(sec, 0).UTC(), nil
+}
+
+// mhzToHz converts a frequency in MHz to Hz, reporting false if it can't be
+// represented.
+func mhzToHz(mhz float64) (int64, bool) {
+	hz := math.Round(mhz * 1e6)
+	if math.IsNaN(hz) || hz < 0 || hz >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(hz), true
+}
+
+// Spots converts the reception reports in the response into Spots.
+func (r *Response) Spots() ([]Spot, error) {
+	spots := make([]Spot, 0, len(r.ReceptionReports))
+	for _, rr := range r.ReceptionReports {
+		s, err := NewSpot(rr)
+		if err != nil {
+			return nil, err
+		}
+		spots = append(spots, s)
+	}
+	return spots, nil
+}
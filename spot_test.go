@@ -0,0 +1,113 @@
+package pskreporter
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSpot(t *testing.T) {
+	t.Run("no error", func(t *testing.T) {
+		s, err := NewSpot(ReceptionReport{
+			ReceiverCallsign: "W5CJ",
+			ReceiverLocator:  "EM55db92",
+			SenderCallsign:   "AG6K",
+			SenderLocator:    "DM14cc24",
+			Frequency:        "14075311",
+			FlowStartSeconds: "1599163380",
+			Mode:             "FT8",
+			IsSender:         "1",
+			ReceiverDXCC:     "United States",
+			ReceiverDXCCCode: "K",
+			SNR:              "-19",
+		})
+		require.NoError(t, err)
+		require.Equal(t, "W5CJ", s.ReceiverCallsign)
+		require.Equal(t, "AG6K", s.SenderCallsign)
+		require.Equal(t, int64(14075311), s.Frequency)
+		require.Equal(t, time.Unix(1599163380, 0).UTC(), s.Time)
+		require.Equal(t, -19, s.SNR)
+		require.True(t, s.HasSNR)
+		require.True(t, s.IsSender)
+	})
+
+	t.Run("empty fields", func(t *testing.T) {
+		s, err := NewSpot(ReceptionReport{})
+		require.NoError(t, err)
+		require.False(t, s.HasSNR)
+		require.True(t, s.Time.IsZero())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, rr := range []ReceptionReport{
+			{Frequency: "abc"},
+			{Frequency: "-14075311"},
+			{Frequency: "9223372036854775808"},
+			{FlowStartSeconds: "abc"},
+			{FlowStartSeconds: "253402300800"},
+			{FlowStartSeconds: "-9223372036854775808"},
+			{SNR: "abc"},
+			{SNR: "4294967296"},
+		} {
+			_, err := NewSpot(rr)
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestNewSpotNumericRange(t *testing.T) {
+	t.Run("microwave frequency", func(t *testing.T) {
+		s, err := NewSpot(ReceptionReport{Frequency: "241000000100"})
+		require.NoError(t, err)
+		require.Equal(t, int64(241000000100), s.Frequency)
+	})
+
+	t.Run("far future", func(t *testing.T) {
+		s, err := NewSpot(ReceptionReport{FlowStartSeconds: "253402300799"})
+		require.NoError(t, err)
+		require.Equal(t, time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC), s.Time)
+	})
+
+	t.Run("any frequency", func(t *testing.T) {
+		require.NoError(t, quick.Check(func(f int64) bool {
+			s, err := NewSpot(ReceptionReport{Frequency: strconv.FormatInt(f, 10)})
+			if f < 0 {
+				return err != nil
+			}
+			return err == nil && s.Frequency == f
+		}, nil))
+	})
+
+	t.Run("any time", func(t *testing.T) {
+		check := func(sec int64) bool {
+			s, err := NewSpot(ReceptionReport{FlowStartSeconds: strconv.FormatInt(sec, 10)})
+			if sec < minUnixSeconds || sec > maxUnixSeconds {
+				return err != nil
+			}
+			if err != nil || s.Time.Unix() != sec {
+				return false
+			}
+			_, err = json.Marshal(s)
+			return err == nil
+		}
+		require.NoError(t, quick.Check(check, nil))
+
+		// Most int64s are out of range, so check the valid range too.
+		require.NoError(t, quick.Check(func(n uint64) bool {
+			return check(minUnixSeconds + int64(n%(maxUnixSeconds-minUnixSeconds+1)))
+		}, nil))
+	})
+}
+
+func TestResponseSpots(t *testing.T) {
+	resp := loadResponse(t)
+
+	spots, err := resp.Spots()
+	require.NoError(t, err)
+	require.Len(t, spots, len(resp.ReceptionReports))
+	require.Equal(t, "W5CJ", spots[0].ReceiverCallsign)
+}
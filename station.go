@@ -0,0 +1,66 @@
+package pskreporter
+
+import (
+	"errors"
+	"strings"
+)
+
+var errNoStation = errors.New("no callsign given and no station configured")
+
+// StationProfile describes the user's own station.
+type StationProfile struct {
+	Callsign string
+	Locator  string
+	Antenna  string // free-form antenna information, e.g. "EFHW at 10m"
+}
+
+// WithStation sets the user's own station details, used by the client's
+// methods that take a callsign or locator when none is given.
+func WithStation(callsign, locator, antennaInfo string) ClientOption {
+	return func(o *clientOptions) error {
+		if locator != "" {
+			if _, err := ParseLocator(locator); err != nil {
+				return badOption(err)
+			}
+		}
+		o.station = StationProfile{
+			Callsign: strings.TrimSpace(callsign),
+			Locator:  strings.TrimSpace(locator),
+			Antenna:  antennaInfo,
+		}
+		return nil
+	}
+}
+
+// Station returns the station set with WithStation.
+func (c *Client) Station() StationProfile {
+	return c.station
+}
+
+// DistanceTo returns the great-circle distance in kilometers and the initial
+// bearing in degrees from the station set with WithStation to locator.
+func (c *Client) DistanceTo(locator string) (distance, bearing float64, err error) {
+	if c.station.Locator == "" {
+		return 0, 0, errors.New("no station locator configured")
+	}
+	from, err := ParseLocator(c.station.Locator)
+	if err != nil {
+		return 0, 0, err
+	}
+	to, err := ParseLocator(locator)
+	if err != nil {
+		return 0, 0, err
+	}
+	return from.DistanceTo(to), from.BearingTo(to), nil
+}
+
+// stationCallsign returns callsign, or the station's callsign if it is empty.
+func (c *Client) stationCallsign(callsign string) (string, error) {
+	if callsign != "" {
+		return callsign, nil
+	}
+	if c.station.Callsign == "" {
+		return "", errNoStation
+	}
+	return c.station.Callsign, nil
+}
@@ -0,0 +1,63 @@
+package pskreporter
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithStation(t *testing.T) {
+	c, err := New(WithStation("AG6K", "DM14cc", "EFHW"))
+	require.NoError(t, err)
+	require.Equal(t, StationProfile{Callsign: "AG6K", Locator: "DM14cc", Antenna: "EFHW"}, c.Station())
+
+	c, err = c.With(WithCacheDir(t.TempDir()))
+	require.NoError(t, err)
+	require.Equal(t, "AG6K", c.Station().Callsign)
+
+	_, err = New(WithStation("AG6K", "ZZ99", ""))
+	require.Error(t, err)
+
+	t.Run("distance", func(t *testing.T) {
+		d, b, err := c.DistanceTo("EM55db")
+		require.NoError(t, err)
+
+		from, _ := ParseLocator("DM14cc")
+		to, _ := ParseLocator("EM55db")
+		require.Equal(t, from.DistanceTo(to), d)
+		require.Equal(t, from.BearingTo(to), b)
+
+		_, _, err = c.DistanceTo("nope")
+		require.Error(t, err)
+
+		c, err := New()
+		require.NoError(t, err)
+		_, _, err = c.DistanceTo("EM55db")
+		require.Error(t, err)
+	})
+
+	t.Run("who hears me", func(t *testing.T) {
+		var sender string
+		svr := newHeardServer(t, func(req *http.Request) {
+			sender = req.URL.Query().Get("senderCallsign")
+		})
+
+		c, err := New(WithBaseURL(svr.URL), WithStation("AG6K", "", ""))
+		require.NoError(t, err)
+		spots, err := c.WhoHearsMe(context.Background(), "", 0)
+		require.NoError(t, err)
+		require.Equal(t, "AG6K", sender)
+		require.NotEmpty(t, spots)
+
+		heard, _, err := c.IsBeingHeard(context.Background(), "")
+		require.NoError(t, err)
+		require.True(t, heard)
+
+		c, err = New(WithBaseURL(svr.URL))
+		require.NoError(t, err)
+		_, err = c.WhoHearsMe(context.Background(), "", 0)
+		require.Equal(t, errNoStation, err)
+	})
+}
@@ -0,0 +1,71 @@
+package pskreporter
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// Store persists spots and answers queries over them. Time ranges are half
+// open, [start, end), and a zero start or end leaves that side of the range
+// unbounded. Results are ordered by time.
+type Store interface {
+	// Put adds spots to the store.
+	Put(ctx context.Context, spots []Spot) error
+
+	// QueryByCallsign returns the spots sent or received by callsign.
+	QueryByCallsign(ctx context.Context, callsign string, start, end time.Time) ([]Spot, error)
+
+	// QueryByTimeRange returns every spot in the time range.
+	QueryByTimeRange(ctx context.Context, start, end time.Time) ([]Spot, error)
+
+	// QueryByBand returns the spots on band.
+	QueryByBand(ctx context.Context, band Band, start, end time.Time) ([]Spot, error)
+
+	// Aggregate summarizes the spots in the time range per band.
+	Aggregate(ctx context.Context, start, end time.Time) ([]BandAggregate, error)
+
+	// Snapshot writes the contents of the store to w in a portable format
+	// that any Store can restore.
+	Snapshot(ctx context.Context, w io.Writer) error
+
+	// Restore replaces the contents of the store with a snapshot read from r.
+	Restore(ctx context.Context, r io.Reader) error
+
+	// Close releases any resources held by the store.
+	Close() error
+}
+
+// BandAggregate summarizes the spots on one band.
+type BandAggregate struct {
+	Band        Band
+	Spots       int
+	Senders     int // unique senders
+	Receivers   int // unique receivers
+	BestSNR     int
+	HasSNR      bool
+	MaxDistance float64 // kilometers
+}
+
+// storeSink adapts a Store to the Sink interface.
+type storeSink struct {
+	store Store
+}
+
+// NewStoreSink creates a Sink that puts every spot written to it into store.
+// Closing the sink closes the store.
+func NewStoreSink(store Store) Sink {
+	return &storeSink{store: store}
+}
+
+func (s *storeSink) Write(ctx context.Context, spots []Spot) error {
+	return s.store.Put(ctx, spots)
+}
+
+func (s *storeSink) Flush() error {
+	return nil
+}
+
+func (s *storeSink) Close() error {
+	return s.store.Close()
+}
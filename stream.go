@@ -0,0 +1,170 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// QueryToSink executes a search query and writes the reception reports to sink
+// as Spots while the response is still being read, returning how many spots
+// were written. Reports are decoded one at a time and written in batches of
+// DefaultBatchSize, so memory use stays bounded no matter how large the
+// response is, which suits backfills and large report limits. The client's
+// Pipeline, if any, is applied to each spot. Unlike QuerySpots, DXCC details
+// missing from a report are not filled in from the active callsign lists, and
+// the cache is bypassed. The sink is flushed once the response has been read,
+// but not closed.
+func (c *Client) QueryToSink(ctx context.Context, sink Sink, opts ...QueryOption) (int, error) {
+	return c.queryToSink(ctx, sink, nil, opts)
+}
+
+// streamInfo is what a streamed query learns about the response besides its
+// reports.
+type streamInfo struct {
+	limit              int    // the query's report limit, or zero
+	reports            int    // reception reports in the response, kept or not
+	lastSequenceNumber string // from the lastSequenceNumber element
+}
+
+// queryToSink is QueryToSink, filling in info if it isn't nil.
+func (c *Client) queryToSink(ctx context.Context, sink Sink, info *streamInfo, opts []QueryOption) (int, error) {
+	u, o, err := c.queryURL(opts)
+	if err != nil {
+		return 0, err
+	}
+	if info != nil {
+		info.limit, _ = strconv.Atoi(o.vals.Get("rptlimit"))
+	}
+	if o.vals.Has("callback") {
+		o.vals.Del("callback")
+		u.RawQuery = o.vals.Encode()
+	}
+
+	var meta QueryMeta
+	body, err := c.get(ctx, u, &meta)
+	if err != nil {
+		return 0, &QueryError{Host: meta.errorHost(u), Params: sanitizeParams(o.vals), Err: err}
+	}
+	defer body.Close()
+
+	var sinkErr error
+	n, err := streamSpots(body, c.pipeline, o.decodeLimits(), DefaultBatchSize, info, func(spots []Spot) error {
+		sinkErr = sink.Write(ctx, spots)
+		return sinkErr
+	})
+	if err != nil {
+		if sinkErr != nil {
+			return n, err
+		}
+		return n, &QueryError{Host: meta.errorHost(u), Params: sanitizeParams(o.vals), Err: err}
+	}
+	return n, sink.Flush()
+}
+
+// streamSpots decodes the reception reports in r, skipping those lim doesn't
+// keep and stopping once it has as many as lim allows, enriches each with p if
+// it isn't nil, and passes them to write in batches of up to size spots. It
+// returns how many spots were written, and records what else it reads of the
+// response in info if it isn't nil.
+func streamSpots(r io.Reader, p *Pipeline, lim decodeLimits, size int, info *streamInfo, write func([]Spot) error) (int, error) {
+	d := xml.NewDecoder(r)
+	batch := make([]Spot, 0, size)
+	n := 0
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := write(batch); err != nil {
+			return err
+		}
+		n += len(batch)
+		// Sinks may hold on to the slice they are given, so start a new one.
+		batch = make([]Spot, 0, size)
+		return nil
+	}
+
+	root := false
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return n, streamDecodeError(d, err)
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !root {
+			if se.Name.Local != "receptionReports" {
+				return n, &DecodeError{Offset: d.InputOffset(), Err: fmt.Errorf("expected element type <receptionReports> but have <%s>", se.Name.Local)}
+			}
+			root = true
+			continue
+		}
+		if se.Name.Local == "lastSequenceNumber" && info != nil {
+			for _, a := range se.Attr {
+				if a.Name.Local == "value" {
+					info.lastSequenceNumber = a.Value
+				}
+			}
+		}
+		if se.Name.Local != "receptionReport" {
+			if err := d.Skip(); err != nil {
+				return n, streamDecodeError(d, err)
+			}
+			continue
+		}
+
+		var rr ReceptionReport
+		if err := d.DecodeElement(&rr, &se); err != nil {
+			return n, streamDecodeError(d, err)
+		}
+		if info != nil {
+			info.reports++
+		}
+		if lim.keep != nil && !lim.keep(rr) {
+			continue
+		}
+		s, err := NewSpot(rr)
+		if err != nil {
+			return n, err
+		}
+		if p != nil {
+			if err := p.Enrich(&s); err != nil {
+				return n, err
+			}
+		}
+
+		batch = append(batch, s)
+		if len(batch) == size {
+			if err := flush(); err != nil {
+				return n, err
+			}
+		}
+		if lim.full(n + len(batch)) {
+			break
+		}
+	}
+	if !root {
+		return n, &DecodeError{Offset: d.InputOffset(), Err: io.ErrUnexpectedEOF}
+	}
+	return n, flush()
+}
+
+// streamDecodeError wraps err in a DecodeError if it is a problem with the
+// document rather than with reading it.
+func streamDecodeError(d *xml.Decoder, err error) error {
+	var se *xml.SyntaxError
+	var ue xml.UnmarshalError
+	if errors.As(err, &se) || errors.As(err, &ue) {
+		return &DecodeError{Offset: d.InputOffset(), Err: err}
+	}
+	return err
+}
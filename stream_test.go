@@ -0,0 +1,109 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryToSink(t *testing.T) {
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		http.ServeFile(w, req, "testdata/output.xml")
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+
+	resp, err := c.Query(WithCallsign("AG6K"))
+	require.NoError(t, err)
+	want, err := resp.Spots()
+	require.NoError(t, err)
+
+	t.Run("normal", func(t *testing.T) {
+		sink := &recordingSink{}
+		n, err := c.QueryToSink(context.Background(), sink, WithCallsign("AG6K"))
+		require.NoError(t, err)
+		require.Equal(t, len(want), n)
+		require.Equal(t, want, sink.spots)
+		require.Equal(t, 1, sink.flushes)
+		require.False(t, sink.closed)
+	})
+
+	t.Run("pipeline", func(t *testing.T) {
+		c, err := c.With(WithPipeline(NewPipeline(BandEnricher())))
+		require.NoError(t, err)
+
+		sink := &recordingSink{}
+		_, err = c.QueryToSink(context.Background(), sink, WithCallsign("AG6K"))
+		require.NoError(t, err)
+		require.Equal(t, Band20m, sink.spots[0].Band)
+	})
+
+	t.Run("modes", func(t *testing.T) {
+		sink := &recordingSink{}
+		n, err := c.QueryToSink(context.Background(), sink, WithModes("ft8", "FT4"))
+		require.NoError(t, err)
+		require.Equal(t, len(want), n)
+
+		sink = &recordingSink{}
+		n, err = c.QueryToSink(context.Background(), sink, WithModes("CW", "SSB"))
+		require.NoError(t, err)
+		require.Zero(t, n)
+		require.Empty(t, sink.spots)
+	})
+
+	t.Run("sink error", func(t *testing.T) {
+		errBoom := errors.New("boom")
+		n, err := c.QueryToSink(context.Background(), &recordingSink{err: errBoom}, WithCallsign("AG6K"))
+		require.Equal(t, errBoom, err)
+		require.Zero(t, n)
+	})
+
+	t.Run("http error", func(t *testing.T) {
+		svr404 := httptest.NewServer(http.NotFoundHandler())
+		defer svr404.Close()
+		c, err := c.With(WithBaseURL(svr404.URL))
+		require.NoError(t, err)
+
+		_, err = c.QueryToSink(context.Background(), &recordingSink{}, WithCallsign("AG6K"))
+		var qe *QueryError
+		require.True(t, errors.As(err, &qe))
+	})
+}
+
+func TestStreamSpots(t *testing.T) {
+	fh, err := os.Open("testdata/output.xml")
+	require.NoError(t, err)
+	defer fh.Close()
+
+	var batches []int
+	n, err := streamSpots(fh, nil, decodeLimits{}, 100, nil, func(spots []Spot) error {
+		batches = append(batches, len(spots))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int{100, 100, 100, 40}, batches)
+	require.Equal(t, 340, n)
+
+	t.Run("wrong root", func(t *testing.T) {
+		_, err := streamSpots(strings.NewReader(`<foo/>`), nil, decodeLimits{}, 10, nil, func([]Spot) error { return nil })
+		require.Error(t, err)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		_, err := streamSpots(strings.NewReader(``), nil, decodeLimits{}, 10, nil, func([]Spot) error { return nil })
+		require.Error(t, err)
+	})
+
+	t.Run("bad report", func(t *testing.T) {
+		_, err := streamSpots(strings.NewReader(`<receptionReports><receptionReport frequency="x"/></receptionReports>`), nil, decodeLimits{}, 10, nil, func([]Spot) error { return nil })
+		require.Error(t, err)
+	})
+}
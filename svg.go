@@ -0,0 +1,320 @@
+package pskreporter
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+//go:embed coastline.txt
+var coastlineData string
+
+// coastline is the simplified coastline drawn under the paths, as closed
+// rings of points.
+var coastline = parseCoastline(coastlineData)
+
+// defaultBandColors are the colors used for reception paths on each band.
+var defaultBandColors = map[Band]string{
+	Band2200m: "#555555",
+	Band630m:  "#777777",
+	Band160m:  "#7cfc00",
+	Band80m:   "#e550e5",
+	Band60m:   "#00008b",
+	Band40m:   "#5959ff",
+	Band30m:   "#62d962",
+	Band20m:   "#f2c40c",
+	Band17m:   "#f2f261",
+	Band15m:   "#cca166",
+	Band12m:   "#b22222",
+	Band10m:   "#ff69b4",
+	Band6m:    "#ff0000",
+	Band4m:    "#cc0044",
+	Band2m:    "#ff1493",
+	Band125cm: "#ccff00",
+	Band70cm:  "#999900",
+	Band23cm:  "#5ae6a8",
+}
+
+const unknownBandColor = "#888888"
+
+type svgOptions struct {
+	width      int
+	bandColors map[Band]string
+	aeqd       *AzimuthalEquidistant
+}
+
+// SVGOption is used to customize the output of RenderSVG.
+type SVGOption func(*svgOptions) error
+
+// WithSVGWidth sets the width of the image in pixels. Equirectangular maps are
+// half as tall as they are wide.
+func WithSVGWidth(px int) SVGOption {
+	return func(o *svgOptions) error {
+		if px <= 0 {
+			return errors.New("svg width must be positive")
+		}
+		o.width = px
+		return nil
+	}
+}
+
+// WithAzimuthalProjection draws the map as an azimuthal-equidistant projection
+// centered on the given position instead of an equirectangular one. The image
+// is square, with range rings every 5000 km and bearing lines every 30
+// degrees.
+func WithAzimuthalProjection(center LatLon) SVGOption {
+	return func(o *svgOptions) error {
+		o.aeqd = NewAzimuthalEquidistant(center)
+		return nil
+	}
+}
+
+// WithBandColor overrides the color used to draw paths on the given band. The
+// color can be any valid SVG color.
+func WithBandColor(b Band, color string) SVGOption {
+	return func(o *svgOptions) error {
+		o.bandColors[b] = color
+		return nil
+	}
+}
+
+// RenderSVG draws the great-circle path of each spot onto a world map with a
+// simplified coastline and writes the result to w as an SVG image. Paths are
+// colored by band. Spots missing a valid locator on either end are skipped. By
+// default the map is an equirectangular projection; see
+// WithAzimuthalProjection.
+func RenderSVG(w io.Writer, spots []Spot, opts ...SVGOption) error {
+	o := svgOptions{
+		width:      1024,
+		bandColors: make(map[Band]string, len(defaultBandColors)),
+	}
+	for b, c := range defaultBandColors {
+		o.bandColors[b] = c
+	}
+
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return err
+		}
+	}
+
+	width := float64(o.width)
+	height := width / 2
+	project := func(p LatLon) (x, y float64) {
+		return (p.Lon + 180) / 360 * width, (90 - p.Lat) / 180 * height
+	}
+	paths := greatCircle
+
+	if o.aeqd != nil {
+		height = width
+		scale := width / 2 / o.aeqd.Radius()
+		project = func(p LatLon) (x, y float64) {
+			pt := o.aeqd.Project(p)
+			return width/2 + pt.X*scale, height/2 - pt.Y*scale
+		}
+		// The projection is continuous across the antimeridian.
+		paths = func(a, b LatLon) [][]LatLon {
+			return [][]LatLon{greatCirclePoints(a, b)}
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", o.width, int(height), o.width, int(height))
+	fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="#0b1a2b"/>`+"\n")
+	writeCoastline(bw, project, o.aeqd == nil)
+
+	fmt.Fprintf(bw, `<g stroke="#2c4763" stroke-width="1" fill="none">`+"\n")
+	if o.aeqd != nil {
+		cx, cy, r := width/2, height/2, width/2
+		for km := 5000.0; km < o.aeqd.Radius(); km += 5000 {
+			fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="%.1f"/>`+"\n", cx, cy, km/o.aeqd.Radius()*r)
+		}
+		fmt.Fprintf(bw, `<circle cx="%.1f" cy="%.1f" r="%.1f"/>`+"\n", cx, cy, r)
+		for bearing := 0.0; bearing < 360; bearing += 30 {
+			theta := radians(bearing)
+			fmt.Fprintf(bw, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f"/>`+"\n", cx, cy, cx+r*math.Sin(theta), cy-r*math.Cos(theta))
+		}
+	} else {
+		// Graticule every 30 degrees.
+		for lon := -150.0; lon < 180; lon += 30 {
+			x, _ := project(LatLon{Lon: lon})
+			fmt.Fprintf(bw, `<line x1="%.1f" y1="0" x2="%.1f" y2="%.1f"/>`+"\n", x, x, height)
+		}
+		for lat := -60.0; lat < 90; lat += 30 {
+			_, y := project(LatLon{Lat: lat})
+			fmt.Fprintf(bw, `<line x1="0" y1="%.1f" x2="%.1f" y2="%.1f"/>`+"\n", y, width, y)
+		}
+	}
+	fmt.Fprintf(bw, "</g>\n")
+
+	fmt.Fprintf(bw, `<g fill="none" stroke-width="1.5" stroke-opacity="0.8">`+"\n")
+	for _, s := range spots {
+		from, to, ok := spotEnds(&s)
+		if !ok {
+			continue
+		}
+
+		b := s.Band
+		if b == "" {
+			b, _ = BandForFrequency(s.Frequency)
+		}
+		color, ok := o.bandColors[b]
+		if !ok {
+			color = unknownBandColor
+		}
+
+		fmt.Fprintf(bw, `<path stroke="%s" d="`, escapeXML(color))
+		for i, segment := range paths(from, to) {
+			for j, p := range segment {
+				x, y := project(p)
+				cmd := "L"
+				if j == 0 {
+					cmd = "M"
+					if i > 0 {
+						bw.WriteString(" ")
+					}
+				}
+				fmt.Fprintf(bw, "%s%.1f %.1f", cmd, x, y)
+			}
+		}
+		fmt.Fprintf(bw, `"><title>%s → %s %s</title></path>`+"\n", escapeXML(s.SenderCallsign), escapeXML(s.ReceiverCallsign), escapeXML(string(b)))
+	}
+	fmt.Fprintf(bw, "</g>\n</svg>\n")
+
+	return bw.Flush()
+}
+
+// writeCoastline writes the coastline as a single path. When filled its rings
+// are closed, with seas inside land left unfilled. Otherwise edges along the
+// antimeridian or a pole, which only exist to close rings on an
+// equirectangular map, are left out.
+func writeCoastline(bw *bufio.Writer, project func(LatLon) (x, y float64), fill bool) {
+	if fill {
+		fmt.Fprintf(bw, `<path fill="#1a3048" fill-rule="evenodd" stroke="#2c4763" stroke-width="0.5" d="`)
+	} else {
+		fmt.Fprintf(bw, `<path fill="none" stroke="#3d5f80" stroke-width="0.5" d="`)
+	}
+	for _, ring := range coastline {
+		if fill {
+			for i, p := range ring {
+				x, y := project(p)
+				cmd := "L"
+				if i == 0 {
+					cmd = "M"
+				}
+				fmt.Fprintf(bw, "%s%.1f %.1f", cmd, x, y)
+			}
+			bw.WriteString("Z")
+			continue
+		}
+
+		drawing := false
+		for i, a := range ring {
+			b := ring[(i+1)%len(ring)]
+			if onMapEdge(a) && onMapEdge(b) {
+				drawing = false
+				continue
+			}
+			if !drawing {
+				x, y := project(a)
+				fmt.Fprintf(bw, "M%.1f %.1f", x, y)
+				drawing = true
+			}
+			x, y := project(b)
+			fmt.Fprintf(bw, "L%.1f %.1f", x, y)
+		}
+	}
+	fmt.Fprintf(bw, `"/>`+"\n")
+}
+
+// onMapEdge reports whether p is on the edge of an equirectangular map.
+func onMapEdge(p LatLon) bool {
+	return math.Abs(p.Lon) == 180 || math.Abs(p.Lat) == 90
+}
+
+// parseCoastline parses rings of "lon,lat" points in degrees, one ring per
+// line, skipping blank lines and comments starting with '#'.
+func parseCoastline(s string) [][]LatLon {
+	var rings [][]LatLon
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var ring []LatLon
+		for _, f := range strings.Fields(line) {
+			lon, lat, _ := strings.Cut(f, ",")
+			x, err1 := strconv.ParseFloat(lon, 64)
+			y, err2 := strconv.ParseFloat(lat, 64)
+			if err1 != nil || err2 != nil {
+				panic(fmt.Sprintf("pskreporter: bad coastline point %q", f))
+			}
+			ring = append(ring, LatLon{Lat: y, Lon: x})
+		}
+		rings = append(rings, ring)
+	}
+	return rings
+}
+
+// greatCircleSteps is the number of segments used to approximate a path.
+const greatCircleSteps = 64
+
+// greatCircle returns points along the great-circle path between a and b,
+// split into separate segments wherever the path crosses the antimeridian.
+func greatCircle(a, b LatLon) [][]LatLon {
+	var segments [][]LatLon
+	var current []LatLon
+	for _, p := range greatCirclePoints(a, b) {
+		if n := len(current); n > 0 && math.Abs(p.Lon-current[n-1].Lon) > 180 {
+			segments = append(segments, current)
+			current = nil
+		}
+		current = append(current, p)
+	}
+	return append(segments, current)
+}
+
+// greatCirclePoints returns evenly spaced points along the great-circle path
+// between a and b, including both ends.
+func greatCirclePoints(a, b LatLon) []LatLon {
+	ax, ay, az := toVector(a)
+	bx, by, bz := toVector(b)
+
+	omega := math.Acos(math.Max(-1, math.Min(1, ax*bx+ay*by+az*bz)))
+	if omega == 0 {
+		return []LatLon{a, b}
+	}
+
+	points := make([]LatLon, 0, greatCircleSteps+1)
+	for i := 0; i <= greatCircleSteps; i++ {
+		f := float64(i) / greatCircleSteps
+		wa := math.Sin((1-f)*omega) / math.Sin(omega)
+		wb := math.Sin(f*omega) / math.Sin(omega)
+		points = append(points, fromVector(wa*ax+wb*bx, wa*ay+wb*by, wa*az+wb*bz))
+	}
+	return points
+}
+
+func toVector(p LatLon) (x, y, z float64) {
+	lat, lon := radians(p.Lat), radians(p.Lon)
+	return math.Cos(lat) * math.Cos(lon), math.Cos(lat) * math.Sin(lon), math.Sin(lat)
+}
+
+func fromVector(x, y, z float64) LatLon {
+	return LatLon{
+		Lat: degrees(math.Atan2(z, math.Sqrt(x*x+y*y))),
+		Lon: degrees(math.Atan2(y, x)),
+	}
+}
+
+func escapeXML(s string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
@@ -0,0 +1,113 @@
+package pskreporter
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderSVG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderSVG(&buf, testSpots, WithSVGWidth(800), WithBandColor(Band20m, "red")))
+
+	var doc struct {
+		Width  int `xml:"width,attr"`
+		Height int `xml:"height,attr"`
+		Land   []struct {
+			D string `xml:"d,attr"`
+		} `xml:"path"`
+		Groups []struct {
+			Paths []struct {
+				Stroke string `xml:"stroke,attr"`
+				D      string `xml:"d,attr"`
+				Title  string `xml:"title"`
+			} `xml:"path"`
+		} `xml:"g"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, 800, doc.Width)
+	require.Equal(t, 400, doc.Height)
+	require.Len(t, doc.Groups, 2)
+	require.Len(t, doc.Land, 1)
+	require.Equal(t, len(coastline), strings.Count(doc.Land[0].D, "Z"))
+
+	// The second spot has no locators, so only one path is drawn.
+	paths := doc.Groups[1].Paths
+	require.Len(t, paths, 1)
+	require.Equal(t, "red", paths[0].Stroke)
+	require.Equal(t, "AG6K → W5CJ 20m", paths[0].Title)
+	require.Regexp(t, `^M\d`, paths[0].D)
+
+	t.Run("bad option", func(t *testing.T) {
+		require.Error(t, RenderSVG(&buf, testSpots, WithSVGWidth(0)))
+	})
+}
+
+func TestRenderSVGAzimuthal(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, RenderSVG(&buf, testSpots, WithSVGWidth(600), WithAzimuthalProjection(LatLon{Lat: 34, Lon: -118})))
+
+	var doc struct {
+		Width   int `xml:"width,attr"`
+		Height  int `xml:"height,attr"`
+		Circles []struct {
+			R float64 `xml:"r,attr"`
+		} `xml:"g>circle"`
+		Land []struct {
+			D string `xml:"d,attr"`
+		} `xml:"path"`
+		Paths []struct {
+			D string `xml:"d,attr"`
+		} `xml:"g>path"`
+	}
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &doc))
+	require.Equal(t, 600, doc.Width)
+	require.Equal(t, 600, doc.Height)
+	require.Len(t, doc.Circles, 5)
+	require.Equal(t, 300.0, doc.Circles[4].R)
+	require.Len(t, doc.Paths, 1)
+	require.NotContains(t, doc.Paths[0].D, " M")
+
+	// The coastline isn't closed along the antimeridian.
+	require.Len(t, doc.Land, 1)
+	require.NotContains(t, doc.Land[0].D, "Z")
+	require.Greater(t, strings.Count(doc.Land[0].D, "M"), len(coastline))
+}
+
+func TestCoastline(t *testing.T) {
+	require.Greater(t, len(coastline), 10)
+	for _, ring := range coastline {
+		require.Greater(t, len(ring), 2)
+		for _, p := range ring {
+			require.True(t, p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180, p)
+		}
+	}
+}
+
+func TestGreatCircle(t *testing.T) {
+	t.Run("simple", func(t *testing.T) {
+		segments := greatCircle(LatLon{}, LatLon{Lon: 90})
+		require.Len(t, segments, 1)
+		require.Len(t, segments[0], greatCircleSteps+1)
+		require.InDelta(t, 45, segments[0][greatCircleSteps/2].Lon, 0.000001)
+		require.InDelta(t, 0, segments[0][greatCircleSteps/2].Lat, 0.000001)
+	})
+
+	t.Run("crosses antimeridian", func(t *testing.T) {
+		tokyo := LatLon{Lat: 35.7, Lon: 139.7}
+		sanFrancisco := LatLon{Lat: 37.8, Lon: -122.4}
+
+		segments := greatCircle(tokyo, sanFrancisco)
+		require.Len(t, segments, 2)
+		require.Greater(t, segments[0][len(segments[0])-1].Lon, 170.0)
+		require.Less(t, segments[1][0].Lon, -170.0)
+		require.Greater(t, segments[0][len(segments[0])-1].Lat, 45.0)
+	})
+
+	t.Run("same point", func(t *testing.T) {
+		require.Equal(t, [][]LatLon{{{}, {}}}, greatCircle(LatLon{}, LatLon{}))
+	})
+}
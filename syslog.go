@@ -0,0 +1,156 @@
+//go:build !windows && !plan9
+
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/syslog"
+	"time"
+)
+
+// SyslogOption is used to customize a SyslogSink.
+type SyslogOption func(*syslogOptions) error
+
+type syslogOptions struct {
+	network       string
+	raddr         string
+	facility      syslog.Priority
+	tag           string
+	spotSeverity  syslog.Priority
+	alertSeverity syslog.Priority
+}
+
+// WithSyslogServer sends messages to a remote syslog server instead of the
+// local daemon. network is "udp" or "tcp".
+func WithSyslogServer(network, raddr string) SyslogOption {
+	return func(o *syslogOptions) error {
+		o.network = network
+		o.raddr = raddr
+		return nil
+	}
+}
+
+// WithSyslogFacility sets the facility, such as syslog.LOG_LOCAL0.
+func WithSyslogFacility(facility syslog.Priority) SyslogOption {
+	return func(o *syslogOptions) error {
+		if facility&^0xf8 != 0 {
+			return errors.New("invalid syslog facility")
+		}
+		o.facility = facility
+		return nil
+	}
+}
+
+// WithSyslogTag sets the tag messages are logged with.
+func WithSyslogTag(tag string) SyslogOption {
+	return func(o *syslogOptions) error {
+		o.tag = tag
+		return nil
+	}
+}
+
+// WithSyslogSeverities sets the severities spots and notifications are
+// logged at.
+func WithSyslogSeverities(spot, alert syslog.Priority) SyslogOption {
+	return func(o *syslogOptions) error {
+		if spot&^0x07 != 0 || alert&^0x07 != 0 {
+			return errors.New("invalid syslog severity")
+		}
+		o.spotSeverity = spot
+		o.alertSeverity = alert
+		return nil
+	}
+}
+
+// SyslogSink writes spots and notifications to syslog. It is both a Sink and
+// a Notifier. By default it logs to the local daemon with the user facility,
+// spots at info and notifications at notice.
+type SyslogSink struct {
+	w             *syslog.Writer
+	spotSeverity  syslog.Priority
+	alertSeverity syslog.Priority
+}
+
+// NewSyslogSink connects to syslog.
+func NewSyslogSink(opts ...SyslogOption) (*SyslogSink, error) {
+	o := syslogOptions{
+		facility:      syslog.LOG_USER,
+		tag:           "pskreporter",
+		spotSeverity:  syslog.LOG_INFO,
+		alertSeverity: syslog.LOG_NOTICE,
+	}
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return nil, err
+		}
+	}
+
+	w, err := syslog.Dial(o.network, o.raddr, o.facility|o.spotSeverity, o.tag)
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogSink{w: w, spotSeverity: o.spotSeverity, alertSeverity: o.alertSeverity}, nil
+}
+
+// Write logs one line per spot.
+func (s *SyslogSink) Write(ctx context.Context, spots []Spot) error {
+	for _, spot := range spots {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Log the absolute time rather than the relative age from String.
+		line := spot
+		line.Time = time.Time{}
+		msg := line.String()
+		if !spot.Time.IsZero() {
+			msg += " " + spot.Time.UTC().Format(time.RFC3339)
+		}
+		if err := s.log(s.spotSeverity, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Notify logs the notification's title and message.
+func (s *SyslogSink) Notify(ctx context.Context, n Notification) error {
+	msg := n.Message
+	if n.Title != "" {
+		msg = n.Title + ": " + n.Message
+	}
+	return s.log(s.alertSeverity, msg)
+}
+
+// Flush is a no-op; every message is sent as it is received.
+func (s *SyslogSink) Flush() error {
+	return nil
+}
+
+// Close closes the connection to syslog.
+func (s *SyslogSink) Close() error {
+	return s.w.Close()
+}
+
+func (s *SyslogSink) log(severity syslog.Priority, msg string) error {
+	switch severity {
+	case syslog.LOG_EMERG:
+		return s.w.Emerg(msg)
+	case syslog.LOG_ALERT:
+		return s.w.Alert(msg)
+	case syslog.LOG_CRIT:
+		return s.w.Crit(msg)
+	case syslog.LOG_ERR:
+		return s.w.Err(msg)
+	case syslog.LOG_WARNING:
+		return s.w.Warning(msg)
+	case syslog.LOG_NOTICE:
+		return s.w.Notice(msg)
+	case syslog.LOG_INFO:
+		return s.w.Info(msg)
+	case syslog.LOG_DEBUG:
+		return s.w.Debug(msg)
+	}
+	return fmt.Errorf("invalid syslog severity %d", severity)
+}
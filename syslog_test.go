@@ -0,0 +1,57 @@
+//go:build !windows && !plan9
+
+package pskreporter
+
+import (
+	"context"
+	"log/syslog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyslogSink(t *testing.T) {
+	l, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	s, err := NewSyslogSink(
+		WithSyslogServer("udp", l.LocalAddr().String()),
+		WithSyslogFacility(syslog.LOG_LOCAL3),
+		WithSyslogTag("psk"),
+		WithSyslogSeverities(syslog.LOG_DEBUG, syslog.LOG_WARNING),
+	)
+	require.NoError(t, err)
+	defer s.Close()
+
+	read := func() string {
+		buf := make([]byte, 2048)
+		require.NoError(t, l.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := l.ReadFrom(buf)
+		require.NoError(t, err)
+		return string(buf[:n])
+	}
+
+	ctx := context.Background()
+	require.NoError(t, s.Write(ctx, testSpots[:1]))
+	require.NoError(t, s.Flush())
+	msg := read()
+	// LOG_LOCAL3|LOG_DEBUG = 19<<3 | 7
+	require.Regexp(t, `^<159>`, msg)
+	require.Contains(t, msg, " psk[")
+	require.Contains(t, msg, "W5CJ EM55db92 ← AG6K DM14cc24 14.075MHz FT8 -19dB 2020-09-03T20:03:00Z")
+
+	require.NoError(t, s.Notify(ctx, Notification{Title: "Beacon silent", Message: "W6ABC not heard for 30m"}))
+	msg = read()
+	require.Regexp(t, `^<156>`, msg)
+	require.Contains(t, msg, "Beacon silent: W6ABC not heard for 30m")
+}
+
+func TestSyslogOptions(t *testing.T) {
+	_, err := NewSyslogSink(WithSyslogFacility(syslog.LOG_INFO))
+	require.Error(t, err)
+	_, err = NewSyslogSink(WithSyslogSeverities(syslog.LOG_LOCAL0, syslog.LOG_INFO))
+	require.Error(t, err)
+}
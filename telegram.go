@@ -0,0 +1,70 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+const (
+	// DefaultTelegramAPIURL is the base URL of the Telegram bot API.
+	DefaultTelegramAPIURL = "https://api.telegram.org"
+
+	// telegramMaxText is the longest message Telegram accepts.
+	telegramMaxText = 4096
+)
+
+// TelegramNotifier sends notifications to a Telegram chat through a bot.
+type TelegramNotifier struct {
+	token  string
+	chatID string
+	opts   notifierOptions
+}
+
+// NewTelegramNotifier creates a notifier that sends messages as the bot with
+// the given token to chatID, which is a numeric chat ID or an @channelname.
+func NewTelegramNotifier(token, chatID string, opts ...NotifierOption) (*TelegramNotifier, error) {
+	if token == "" || chatID == "" {
+		return nil, errors.New("telegram bot token and chat id are required")
+	}
+	o, err := newNotifierOptions(DefaultTelegramAPIURL, DefaultNotificationTemplate, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &TelegramNotifier{token: token, chatID: chatID, opts: o}, nil
+}
+
+// Notify sends the rendered notification, truncated to Telegram's limit.
+func (t *TelegramNotifier) Notify(ctx context.Context, n Notification) error {
+	text, err := t.opts.render(n)
+	if err != nil {
+		return err
+	}
+
+	msg := struct {
+		ChatID string `json:"chat_id"`
+		Text   string `json:"text"`
+	}{t.chatID, truncate(text, telegramMaxText)}
+
+	u := fmt.Sprintf("%s/bot%s/sendMessage", t.opts.apiURL, t.token)
+	b, err := t.opts.postJSON(ctx, u, msg)
+	if err != nil {
+		// The URL holds the bot token, so keep it out of the error.
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			return fmt.Errorf("sending telegram message: %w", ue.Err)
+		}
+
+		// The bot API explains failures in the body.
+		var resp struct {
+			Description string `json:"description"`
+		}
+		if json.Unmarshal(b, &resp) == nil && resp.Description != "" {
+			return fmt.Errorf("%w: %s", err, resp.Description)
+		}
+		return err
+	}
+	return nil
+}
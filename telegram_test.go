@@ -0,0 +1,48 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramNotifier(t *testing.T) {
+	var path string
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		got = nil
+		json.NewDecoder(r.Body).Decode(&got)
+		if got["chat_id"] == "bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"description":"Bad Request: chat not found"}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewTelegramNotifier("", "123")
+	require.Error(t, err)
+
+	ctx := context.Background()
+	n, err := NewTelegramNotifier("secret", "123", WithNotifierAPIURL(srv.URL+"/"))
+	require.NoError(t, err)
+	require.NoError(t, n.Notify(ctx, Notification{Title: "Beacon heard", Message: "W6ABC heard in ZL"}))
+	require.Equal(t, "/botsecret/sendMessage", path)
+	require.Equal(t, map[string]string{"chat_id": "123", "text": "Beacon heard\nW6ABC heard in ZL"}, got)
+
+	n, err = NewTelegramNotifier("secret", "bad", WithNotifierAPIURL(srv.URL))
+	require.NoError(t, err)
+	require.EqualError(t, n.Notify(ctx, Notification{Message: "hi"}), "unexpected http response 400: Bad Request: chat not found")
+
+	n, err = NewTelegramNotifier("secret", "123", WithNotifierAPIURL("http://127.0.0.1:0"))
+	require.NoError(t, err)
+	err = n.Notify(ctx, Notification{Message: "hi"})
+	require.Error(t, err)
+	require.NotContains(t, err.Error(), "secret")
+}
@@ -0,0 +1,89 @@
+package pskreporter
+
+import (
+	"bytes"
+	"errors"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrThrottled is matched by errors.Is when the server refused a query
+// because the client has been querying too often, whether it said so with a
+// RateLimitError status or a ThrottledError page.
+var ErrThrottled = errors.New("throttled by server")
+
+// ThrottledError is returned, wrapped in a QueryError, when the server
+// answers a query with its HTML page saying the client is querying too
+// often, instead of a response.
+type ThrottledError struct {
+	// Message is the text of the page.
+	Message string
+
+	// RetryAfter is how long the page suggests waiting before querying
+	// again, or zero if it didn't say.
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	if e.RetryAfter > 0 {
+		return "throttled by server, retry after " + e.RetryAfter.String() + ": " + e.Message
+	}
+	return "throttled by server: " + e.Message
+}
+
+// Is reports whether target is ErrThrottled.
+func (e *ThrottledError) Is(target error) bool {
+	return target == ErrThrottled
+}
+
+// throttlePageLimit is how much of an HTML page is read to check whether it
+// is a throttling page.
+const throttlePageLimit = 64 << 10
+
+var (
+	htmlTag       = regexp.MustCompile(`(?s)<[^>]*>`)
+	throttleWords = regexp.MustCompile(`(?i)too (many|often|frequent)|slow down|rate limit`)
+	throttleWait  = regexp.MustCompile(`(?i)(\d+)\s*(seconds?|secs?|minutes?|mins?|hours?)\b`)
+)
+
+// isHTML reports whether b starts like an HTML page rather than XML.
+func isHTML(b []byte) bool {
+	b = bytes.ToLower(bytes.TrimSpace(b))
+	return bytes.HasPrefix(b, []byte("<!doctype html")) || bytes.HasPrefix(b, []byte("<html"))
+}
+
+// throttled returns a ThrottledError if b is the server's page saying the
+// client is querying too often, or nil otherwise.
+func throttled(b []byte) error {
+	if !isHTML(b) {
+		return nil
+	}
+	// Drop the head so the title and styles don't end up in the message.
+	if i := bytes.Index(bytes.ToLower(b), []byte("<body")); i >= 0 {
+		b = b[i:]
+	}
+	text := strings.Join(strings.Fields(html.UnescapeString(htmlTag.ReplaceAllString(string(b), " "))), " ")
+	if !throttleWords.MatchString(text) {
+		return nil
+	}
+
+	e := &ThrottledError{Message: text}
+	if m := throttleWait.FindStringSubmatch(text); m != nil {
+		if n, err := strconv.Atoi(m[1]); err == nil {
+			unit := time.Second
+			switch strings.ToLower(m[2])[0] {
+			case 'm':
+				unit = time.Minute
+			case 'h':
+				unit = time.Hour
+			}
+			if n > 0 && int64(n) <= int64(maxLookback/unit) {
+				e.RetryAfter = time.Duration(n) * unit
+			}
+		}
+	}
+	return e
+}
@@ -0,0 +1,87 @@
+package pskreporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testThrottlePage = `<!DOCTYPE html>
+<html><head><title>PSK Reporter</title><style>p { color: red; }</style></head>
+<body>
+<h1>Slow down</h1>
+<p>Your IP address has made too many queries &amp; is being throttled.
+Please wait 300 seconds before querying again.</p>
+</body></html>`
+
+func TestThrottled(t *testing.T) {
+	tests := []struct {
+		desc  string
+		page  string
+		ok    bool
+		wait  time.Duration
+		inMsg string
+	}{
+		{"page", testThrottlePage, true, 5 * time.Minute, "too many queries & is being throttled"},
+		{"minutes", "<html><body>You are querying too often. Try again in 2 minutes.</body></html>", true, 2 * time.Minute, "querying too often"},
+		{"no wait", "<HTML><BODY>Rate limit exceeded</BODY></HTML>", true, 0, "Rate limit exceeded"},
+		{"other html", "<html><body>Service unavailable</body></html>", false, 0, ""},
+		{"xml", "<receptionReports>too many</receptionReports>", false, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			err := throttled([]byte(tt.page))
+			if !tt.ok {
+				require.NoError(t, err)
+				return
+			}
+			var te *ThrottledError
+			require.True(t, errors.As(err, &te))
+			require.Equal(t, tt.wait, te.RetryAfter)
+			require.Contains(t, te.Message, tt.inMsg)
+			require.NotContains(t, te.Message, "color")
+			require.True(t, errors.Is(err, ErrThrottled))
+		})
+	}
+}
+
+func TestQueryThrottled(t *testing.T) {
+	page := testThrottlePage
+	svr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte(page))
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL), WithCacheDir(t.TempDir()))
+	require.NoError(t, err)
+
+	_, err = c.Query()
+	require.True(t, errors.Is(err, ErrThrottled))
+	var te *ThrottledError
+	require.True(t, errors.As(err, &te))
+	require.Equal(t, 5*time.Minute, te.RetryAfter)
+	var qe *QueryError
+	require.True(t, errors.As(err, &qe))
+
+	_, err = c.QueryToSink(context.Background(), &recordingSink{})
+	require.True(t, errors.Is(err, ErrThrottled))
+
+	// Throttling pages aren't cached.
+	page = "<receptionReports/>"
+	_, err = c.Query()
+	require.NoError(t, err)
+
+	// Other HTML still fails to decode.
+	page = "<html><body>Oops</body></html>"
+	_, err = c.Query(WithCallsign("AG6K"))
+	var de *DecodeError
+	require.True(t, errors.As(err, &de))
+	require.False(t, errors.Is(err, ErrThrottled))
+
+	require.True(t, errors.Is(&RateLimitError{StatusCode: 429}, ErrThrottled))
+}
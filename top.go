@@ -0,0 +1,86 @@
+package pskreporter
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TopCount is a value and the number of spots it appeared in.
+type TopCount struct {
+	Key   string
+	Count int
+}
+
+// TopNStore is implemented by stores that can compute top-N aggregations
+// natively. Each method counts the receiving side of the spots heard in the
+// last window and returns the n most frequent values, most frequent first.
+type TopNStore interface {
+	TopReceivers(ctx context.Context, window time.Duration, n int) ([]TopCount, error)
+	TopDXCC(ctx context.Context, window time.Duration, n int) ([]TopCount, error)
+	TopGrids(ctx context.Context, window time.Duration, n int) ([]TopCount, error)
+}
+
+// TopReceivers returns the n receivers that reported the most spots in s over
+// the last window.
+func TopReceivers(ctx context.Context, s Store, window time.Duration, n int) ([]TopCount, error) {
+	if t, ok := s.(TopNStore); ok {
+		return t.TopReceivers(ctx, window, n)
+	}
+	return topN(ctx, s, window, n, func(st Station) (string, bool) {
+		return strings.ToUpper(st.Callsign), st.Callsign != ""
+	})
+}
+
+// TopDXCC returns the n DXCC entities with the most receiver spots in s over
+// the last window.
+func TopDXCC(ctx context.Context, s Store, window time.Duration, n int) ([]TopCount, error) {
+	if t, ok := s.(TopNStore); ok {
+		return t.TopDXCC(ctx, window, n)
+	}
+	return topN(ctx, s, window, n, DXCCMultiplier)
+}
+
+// TopGrids returns the n 4 character grid squares with the most receiver spots
+// in s over the last window.
+func TopGrids(ctx context.Context, s Store, window time.Duration, n int) ([]TopCount, error) {
+	if t, ok := s.(TopNStore); ok {
+		return t.TopGrids(ctx, window, n)
+	}
+	return topN(ctx, s, window, n, GridMultiplier)
+}
+
+func topN(ctx context.Context, s Store, window time.Duration, n int, key Multiplier) ([]TopCount, error) {
+	spots, err := s.QueryByTimeRange(ctx, timeNow().Add(-window), time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	return countTop(spots, n, key), nil
+}
+
+// countTop counts the receiving stations of spots by key and returns the n
+// most frequent, breaking ties by key. A non-positive n returns them all.
+func countTop(spots []Spot, n int, key Multiplier) []TopCount {
+	counts := make(map[string]int)
+	for _, s := range spots {
+		if k, ok := key(s.Receiver()); ok {
+			counts[k]++
+		}
+	}
+
+	top := make([]TopCount, 0, len(counts))
+	for k, c := range counts {
+		top = append(top, TopCount{Key: k, Count: c})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count != top[j].Count {
+			return top[i].Count > top[j].Count
+		}
+		return top[i].Key < top[j].Key
+	})
+	if n > 0 && len(top) > n {
+		top = top[:n]
+	}
+	return top
+}
@@ -0,0 +1,57 @@
+package pskreporter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTopNStore struct {
+	Store
+}
+
+func (fakeTopNStore) TopReceivers(context.Context, time.Duration, int) ([]TopCount, error) {
+	return []TopCount{{Key: "native", Count: 1}}, nil
+}
+
+func (fakeTopNStore) TopDXCC(context.Context, time.Duration, int) ([]TopCount, error) {
+	return nil, nil
+}
+
+func (fakeTopNStore) TopGrids(context.Context, time.Duration, int) ([]TopCount, error) {
+	return nil, nil
+}
+
+func TestTopN(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1599163380, 0).UTC()
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	s := NewMemoryStore()
+	require.NoError(t, s.Put(ctx, []Spot{
+		{ReceiverCallsign: "OLD1", ReceiverDXCC: "Japan", ReceiverLocator: "PM95", Time: now.Add(-2 * time.Hour)},
+		{ReceiverCallsign: "W5CJ", ReceiverDXCC: "United States", ReceiverLocator: "EM55db", Time: now.Add(-time.Minute)},
+		{ReceiverCallsign: "w5cj", ReceiverDXCC: "United States", ReceiverLocator: "EM55db", Time: now.Add(-time.Minute)},
+		{ReceiverCallsign: "N7HPX", ReceiverDXCC: "United States", ReceiverLocator: "DN13", Time: now.Add(-time.Minute)},
+		{ReceiverCallsign: "JA1XYZ", ReceiverDXCC: "Japan", Time: now.Add(-time.Minute)},
+	}))
+
+	top, err := TopReceivers(ctx, s, time.Hour, 2)
+	require.NoError(t, err)
+	require.Equal(t, []TopCount{{"W5CJ", 2}, {"JA1XYZ", 1}}, top)
+
+	top, err = TopDXCC(ctx, s, time.Hour, 0)
+	require.NoError(t, err)
+	require.Equal(t, []TopCount{{"United States", 3}, {"Japan", 1}}, top)
+
+	top, err = TopGrids(ctx, s, time.Hour, 10)
+	require.NoError(t, err)
+	require.Equal(t, []TopCount{{"EM55", 2}, {"DN13", 1}}, top)
+
+	top, err = TopReceivers(ctx, fakeTopNStore{s}, time.Hour, 2)
+	require.NoError(t, err)
+	require.Equal(t, []TopCount{{"native", 1}}, top)
+}
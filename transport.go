@@ -0,0 +1,63 @@
+package pskreporter
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+var errTransportWithHTTPClient = badOption(errors.New("WithProxy and WithTLSConfig can't be combined with WithHTTPClient"))
+
+// WithProxy sends queries through the proxy at rawURL, such as
+// "http://proxy.example.com:3128" or "socks5://localhost:1080", instead of the
+// proxy from the environment. It can't be combined with WithHTTPClient, as the
+// client builds its own HTTP client for it.
+func WithProxy(rawURL string) ClientOption {
+	return func(o *clientOptions) error {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return badOption(fmt.Errorf("parsing proxy url: %w", err))
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return badOption(fmt.Errorf("unsupported proxy scheme %q", u.Scheme))
+		}
+		if u.Host == "" {
+			return badOption(fmt.Errorf("proxy url %q has no host", rawURL))
+		}
+		o.proxy = u
+		o.buildTransport = true
+		return nil
+	}
+}
+
+// WithTLSConfig sets the TLS configuration used to connect to the server, for
+// example to trust a private CA or pin certificates. The configuration is
+// cloned, so later changes to cfg have no effect. It can't be combined with
+// WithHTTPClient, as the client builds its own HTTP client for it.
+func WithTLSConfig(cfg *tls.Config) ClientOption {
+	return func(o *clientOptions) error {
+		if cfg == nil {
+			return badOption(errors.New("tls config must not be nil"))
+		}
+		o.tlsConfig = cfg.Clone()
+		o.buildTransport = true
+		return nil
+	}
+}
+
+// newHTTPClient returns an HTTP client configured like http.DefaultClient but
+// with the given proxy and TLS configuration, either of which may be nil.
+func newHTTPClient(proxy *url.URL, cfg *tls.Config) *http.Client {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if proxy != nil {
+		t.Proxy = http.ProxyURL(proxy)
+	}
+	if cfg != nil {
+		t.TLSClientConfig = cfg
+	}
+	return &http.Client{Transport: t}
+}
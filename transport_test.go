@@ -0,0 +1,80 @@
+package pskreporter
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithProxy(t *testing.T) {
+	var host string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		host = req.URL.Host
+		w.Write([]byte("<receptionReports/>"))
+	}))
+	defer proxy.Close()
+
+	c, err := New(WithBaseURL("http://pskreporter.invalid/query"), WithProxy(proxy.URL))
+	require.NoError(t, err)
+	_, err = c.Query()
+	require.NoError(t, err)
+	require.Equal(t, "pskreporter.invalid", host)
+
+	// Derived clients keep the proxy.
+	d, err := c.With(WithDefaultAppContact("me@example.com"))
+	require.NoError(t, err)
+	require.Same(t, c.doer, d.doer)
+
+	for _, bad := range []string{"ftp://proxy", "http://", "://"} {
+		_, err := New(WithProxy(bad))
+		require.Error(t, err, bad)
+	}
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	svr := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Write([]byte("<receptionReports/>"))
+	}))
+	defer svr.Close()
+
+	c, err := New(WithBaseURL(svr.URL))
+	require.NoError(t, err)
+	_, err = c.Query()
+	require.Error(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(svr.Certificate())
+	cfg := &tls.Config{RootCAs: pool}
+	c, err = New(WithBaseURL(svr.URL), WithTLSConfig(cfg))
+	require.NoError(t, err)
+	cfg.RootCAs = nil
+	_, err = c.Query()
+	require.NoError(t, err)
+
+	_, err = New(WithTLSConfig(nil))
+	require.Error(t, err)
+}
+
+func TestTransportWithHTTPClient(t *testing.T) {
+	_, err := New(WithHTTPClient(&http.Client{}), WithProxy("http://proxy:3128"))
+	require.Equal(t, errTransportWithHTTPClient, err)
+
+	_, err = New(WithTLSConfig(&tls.Config{}), WithHTTPClient(&http.Client{}))
+	require.Equal(t, errTransportWithHTTPClient, err)
+
+	c, err := New(WithProxy("http://proxy:3128"))
+	require.NoError(t, err)
+	doer := &http.Client{}
+	d, err := c.With(WithHTTPClient(doer))
+	require.NoError(t, err)
+	require.Same(t, doer, d.doer)
+	require.Nil(t, d.proxy)
+
+	// A client derived from one with its own HTTP client can't add a proxy.
+	_, err = d.With(WithProxy("http://proxy:3128"))
+	require.Equal(t, errTransportWithHTTPClient, err)
+}
@@ -0,0 +1,235 @@
+package pskreporter
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Transmission is one transmission from the user's station.
+type Transmission struct {
+	Time      time.Time
+	Frequency int64         // Hz, zero if unknown
+	Mode      string        // e.g. "FT8"
+	Power     float64       // watts, zero if unknown
+	Duration  time.Duration // zero to use the usual length for the mode
+}
+
+// txDurations are the transmission periods of common modes, used for
+// transmissions without a Duration. Other modes are taken to transmit for a
+// minute.
+var txDurations = map[string]time.Duration{
+	"FT8":  15 * time.Second,
+	"FT4":  7500 * time.Millisecond,
+	"JT9":  time.Minute,
+	"JT65": time.Minute,
+	"Q65":  time.Minute,
+	"WSPR": wsprSlot,
+}
+
+// Correlation limits.
+const (
+	// txTimeSlack is how long before a transmission a spot may be timestamped
+	// and still be matched to it, to allow for clock differences.
+	txTimeSlack = 2 * time.Second
+
+	// txFrequencySlack is how far in Hz a spot's frequency may be from the
+	// transmission's, about the width of a receiver passband.
+	txFrequencySlack = 3000
+)
+
+// duration returns how long t lasted.
+func (t Transmission) duration() time.Duration {
+	if t.Duration > 0 {
+		return t.Duration
+	}
+	if d, ok := txDurations[strings.ToUpper(t.Mode)]; ok {
+		return d
+	}
+	return time.Minute
+}
+
+// TransmissionReport describes how far one transmission reached.
+type TransmissionReport struct {
+	Transmission Transmission
+	Spots        []Spot // ordered by time
+	Receivers    int    // unique receivers
+	MaxDistance  float64
+	BestSNR      int
+	HasSNR       bool
+
+	// DistancePerWatt is MaxDistance divided by the transmission's power, in
+	// kilometers per watt, or zero if the power isn't known.
+	DistancePerWatt float64
+}
+
+// CorrelateTransmissions matches spots of the user's signal against the
+// transmissions that produced them, returning a report per transmission
+// ordered by time. A spot matches a transmission if it was heard while the
+// transmission was on the air, allowing a couple of seconds for clock
+// differences, and within 3kHz of its frequency when both are known. Spots
+// matching more than one transmission go to the one that started closest to
+// them, and spots matching none are ignored. Spots are expected to all be of
+// the user's own callsign.
+func CorrelateTransmissions(txs []Transmission, spots []Spot) []TransmissionReport {
+	reports := make([]TransmissionReport, len(txs))
+	for i, tx := range txs {
+		reports[i].Transmission = tx
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].Transmission.Time.Before(reports[j].Transmission.Time)
+	})
+
+	// Transmissions can't be longer than this, so only those that started
+	// within it before a spot need to be considered.
+	var longest time.Duration
+	for _, r := range reports {
+		if d := r.Transmission.duration(); d > longest {
+			longest = d
+		}
+	}
+
+	for _, s := range spots {
+		// The first transmission that could have been on the air.
+		first := sort.Search(len(reports), func(i int) bool {
+			return !reports[i].Transmission.Time.Before(s.Time.Add(-longest))
+		})
+
+		best := -1
+		var bestGap time.Duration
+		for i := first; i < len(reports); i++ {
+			tx := reports[i].Transmission
+			if tx.Time.After(s.Time.Add(txTimeSlack)) {
+				break
+			}
+			if !s.Time.Before(tx.Time.Add(tx.duration())) {
+				continue
+			}
+			if tx.Frequency != 0 && s.Frequency != 0 && abs64(tx.Frequency-s.Frequency) > txFrequencySlack {
+				continue
+			}
+			gap := s.Time.Sub(tx.Time)
+			if gap < 0 {
+				gap = -gap
+			}
+			if best < 0 || gap < bestGap {
+				best, bestGap = i, gap
+			}
+		}
+		if best >= 0 {
+			reports[best].Spots = append(reports[best].Spots, s)
+		}
+	}
+
+	for i := range reports {
+		reports[i].summarize()
+	}
+	return reports
+}
+
+func (r *TransmissionReport) summarize() {
+	sort.SliceStable(r.Spots, func(i, j int) bool {
+		return r.Spots[i].Time.Before(r.Spots[j].Time)
+	})
+
+	receivers := make(map[string]bool)
+	for i := range r.Spots {
+		s := &r.Spots[i]
+		receivers[strings.ToUpper(s.ReceiverCallsign)] = true
+		if s.HasSNR && (!r.HasSNR || s.SNR > r.BestSNR) {
+			r.BestSNR, r.HasSNR = s.SNR, true
+		}
+		if d, ok := spotDistance(s); ok && d > r.MaxDistance {
+			r.MaxDistance = d
+		}
+	}
+	r.Receivers = len(receivers)
+	if p := r.Transmission.Power; p > 0 {
+		r.DistancePerWatt = r.MaxDistance / p
+	}
+}
+
+func abs64(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// ReadADIFTransmissions reads transmissions from the QSO records of an ADIF
+// file, using QSO_DATE and TIME_ON for the time, FREQ, MODE, and TX_PWR for
+// the power in watts.
+func ReadADIFTransmissions(r io.Reader) ([]Transmission, error) {
+	var txs []Transmission
+	err := readADIF(r, func(fields map[string]string) error {
+		tx := Transmission{Mode: fields["MODE"]}
+		var err error
+		if tx.Frequency, err = adifFrequency(fields); err != nil {
+			return err
+		}
+		if tx.Time, err = adifTime(fields); err != nil {
+			return err
+		}
+		if v := strings.TrimSpace(fields["TX_PWR"]); v != "" {
+			if tx.Power, err = strconv.ParseFloat(v, 64); err != nil || tx.Power < 0 || math.IsInf(tx.Power, 0) {
+				return fmt.Errorf("parsing power %q: invalid value", v)
+			}
+		}
+		txs = append(txs, tx)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
+
+// ReadWSJTXAll reads the transmissions from a WSJT-X ALL.TXT file, skipping
+// received messages. Lines look like:
+//
+//	230903_200300    14.074 Tx FT8      0  0.0 1500 CQ AG6K DM14
+//
+// The frequency of a transmission is the dial frequency plus the audio offset.
+// ALL.TXT doesn't record the power, so it is left unknown.
+func ReadWSJTXAll(r io.Reader) ([]Transmission, error) {
+	var txs []Transmission
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		f := strings.Fields(sc.Text())
+		if len(f) < 7 || f[2] != "Tx" {
+			continue
+		}
+
+		t, err := time.Parse("060102_150405", f[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: parsing time %q: %w", line, f[0], err)
+		}
+		mhz, err := strconv.ParseFloat(f[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: parsing frequency %q: %w", line, f[1], err)
+		}
+		dial, ok := mhzToHz(mhz)
+		if !ok {
+			return nil, fmt.Errorf("line %d: parsing frequency %q: %w", line, f[1], strconv.ErrRange)
+		}
+		offset, err := strconv.ParseInt(f[6], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: parsing audio frequency %q: %w", line, f[6], err)
+		}
+
+		txs = append(txs, Transmission{
+			Time:      t,
+			Frequency: dial + offset,
+			Mode:      f[3],
+		})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return txs, nil
+}
@@ -0,0 +1,104 @@
+package pskreporter
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCorrelateTransmissions(t *testing.T) {
+	base := time.Date(2023, 9, 3, 20, 3, 0, 0, time.UTC)
+	txs := []Transmission{
+		{Time: base.Add(30 * time.Second), Frequency: 14075500, Mode: "FT8", Power: 5},
+		{Time: base, Frequency: 14075500, Mode: "FT8", Power: 50},
+		{Time: base.Add(time.Hour), Frequency: 7075500, Mode: "FT8"},
+	}
+	spot := func(offset time.Duration, receiver string, freq int64, snr int, km float64) Spot {
+		return Spot{
+			SenderCallsign:   "AG6K",
+			ReceiverCallsign: receiver,
+			Frequency:        freq,
+			SNR:              snr,
+			HasSNR:           true,
+			Distance:         km,
+			Time:             base.Add(offset),
+		}
+	}
+	spots := []Spot{
+		spot(0, "W5CJ", 14075510, -10, 1500),
+		spot(time.Second, "N7HPX", 14075490, -3, 900),
+		spot(0, "W5CJ", 14075510, -12, 1500), // heard twice
+		spot(-time.Second, "K1ABC", 14075500, -20, 4000),
+		spot(30*time.Second, "VK2XYZ", 14075500, -22, 12000),
+		spot(30*time.Second, "JA1XYZ", 21075500, -22, 8000), // wrong frequency
+		spot(10*time.Minute, "W5CJ", 14075500, -1, 1500),    // not transmitting
+	}
+
+	reports := CorrelateTransmissions(txs, spots)
+	require.Len(t, reports, 3)
+
+	first := reports[0]
+	require.Equal(t, base, first.Transmission.Time)
+	require.Len(t, first.Spots, 4)
+	require.Equal(t, "K1ABC", first.Spots[0].ReceiverCallsign)
+	require.Equal(t, 3, first.Receivers)
+	require.Equal(t, 4000.0, first.MaxDistance)
+	require.Equal(t, -3, first.BestSNR)
+	require.True(t, first.HasSNR)
+	require.Equal(t, 80.0, first.DistancePerWatt)
+
+	second := reports[1]
+	require.Len(t, second.Spots, 1)
+	require.Equal(t, 1, second.Receivers)
+	require.Equal(t, 2400.0, second.DistancePerWatt)
+
+	third := reports[2]
+	require.Empty(t, third.Spots)
+	require.False(t, third.HasSNR)
+	require.Zero(t, third.DistancePerWatt)
+}
+
+func TestReadADIFTransmissions(t *testing.T) {
+	adif := `<ADIF_VER:5>3.1.0 <EOH>
+<CALL:4>W5CJ <MODE:3>FT8 <QSO_DATE:8>20230903 <TIME_ON:6>200300 <FREQ:9>14.075500 <TX_PWR:2>50 <EOR>
+<CALL:5>N7HPX <MODE:4>WSPR <QSO_DATE:8>20230903 <TIME_ON:4>2004 <EOR>
+`
+	txs, err := ReadADIFTransmissions(strings.NewReader(adif))
+	require.NoError(t, err)
+	require.Equal(t, []Transmission{
+		{Time: time.Date(2023, 9, 3, 20, 3, 0, 0, time.UTC), Frequency: 14075500, Mode: "FT8", Power: 50},
+		{Time: time.Date(2023, 9, 3, 20, 4, 0, 0, time.UTC), Mode: "WSPR"},
+	}, txs)
+	require.Equal(t, 15*time.Second, txs[0].duration())
+	require.Equal(t, 2*time.Minute, txs[1].duration())
+
+	_, err = ReadADIFTransmissions(strings.NewReader("<TX_PWR:4>lots<EOR>"))
+	require.Error(t, err)
+	_, err = ReadADIFTransmissions(strings.NewReader("<TX_PWR:2>-5<EOR>"))
+	require.Error(t, err)
+}
+
+func TestReadWSJTXAll(t *testing.T) {
+	all := `230903_200245    14.074 Rx FT8    -12  0.1 1234 CQ W5CJ EM55
+230903_200300    14.074 Tx FT8      0  0.0 1500 CQ AG6K DM14
+230903_200315    14.074 Rx FT8     -8  0.2 1500 AG6K W5CJ -12
+230903_200330    14.074 Tx FT8      0  0.0 1500 W5CJ AG6K R-08
+`
+	txs, err := ReadWSJTXAll(strings.NewReader(all))
+	require.NoError(t, err)
+	require.Equal(t, []Transmission{
+		{Time: time.Date(2023, 9, 3, 20, 3, 0, 0, time.UTC), Frequency: 14075500, Mode: "FT8"},
+		{Time: time.Date(2023, 9, 3, 20, 3, 30, 0, time.UTC), Frequency: 14075500, Mode: "FT8"},
+	}, txs)
+
+	for _, bad := range []string{
+		"230903-200300 14.074 Tx FT8 0 0.0 1500 CQ",
+		"230903_200300 abc Tx FT8 0 0.0 1500 CQ",
+		"230903_200300 14.074 Tx FT8 0 0.0 abc CQ",
+	} {
+		_, err := ReadWSJTXAll(strings.NewReader(bad))
+		require.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,53 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+)
+
+// DefaultUDPSinkAddr broadcasts to every host on the local network.
+const DefaultUDPSinkAddr = "255.255.255.255:2240"
+
+// UDPSink sends each spot as a JSON datagram, in the same form as a line
+// written by JSONLSink.
+type UDPSink struct {
+	conn net.Conn
+}
+
+// NewUDPSink creates a sink that sends datagrams to addr, a host:port that
+// may be a broadcast or multicast address.
+func NewUDPSink(addr string) (*UDPSink, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &UDPSink{conn: conn}, nil
+}
+
+// Write sends one datagram per spot.
+func (s *UDPSink) Write(ctx context.Context, spots []Spot) error {
+	for _, spot := range spots {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b, err := json.Marshal(spot)
+		if err != nil {
+			return err
+		}
+		if _, err := s.conn.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush is a no-op; every spot is sent as it is received.
+func (s *UDPSink) Flush() error {
+	return nil
+}
+
+// Close closes the socket.
+func (s *UDPSink) Close() error {
+	return s.conn.Close()
+}
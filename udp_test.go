@@ -0,0 +1,37 @@
+package pskreporter
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUDPSink(t *testing.T) {
+	l, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+
+	s, err := NewUDPSink(l.LocalAddr().String())
+	require.NoError(t, err)
+	require.NoError(t, s.Write(context.Background(), testSpots))
+	require.NoError(t, s.Flush())
+	require.NoError(t, s.Close())
+
+	buf := make([]byte, 64*1024)
+	for _, want := range testSpots {
+		require.NoError(t, l.SetReadDeadline(time.Now().Add(time.Second)))
+		n, _, err := l.ReadFrom(buf)
+		require.NoError(t, err)
+
+		var got Spot
+		require.NoError(t, json.Unmarshal(buf[:n], &got))
+		require.Equal(t, want, got)
+	}
+
+	_, err = NewUDPSink("not an address")
+	require.Error(t, err)
+}
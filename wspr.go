@@ -0,0 +1,195 @@
+package pskreporter
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// IsWSPR returns true if the spot was made using the WSPR mode.
+func IsWSPR(s Spot) bool {
+	return strings.EqualFold(s.Mode, "WSPR")
+}
+
+// DistanceBucket summarizes the reception distances seen on a band during one
+// interval.
+type DistanceBucket struct {
+	Start       time.Time
+	Count       int
+	Percentiles []float64 // kilometers, in the order they were requested
+}
+
+// DistancePercentiles groups spots by band and into fixed intervals of time,
+// and computes the requested distance percentiles (0-100) for each group. The
+// buckets for each band are ordered by time. Spots whose distance or band
+// cannot be determined are skipped.
+func DistancePercentiles(spots []Spot, interval time.Duration, percentiles ...float64) map[Band][]DistanceBucket {
+	type key struct {
+		band  Band
+		start int64
+	}
+	distances := make(map[key][]float64)
+	for i := range spots {
+		b, ok := spotBand(&spots[i])
+		if !ok {
+			continue
+		}
+		d, ok := spotDistance(&spots[i])
+		if !ok {
+			continue
+		}
+		k := key{band: b, start: spots[i].Time.Truncate(interval).Unix()}
+		distances[k] = append(distances[k], d)
+	}
+
+	result := make(map[Band][]DistanceBucket)
+	for k, d := range distances {
+		sort.Float64s(d)
+		bucket := DistanceBucket{
+			Start:       time.Unix(k.start, 0).UTC(),
+			Count:       len(d),
+			Percentiles: make([]float64, 0, len(percentiles)),
+		}
+		for _, p := range percentiles {
+			bucket.Percentiles = append(bucket.Percentiles, percentile(d, p))
+		}
+		result[k.band] = append(result[k.band], bucket)
+	}
+
+	for _, buckets := range result {
+		sort.Slice(buckets, func(i, j int) bool {
+			return buckets[i].Start.Before(buckets[j].Start)
+		})
+	}
+	return result
+}
+
+// wsprSlot is the length of a WSPR transmission period, and wsprCycle the
+// length of the band hopping cycle used by WSJT-X.
+const (
+	wsprSlot  = 2 * time.Minute
+	wsprCycle = 20 * time.Minute
+)
+
+// HoppingSchedule describes the bands a station transmits on in each of the
+// ten 2 minute slots of a 20 minute WSPR band hopping cycle.
+type HoppingSchedule struct {
+	Callsign string
+	Slots    [10]Band // an empty Band means the slot was not observed
+	Bands    []Band   // the distinct bands in the schedule, ordered by frequency
+}
+
+// minHoppingConsistency is the share of a slot's spots that must be on the same
+// band for the slot to be considered part of a schedule.
+const minHoppingConsistency = 0.8
+
+// DetectBandHopping looks for WSPR senders that consistently transmit on a
+// different band in different slots of the band hopping cycle, and returns
+// their schedules ordered by callsign. Senders heard on a single band, or
+// whose slots don't consistently map to one band, are not returned.
+func DetectBandHopping(spots []Spot) []HoppingSchedule {
+	slotsPerCycle := int(wsprCycle / wsprSlot)
+	counts := make(map[string][]map[Band]int)
+	for i := range spots {
+		s := &spots[i]
+		if !IsWSPR(*s) || s.Time.IsZero() {
+			continue
+		}
+		b, ok := spotBand(s)
+		if !ok {
+			continue
+		}
+
+		call := strings.ToUpper(s.SenderCallsign)
+		if counts[call] == nil {
+			counts[call] = make([]map[Band]int, slotsPerCycle)
+		}
+		slot := int(s.Time.Sub(s.Time.Truncate(wsprCycle)) / wsprSlot)
+		if counts[call][slot] == nil {
+			counts[call][slot] = make(map[Band]int)
+		}
+		counts[call][slot][b]++
+	}
+
+	var schedules []HoppingSchedule
+	for call, slots := range counts {
+		schedule := HoppingSchedule{Callsign: call}
+		consistent := true
+		seen := make(map[Band]bool)
+		for i, bands := range slots {
+			if bands == nil {
+				continue
+			}
+			best, total := Band(""), 0
+			for b, n := range bands {
+				total += n
+				if n > bands[best] || (n == bands[best] && b < best) {
+					best = b
+				}
+			}
+			if float64(bands[best])/float64(total) < minHoppingConsistency {
+				consistent = false
+				break
+			}
+			schedule.Slots[i] = best
+			seen[best] = true
+		}
+
+		if !consistent || len(seen) < 2 {
+			continue
+		}
+
+		for _, b := range Bands() {
+			if seen[b] {
+				schedule.Bands = append(schedule.Bands, b)
+			}
+		}
+		schedules = append(schedules, schedule)
+	}
+
+	sort.Slice(schedules, func(i, j int) bool {
+		return schedules[i].Callsign < schedules[j].Callsign
+	})
+	return schedules
+}
+
+// spotBand returns the band of the spot, falling back to its frequency when it
+// has not been enriched.
+func spotBand(s *Spot) (Band, bool) {
+	if s.Band != "" {
+		return s.Band, true
+	}
+	return BandForFrequency(s.Frequency)
+}
+
+// spotDistance returns the distance of the spot, falling back to computing it
+// from the locators when it has not been enriched.
+func spotDistance(s *Spot) (float64, bool) {
+	if s.Distance > 0 {
+		return s.Distance, true
+	}
+	from, to, ok := spotEnds(s)
+	if !ok {
+		return 0, false
+	}
+	return from.DistanceTo(to), true
+}
+
+// percentile returns the p-th percentile (0-100) of the sorted values using
+// linear interpolation between the closest ranks.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
@@ -0,0 +1,77 @@
+package pskreporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsWSPR(t *testing.T) {
+	require.True(t, IsWSPR(Spot{Mode: "WSPR"}))
+	require.True(t, IsWSPR(Spot{Mode: "wspr"}))
+	require.False(t, IsWSPR(Spot{Mode: "FT8"}))
+}
+
+func TestDistancePercentiles(t *testing.T) {
+	base := time.Date(2020, 9, 3, 20, 0, 0, 0, time.UTC)
+	spots := []Spot{
+		{Band: Band20m, Distance: 100, Time: base},
+		{Band: Band20m, Distance: 200, Time: base.Add(10 * time.Minute)},
+		{Band: Band20m, Distance: 300, Time: base.Add(20 * time.Minute)},
+		{Band: Band20m, Distance: 400, Time: base.Add(30 * time.Minute)},
+		{Band: Band20m, Distance: 500, Time: base.Add(40 * time.Minute)},
+		{Frequency: 7040100, Distance: 1000, Time: base.Add(90 * time.Minute)},
+		{Band: Band20m, Distance: 1500, Time: base.Add(90 * time.Minute)},
+		{Band: Band20m, Time: base},  // no distance
+		{Distance: 1500, Time: base}, // no band
+		{Frequency: 7040100, SenderLocator: "DM14", ReceiverLocator: "DM14", Time: base.Add(80 * time.Minute)},
+	}
+
+	result := DistancePercentiles(spots, time.Hour, 0, 50, 90, 100)
+	require.Len(t, result, 2)
+
+	require.Equal(t, []DistanceBucket{
+		{Start: base, Count: 5, Percentiles: []float64{100, 300, 460, 500}},
+		{Start: base.Add(time.Hour), Count: 1, Percentiles: []float64{1500, 1500, 1500, 1500}},
+	}, result[Band20m])
+
+	require.Equal(t, []DistanceBucket{
+		{Start: base.Add(time.Hour), Count: 2, Percentiles: []float64{0, 500, 900, 1000}},
+	}, result[Band40m])
+}
+
+func TestDetectBandHopping(t *testing.T) {
+	base := time.Date(2020, 9, 3, 20, 0, 0, 0, time.UTC)
+	var spots []Spot
+	for cycle := 0; cycle < 3; cycle++ {
+		start := base.Add(time.Duration(cycle) * wsprCycle)
+		spots = append(spots,
+			// K1ABC hops between 40m, 30m, and 20m.
+			Spot{SenderCallsign: "K1ABC", Mode: "WSPR", Frequency: 7040100, Time: start},
+			Spot{SenderCallsign: "K1ABC", Mode: "WSPR", Frequency: 10140200, Time: start.Add(2 * time.Minute)},
+			Spot{SenderCallsign: "K1ABC", Mode: "WSPR", Frequency: 14097100, Time: start.Add(4*time.Minute + 1*time.Second)},
+			// W1XYZ always transmits on 20m.
+			Spot{SenderCallsign: "W1XYZ", Mode: "WSPR", Frequency: 14097050, Time: start},
+			Spot{SenderCallsign: "W1XYZ", Mode: "WSPR", Frequency: 14097050, Time: start.Add(2 * time.Minute)},
+			// N1QRM uses a different band in the same slot every cycle.
+			Spot{SenderCallsign: "N1QRM", Mode: "WSPR", Frequency: []int64{7040100, 10140200, 14097100}[cycle], Time: start},
+			// Not WSPR.
+			Spot{SenderCallsign: "K2FT8", Mode: "FT8", Frequency: []int64{7074000, 14074000, 21074000}[cycle], Time: start},
+		)
+	}
+
+	schedules := DetectBandHopping(spots)
+	require.Len(t, schedules, 1)
+	require.Equal(t, "K1ABC", schedules[0].Callsign)
+	require.Equal(t, [10]Band{Band40m, Band30m, Band20m}, schedules[0].Slots)
+	require.Equal(t, []Band{Band40m, Band30m, Band20m}, schedules[0].Bands)
+}
+
+func TestPercentile(t *testing.T) {
+	require.Equal(t, 0.0, percentile(nil, 50))
+	require.Equal(t, 5.0, percentile([]float64{5}, 90))
+	require.Equal(t, 1.5, percentile([]float64{1, 2}, 50))
+	require.Equal(t, 1.0, percentile([]float64{1, 2}, -10))
+	require.Equal(t, 2.0, percentile([]float64{1, 2}, 110))
+}